@@ -13,12 +13,17 @@ import (
 )
 
 var _ DbClient = (*AbstractDbClient)(nil)
+var _ RetryableErrorClassifier = (*AbstractDbClient)(nil)
+var _ ParamLimitProvider = (*AbstractDbClient)(nil)
 
 // AbstractDbClient 是一个 DbClient 的抽象实现。
 type AbstractDbClient struct {
 	config *DbClientConfig      // 存储数据库连接配置。
 	Db     *sqlen.DbEnhance     // 内部依赖的连接池。
 	Exer   sqlen.EnhancedDbExer // 获取方法实际使用的执行对象。
+
+	stmtCache *stmtCache       // 预编译语句缓存，未开启时为 nil 。
+	lifecycle *clientLifecycle // 关闭状态及进行中的事务，由连接池及其上开启的事务共享。
 }
 
 // NewAbstractDbClient 用于获取一个 internalDbClient 对象。
@@ -40,12 +45,21 @@ func NewAbstractDbClient(config *DbClientConfig) (*AbstractDbClient, error) {
 		}
 	}
 
+	config.applyPoolSettings(config.Db)
+
 	dbEnhance := sqlen.NewDbEnhance(config.Db, config.getScanTypeFunc, config.unifyDataTypeFunc)
-	return &AbstractDbClient{
-		config: config,
-		Db:     dbEnhance,
-		Exer:   dbEnhance,
-	}, nil
+	client := &AbstractDbClient{
+		config:    config,
+		Db:        dbEnhance,
+		Exer:      dbEnhance,
+		lifecycle: &clientLifecycle{},
+	}
+
+	if config.stmtCacheCapacity > 0 {
+		client.stmtCache = newStmtCache(config.Db, config.stmtCacheCapacity)
+	}
+
+	return client, nil
 }
 
 // 用于获取数据库连接池对象。
@@ -79,20 +93,41 @@ func (client *AbstractDbClient) Dsn() string {
 	return client.config.Dsn
 }
 
+// IsRetryableError 用于判断给定的错误是否可以通过重试事务解决（如死锁、序列化失败等），判断逻辑由驱动提供。
+func (client *AbstractDbClient) IsRetryableError(err error) bool {
+	return err != nil && client.config.isRetryableErrorFunc(err)
+}
+
+// MaxParamCount 返回单条语句允许使用的最大参数个数，返回 0 表示未知。
+func (client *AbstractDbClient) MaxParamCount() int {
+	return client.config.maxParamCount
+}
+
+// MaxInsertRows 返回单条 INSERT ... VALUES 语句允许插入的最大行数，返回 0 表示不限制。
+func (client *AbstractDbClient) MaxInsertRows() int {
+	return client.config.maxInsertRows
+}
+
 // getExecTimeoutContext 用于获取数据库语句默认超时 context。
 func (client *AbstractDbClient) getExecTimeoutContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), client.GetExecTimeout())
 }
 
-var dbConv = conv.Conv{
-	Conf: conv.Config{
-		FieldMatcherCreator: &conv.SimpleMatcherCreator{
-			Conf: conv.SimpleMatcherConfig{
-				Tag:            "conv",
-				CamelSnakeCase: true,
+// dbConv 是包内部（如结构体参数转 map ）使用的 conv.Conv 实例，配置同 newDbConv 。
+var dbConv = newDbConv()
+
+// newDbConv 用于创建一个默认配置的 conv.Conv 实例，提供 mysql 的 snake_case 名称的字段到 Go 的 CamelCase 字段的匹配。
+func newDbConv() conv.Conv {
+	return conv.Conv{
+		Conf: conv.Config{
+			FieldMatcherCreator: &conv.SimpleMatcherCreator{
+				Conf: conv.SimpleMatcherConfig{
+					Tag:            "conv",
+					CamelSnakeCase: true,
+				},
 			},
 		},
-	},
+	}
 }
 
 // preHandleArgs 用于对 SQL 语句和参数进行预处理。
@@ -112,6 +147,73 @@ func preHandleArgs(args ...any) ([]any, error) {
 	return []any{mergedArgs}, nil
 }
 
+// positionalArgs 是按位置绑定的参数，其中的值依次对应语句中的 @p1...@pn 。
+// 与变长的索引参数不同，其中的值总是原样作为参数值使用，不会被合并为命名参数，切片也不会被展开。
+type positionalArgs []any
+
+// bindPositionalArgs 用于按位置绑定参数。
+// 先以各参数的序号代替参数值交给 bindFunc 绑定，得到驱动的占位符及参数顺序，再将序号换回原始的参数值。
+func bindPositionalArgs(bindFunc BindSqlArgsFunc, sqlText string, args positionalArgs) (string, []any, error) {
+	indexes := make([]any, len(args))
+	for i := range args {
+		indexes[i] = i
+	}
+
+	template, err := newBoundTemplate(bindFunc, sqlText, len(args), indexes...)
+	if err != nil {
+		return "", nil, err
+	}
+	return template.fixedSql, template.bind(args), nil
+}
+
+// boundTemplate 是以参数序号代替参数值绑定得到的结果，参数值变化时，将序号换成参数值即可得到绑定结果，无需重新解析语句。
+// 只适用于没有需要展开的切片参数的情况。
+type boundTemplate struct {
+	fixedSql string
+	indexes  []int    // 绑定后的各参数对应的参数序号。
+	names    []string // 绑定后的参数为 sql.NamedArg 时（如 SqlServer ），为其名称，否则为空字符串。
+}
+
+// newBoundTemplate 用于以参数序号代替参数值调用 bindFunc 得到 boundTemplate 。
+// standIns 是交给 bindFunc 的参数，其中的参数值均为 [0, count) 范围内的参数序号。
+func newBoundTemplate(bindFunc BindSqlArgsFunc, sqlText string, count int, standIns ...any) (*boundTemplate, error) {
+	fixedSql, fixedArgs, err := bindFunc(sqlText, standIns...)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &boundTemplate{
+		fixedSql: fixedSql,
+		indexes:  make([]int, len(fixedArgs)),
+		names:    make([]string, len(fixedArgs)),
+	}
+	for i, arg := range fixedArgs {
+		if namedArg, ok := arg.(sql.NamedArg); ok {
+			template.names[i], arg = namedArg.Name, namedArg.Value
+		}
+
+		index, ok := arg.(int)
+		if !ok || index < 0 || index >= count {
+			return nil, fmt.Errorf("%w: unexpected bound parameter %v\nsql = %s", ErrParseParamFailed, arg, sqlText)
+		}
+		template.indexes[i] = index
+	}
+	return template, nil
+}
+
+// bind 用于将参数序号换成 values 中对应的参数值，得到绑定后的参数。
+func (template *boundTemplate) bind(values []any) []any {
+	fixedArgs := make([]any, len(template.indexes))
+	for i, index := range template.indexes {
+		if template.names[i] != "" {
+			fixedArgs[i] = sql.Named(template.names[i], values[index])
+		} else {
+			fixedArgs[i] = values[index]
+		}
+	}
+	return fixedArgs
+}
+
 // 检查参数类型是否需要合并处理。
 func needMergeArgs(args ...any) bool {
 	hasMap := false // 判断是否有 map 参数。
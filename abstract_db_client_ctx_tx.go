@@ -0,0 +1,36 @@
+package sqlmer
+
+import (
+	"context"
+
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+// txContextKey 是 context 中事务的 key 。
+type txContextKey struct{}
+
+// WithTx 返回一个携带事务 tx 的 context 。
+// 开启了 WithContextTx 的 DbClient ，在使用该 context 调用 *Context 版本的方法时，语句会在 tx 上执行，
+// 调用 CreateTransactionContext 时会加入 tx （遵循嵌套事务的语义）。
+// 这样，上层开启事务后，只需传递 context ，下层未做修改的代码即可参与到同一个事务中。
+func WithTx(ctx context.Context, tx TransactionKeeper) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext 用于获取通过 WithTx 放入 context 的事务。
+func TxFromContext(ctx context.Context) (tx TransactionKeeper, ok bool) {
+	tx, ok = ctx.Value(txContextKey{}).(TransactionKeeper)
+	return tx, ok && tx != nil
+}
+
+// contextTx 用于获取应当接管语句执行的 context 中的事务。
+// 未开启 WithContextTx ，或当前实例本身已经在事务中时，返回 false 。
+func (client *AbstractDbClient) contextTx(ctx context.Context) (TransactionKeeper, bool) {
+	if !client.config.contextTxEnabled {
+		return nil, false
+	}
+	if _, inTx := client.Exer.(*sqlen.TxEnhance); inTx {
+		return nil, false
+	}
+	return TxFromContext(ctx)
+}
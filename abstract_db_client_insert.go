@@ -0,0 +1,18 @@
+package sqlmer
+
+// InsertIdDialect 用于定制 Insert 获取自增 id 的方式。
+type InsertIdDialect interface {
+	// InsertIdSql 用于将（已完成参数绑定的）插入语句改写为执行后返回一行一列自增 id 的查询语句。
+	// 返回 ok=false 时，表示不改写语句，通过 sql.Result.LastInsertId 获取自增 id 。
+	InsertIdSql(sqlText string) (querySql string, ok bool)
+}
+
+var _ InsertIdDialect = LastInsertIdDialect{}
+
+// LastInsertIdDialect 通过 sql.Result.LastInsertId 获取自增 id ，适用于 MySQL 、 SQLite 等驱动支持 LastInsertId 的数据库。
+type LastInsertIdDialect struct{}
+
+// InsertIdSql 不改写语句，总是返回 ok=false 。
+func (LastInsertIdDialect) InsertIdSql(sqlText string) (string, bool) {
+	return "", false
+}
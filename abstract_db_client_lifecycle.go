@@ -0,0 +1,91 @@
+package sqlmer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// clientLifecycle 用于记录 DbClient 的关闭状态及进行中的事务，由连接池及其上开启的事务共享。
+type clientLifecycle struct {
+	mu     sync.Mutex
+	closed bool
+	txs    sync.WaitGroup // 进行中的事务。
+}
+
+// beginTx 用于登记一个即将开始的事务，DbClient 已关闭时返回 false 。
+func (l *clientLifecycle) beginTx() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return false
+	}
+	l.txs.Add(1)
+	return true
+}
+
+// endTx 用于注销一个已完结的事务。
+func (l *clientLifecycle) endTx() {
+	l.txs.Done()
+}
+
+// close 用于标记 DbClient 已关闭，已经关闭过时返回 false 。
+func (l *clientLifecycle) close() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return false
+	}
+	l.closed = true
+	return true
+}
+
+// wait 用于等待进行中的事务完结，ctx 结束时不再等待，返回 ctx 的错误。
+func (l *clientLifecycle) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		l.txs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PingContext 用于检查数据库连接是否可用，必要时会建立连接。
+func (client *AbstractDbClient) PingContext(ctx context.Context) error {
+	return client.Db.PingContext(ctx)
+}
+
+// Stats 用于获取连接池的统计信息。
+func (client *AbstractDbClient) Stats() sql.DBStats {
+	return client.Db.Stats()
+}
+
+// Close 用于关闭 DbClient ：不再允许开始新的事务，等待进行中的事务完结后，关闭缓存的预编译语句和连接池。
+// 等待的时限由 WithContext 设置的 context 控制， context 结束时不再等待，仍会关闭连接池，并返回 context 的错误。
+// 通过 WithDb 传入的连接池同样会被关闭。重复调用是安全的。
+func (client *AbstractDbClient) Close() error {
+	if !client.lifecycle.close() {
+		return nil
+	}
+
+	var waitErr error
+	if err := client.lifecycle.wait(client.config.context); err != nil {
+		waitErr = fmt.Errorf("dbClient: stop waiting for in-flight transactions: %w", err)
+	}
+
+	if client.stmtCache != nil {
+		client.stmtCache.close()
+	}
+
+	return errors.Join(waitErr, client.Db.Close())
+}
@@ -0,0 +1,86 @@
+package sqlmer
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+// SqlObservation 是一次语句执行的观察结果，由 WithObserver 设置的观察函数接收。
+type SqlObservation struct {
+	RawSQL    string // 原始的 SQL 语句。
+	Args      []any  // 调用时传入的原始参数（未经合并及展开），命名参数的名称与 RawSQL 中的 @name 对应。
+	FixedSQL  string // 参数绑定（ IN 展开、命名参数改写等）后实际执行的 SQL ，参数绑定失败时为空。
+	FixedArgs []any  // 实际传给驱动的参数，参数绑定失败时为 nil 。
+	Query     bool   // 是否是查询语句。
+	InTx      bool   // 是否在事务中执行。
+
+	// 参数绑定和执行语句的耗时，查询语句只统计到返回游标为止。
+	Duration time.Duration
+
+	// 非查询语句执行成功时的执行结果，其余情况为 nil 。
+	Result sql.Result
+
+	// 执行遇到的错误，与方法返回的错误一致。
+	Err error
+}
+
+// ObserverFunc 用于观察 DbClient 执行的每条语句，在语句执行后同步调用，不应阻塞或修改参数。
+type ObserverFunc func(ctx context.Context, observation SqlObservation)
+
+// pendingObservation 是一次尚未结束的语句执行观察。
+type pendingObservation struct {
+	observers []ObserverFunc
+	rawSql    string
+	args      []any
+	query     bool
+	inTx      bool
+	start     time.Time
+}
+
+// startObservation 用于开始观察一次语句执行，未设置观察函数时返回 nil 。
+func (client *AbstractDbClient) startObservation(rawSql string, args []any, query bool) *pendingObservation {
+	if len(client.config.observers) == 0 {
+		return nil
+	}
+
+	if len(args) == 1 {
+		if positional, ok := args[0].(positionalArgs); ok { // 按位置绑定的参数，展开为索引参数。
+			args = positional
+		}
+	}
+
+	_, inTx := client.Exer.(*sqlen.TxEnhance)
+	return &pendingObservation{
+		observers: client.config.observers,
+		rawSql:    rawSql,
+		args:      args,
+		query:     query,
+		inTx:      inTx,
+		start:     time.Now(),
+	}
+}
+
+// finish 用于结束观察，并通知所有的观察函数。在 nil 上调用是安全的。
+func (o *pendingObservation) finish(ctx context.Context, fixedSql string, fixedArgs []any, result sql.Result, err error) {
+	if o == nil {
+		return
+	}
+
+	observation := SqlObservation{
+		RawSQL:    o.rawSql,
+		Args:      o.args,
+		FixedSQL:  fixedSql,
+		FixedArgs: fixedArgs,
+		Query:     o.query,
+		InTx:      o.inTx,
+		Duration:  time.Since(o.start),
+		Result:    result,
+		Err:       err,
+	}
+	for _, observer := range o.observers {
+		observer(ctx, observation)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/bunnier/sqlmer/sqlen"
 )
@@ -17,8 +18,46 @@ var _ DbClient = (*AbstractDbClient)(nil)
 //	@tran 返回一个实现了 TransactionKeeper（内嵌 DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
 //	@err 创建事务时遇到的错误。
 func (client *AbstractDbClient) CreateTransaction() (TransactionKeeper, error) {
-	tx, err := client.Db.Begin()
+	return client.CreateTransactionContext(context.Background(), nil)
+}
+
+// CreateTransactionContext 用于开始一个事务。开启了 WithContextTx 且 ctx 中带有 WithTx 设置的事务时，加入该事务。
+// params:
+//
+//	@ctx 事务的 context，在事务提交或回滚前一直有效，若 ctx 被取消，事务将被回滚。
+//	@opts 事务选项，用于指定隔离级别及是否只读，为 nil 时使用驱动的默认值。
+//
+// returns:
+//
+//	@tran 返回一个实现了 TransactionKeeper（内嵌 DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
+//	@err 创建事务时遇到的错误。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - sqlmer.ErrConnect: 当获取连接并开始事务的过程超过 GetConnTimeout 时返回该类错误。
+//   - sqlmer.ErrClientClosed: 当 DbClient 已经关闭时返回该类错误。
+func (client *AbstractDbClient) CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (TransactionKeeper, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.CreateTransactionContext(ctx, opts)
+	}
+
+	if !client.lifecycle.beginTx() {
+		return nil, fmt.Errorf("%w: can not begin transaction", ErrClientClosed)
+	}
+
+	// ctx 会伴随整个事务的生命周期，因此不能直接使用带超时的 context，
+	// 这里通过计时器，仅在开始事务的阶段应用连接超时时间。
+	txCtx, cancelFunc := context.WithCancel(ctx)
+	context.AfterFunc(txCtx, client.lifecycle.endTx) // 事务完结（或开始失败）时 txCtx 总会被取消，据此注销事务。
+	connTimer := time.AfterFunc(client.GetConnTimeout(), cancelFunc)
+
+	tx, err := client.Db.BeginTx(txCtx, opts)
+	if !connTimer.Stop() { // 计时器已经触发，txCtx 已被取消，即便事务已开始也会被自动回滚。
+		cancelFunc()
+		return nil, fmt.Errorf("%w: begin transaction timeout after %v", ErrConnect, client.GetConnTimeout())
+	}
+
 	if err != nil {
+		cancelFunc()
 		return nil, err
 	}
 
@@ -26,10 +65,16 @@ func (client *AbstractDbClient) CreateTransaction() (TransactionKeeper, error) {
 		config: client.config,
 		Db:     client.Db,                         // Db 对象。
 		Exer:   sqlen.NewTxEnhance(tx, client.Db), // 新的 client 中的实际执行对象使用开启的事务。
+
+		stmtCache: client.stmtCache, // 语句缓存绑定在连接池上，事务中使用时再绑定到事务。
+		lifecycle: client.lifecycle,
 	}
 
 	return &abstractTransactionKeeper{
-		txDbClient, tx, false, 0,
+		AbstractDbClient: txDbClient,
+		Tx:               tx,
+		opts:             opts,
+		cancelFunc:       cancelFunc,
 	}, nil
 }
 
@@ -70,6 +115,10 @@ func (client *AbstractDbClient) Execute(sqlText string, args ...any) (int64, err
 //   - sqlmer.ErrGetEffectedRows: 当执行成功，但驱动不支持获取影响行数时候，返回该类型错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) ExecuteContext(ctx context.Context, sqlText string, args ...any) (int64, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.ExecuteContext(ctx, sqlText, args...)
+	}
+
 	sqlResult, _, _, err := client.bindAndExecContext(ctx, sqlText, args...)
 	if err != nil {
 		return 0, err
@@ -122,6 +171,10 @@ func (client *AbstractDbClient) SizedExecute(expectedSize int64, sqlText string,
 //   - sqlmer.ErrExpectedSizeWrong: 当没有影响到预期行数时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) SizedExecuteContext(ctx context.Context, expectedSize int64, sqlText string, args ...any) error {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.SizedExecuteContext(ctx, expectedSize, sqlText, args...)
+	}
+
 	effectedRow, err := client.ExecuteContext(ctx, sqlText, args...)
 	if err != nil {
 		return err
@@ -132,6 +185,52 @@ func (client *AbstractDbClient) SizedExecuteContext(ctx context.Context, expecte
 	return nil
 }
 
+// Insert 用于执行插入语句，并返回新插入行的自增 id 。
+// params:
+//
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//	@args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+//
+// returns:
+//
+//	@lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+//	@err 执行语句时遇到的错误。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+//   - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+//   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+func (client *AbstractDbClient) Insert(sqlText string, args ...any) (int64, error) {
+	ctx, cancelFunc := client.getExecTimeoutContext()
+	defer cancelFunc()
+	return client.InsertContext(ctx, sqlText, args...)
+}
+
+// InsertContext 用于执行插入语句，并返回新插入行的自增 id 。
+// params:
+//
+//	@ctx context。
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//	@args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+//
+// returns:
+//
+//	@lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+//	@err 执行语句时遇到的错误。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+//   - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+//   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+func (client *AbstractDbClient) InsertContext(ctx context.Context, sqlText string, args ...any) (int64, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.InsertContext(ctx, sqlText, args...)
+	}
+
+	id, _, _, err := client.bindAndInsertContext(ctx, sqlText, args...)
+	return id, err
+}
+
 // Exists 用于判断给定的查询的结果是否至少包含 1 行。
 // params:
 //
@@ -168,6 +267,10 @@ func (client *AbstractDbClient) Exists(sqlText string, args ...any) (bool, error
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) ExistsContext(ctx context.Context, sqlText string, args ...any) (bool, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.ExistsContext(ctx, sqlText, args...)
+	}
+
 	rows, _, _, err := client.bindAndQueryRowsContext(ctx, sqlText, args...)
 	if err != nil {
 		return false, err
@@ -220,6 +323,10 @@ func (client *AbstractDbClient) Scalar(sqlText string, args ...any) (any, bool,
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) ScalarContext(ctx context.Context, sqlText string, args ...any) (any, bool, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.ScalarContext(ctx, sqlText, args...)
+	}
+
 	row, fixedSqlText, fixedArgs, err := client.bindAndQueryRowContext(ctx, sqlText, args...)
 	if err != nil {
 		return nil, false, err
@@ -271,6 +378,10 @@ func (client *AbstractDbClient) Get(sqlText string, args ...any) (map[string]any
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) GetContext(ctx context.Context, sqlText string, args ...any) (map[string]any, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.GetContext(ctx, sqlText, args...)
+	}
+
 	rows, _, _, err := client.bindAndQueryRowsContext(ctx, sqlText, args...)
 	if err != nil {
 		return nil, err
@@ -325,6 +436,10 @@ func (client *AbstractDbClient) SliceGet(sqlText string, args ...any) ([]map[str
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) SliceGetContext(ctx context.Context, sqlText string, args ...any) ([]map[string]any, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.SliceGetContext(ctx, sqlText, args...)
+	}
+
 	rows, _, _, err := client.bindAndQueryRowsContext(ctx, sqlText, args...)
 	if err != nil {
 		return nil, err
@@ -382,6 +497,10 @@ func (client *AbstractDbClient) Row(sqlText string, args ...any) (*sqlen.Enhance
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) RowContext(ctx context.Context, sqlText string, args ...any) (*sqlen.EnhanceRow, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.RowContext(ctx, sqlText, args...)
+	}
+
 	row, fixedSqlText, fixedArgs, err := client.bindAndQueryRowContext(ctx, sqlText, args...)
 	if err != nil {
 		return nil, err
@@ -429,6 +548,10 @@ func (client *AbstractDbClient) Rows(sqlText string, args ...any) (*sqlen.Enhanc
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (client *AbstractDbClient) RowsContext(ctx context.Context, sqlText string, args ...any) (*sqlen.EnhanceRows, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		return tx.RowsContext(ctx, sqlText, args...)
+	}
+
 	rows, fixedSqlText, fixedArgs, err := client.bindAndQueryRowsContext(ctx, sqlText, args...)
 	if err != nil {
 		return nil, err
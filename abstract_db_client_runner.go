@@ -3,47 +3,112 @@ package sqlmer
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/bunnier/sqlmer/sqlen"
 )
 
 // bindAndExecContext 用于统一处理参数绑定、执行 SQL 与执行错误包装。
 func (client *AbstractDbClient) bindAndExecContext(ctx context.Context, rawSql string, args ...any) (sql.Result, string, []any, error) {
+	observation := client.startObservation(rawSql, args, false)
 	fixedSql, fixedArgs, err := client.config.bindArgsFunc(rawSql, args...)
 	if err != nil {
+		observation.finish(ctx, "", nil, nil, err)
 		return nil, "", nil, err
 	}
 
-	result, err := client.Exer.ExecContext(ctx, fixedSql, fixedArgs...)
+	result, err := client.execContext(ctx, fixedSql, fixedArgs)
 	if err != nil {
-		return nil, "", nil, getExecutingSqlError(err, rawSql, fixedSql, fixedArgs)
+		err = getExecutingSqlError(err, rawSql, fixedSql, fixedArgs)
+		observation.finish(ctx, fixedSql, fixedArgs, nil, err)
+		return nil, "", nil, err
 	}
 
+	observation.finish(ctx, fixedSql, fixedArgs, result, nil)
 	return result, fixedSql, fixedArgs, nil
 }
 
 // bindAndQueryRowsContext 用于统一处理参数绑定、查询游标与执行错误包装。
 func (client *AbstractDbClient) bindAndQueryRowsContext(ctx context.Context, rawSql string, args ...any) (*sqlen.EnhanceRows, string, []any, error) {
+	observation := client.startObservation(rawSql, args, true)
 	fixedSql, fixedArgs, err := client.config.bindArgsFunc(rawSql, args...)
 	if err != nil {
+		observation.finish(ctx, "", nil, nil, err)
 		return nil, "", nil, err
 	}
 
-	rows, err := client.Exer.EnhancedQueryContext(ctx, fixedSql, fixedArgs...)
+	rows, err := client.queryContext(ctx, fixedSql, fixedArgs)
 	if err != nil {
-		return nil, "", nil, getExecutingSqlError(err, rawSql, fixedSql, fixedArgs)
+		err = getExecutingSqlError(err, rawSql, fixedSql, fixedArgs)
+		observation.finish(ctx, fixedSql, fixedArgs, nil, err)
+		return nil, "", nil, err
 	}
 
+	observation.finish(ctx, fixedSql, fixedArgs, nil, nil)
 	return rows, fixedSql, fixedArgs, nil
 }
 
 // bindAndQueryRowContext 用于统一处理参数绑定、单行查询对象创建与执行错误包装。
+// 执行语句的错误由返回的 row 携带，观察函数收到的是未包装的错误。
 func (client *AbstractDbClient) bindAndQueryRowContext(ctx context.Context, rawSql string, args ...any) (*sqlen.EnhanceRow, string, []any, error) {
+	observation := client.startObservation(rawSql, args, true)
 	fixedSql, fixedArgs, err := client.config.bindArgsFunc(rawSql, args...)
 	if err != nil {
+		observation.finish(ctx, "", nil, nil, err)
 		return nil, "", nil, err
 	}
 
-	row := client.Exer.EnhancedQueryRowContext(ctx, fixedSql, fixedArgs...)
+	row := client.queryRowContext(ctx, fixedSql, fixedArgs)
+	observation.finish(ctx, fixedSql, fixedArgs, nil, row.Err())
 	return row, fixedSql, fixedArgs, nil
 }
+
+// bindAndInsertContext 用于统一处理参数绑定、执行插入语句、获取自增 id 与执行错误包装。
+func (client *AbstractDbClient) bindAndInsertContext(ctx context.Context, rawSql string, args ...any) (int64, string, []any, error) {
+	observation := client.startObservation(rawSql, args, false)
+	fixedSql, fixedArgs, err := client.config.bindArgsFunc(rawSql, args...)
+	if err != nil {
+		observation.finish(ctx, "", nil, nil, err)
+		return 0, "", nil, err
+	}
+
+	// 驱动不支持 LastInsertId 的，改写为查询语句，从结果中读取自增 id 。
+	if querySql, ok := client.config.insertIdDialect.InsertIdSql(fixedSql); ok {
+		id, err := client.queryInsertId(ctx, rawSql, querySql, fixedArgs)
+		observation.finish(ctx, querySql, fixedArgs, nil, err)
+		if err != nil && !errors.Is(err, ErrGetInsertId) {
+			return 0, "", nil, err
+		}
+		return id, querySql, fixedArgs, err
+	}
+
+	result, err := client.execContext(ctx, fixedSql, fixedArgs)
+	if err != nil {
+		err = getExecutingSqlError(err, rawSql, fixedSql, fixedArgs)
+		observation.finish(ctx, fixedSql, fixedArgs, nil, err)
+		return 0, "", nil, err
+	}
+	observation.finish(ctx, fixedSql, fixedArgs, result, nil)
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fixedSql, fixedArgs, fmt.Errorf("%w: %s", ErrGetInsertId, err.Error())
+	}
+	return id, fixedSql, fixedArgs, nil
+}
+
+// queryInsertId 用于执行改写为查询的插入语句，并从结果中读取自增 id 。
+func (client *AbstractDbClient) queryInsertId(ctx context.Context, rawSql string, querySql string, fixedArgs []any) (int64, error) {
+	var id sql.NullInt64
+	if err := client.queryRowContext(ctx, querySql, fixedArgs).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: no row returned", ErrGetInsertId)
+		}
+		return 0, getExecutingSqlError(err, rawSql, querySql, fixedArgs)
+	}
+	if !id.Valid {
+		return 0, fmt.Errorf("%w: got null", ErrGetInsertId)
+	}
+	return id.Int64, nil
+}
@@ -1,6 +1,7 @@
 package sqlmer_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"path/filepath"
@@ -14,7 +15,7 @@ import (
 	"github.com/bunnier/sqlmer/sqlite"
 )
 
-func newSqliteDbClientForAbstractDbTest(t *testing.T) sqlmer.DbClient {
+func newSqliteDbClientForAbstractDbTest(t *testing.T, options ...sqlmer.DbClientOption) sqlmer.DbClient {
 	t.Helper()
 
 	dsn := filepath.Join(t.TempDir(), "abstract.db")
@@ -28,11 +29,11 @@ func newSqliteDbClientForAbstractDbTest(t *testing.T) sqlmer.DbClient {
 		t.Fatalf("CreateSqliteSchema() error = %v", err)
 	}
 
-	dbClient, err := sqlite.NewSqliteDbClient(
-		dsn,
+	options = append([]sqlmer.DbClientOption{
 		sqlmer.WithConnTimeout(testenv.DefaultTimeout),
 		sqlmer.WithExecTimeout(testenv.DefaultTimeout),
-	)
+	}, options...)
+	dbClient, err := sqlite.NewSqliteDbClient(dsn, options...)
 	if err != nil {
 		t.Fatalf("NewSqliteDbClient() error = %v", err)
 	}
@@ -76,6 +77,62 @@ func Test_AbstractDbClient_Execute(t *testing.T) {
 	}
 }
 
+// returningInsertIdDialect 通过 RETURNING 子句获取自增 id 。
+type returningInsertIdDialect struct {
+	returning string
+}
+
+func (d returningInsertIdDialect) InsertIdSql(sqlText string) (string, bool) {
+	return sqlText + " RETURNING " + d.returning, true
+}
+
+func Test_AbstractDbClient_Insert(t *testing.T) {
+	const sqlText = `INSERT INTO go_TypeTest(intTest, tinyintTest, smallIntTest, bigIntTest, unsignedTest, varcharTest, charTest, charTextTest, dateTest, dateTimeTest, timestampTest, floatTest, doubleTest, decimalTest, bitTest)
+	VALUES (@p1, 5, 5, 5, 5, '行5', '行5char', '行5text','2021-07-05','2021-07-05 15:38:50.425','2021-07-05 15:38:50.425', 5.456, 5.15678, 5.45678999, 1)`
+
+	assertInsertId := func(t *testing.T, dbClient sqlmer.DbClient, intTest int) {
+		t.Helper()
+
+		id, err := dbClient.Insert(sqlText, intTest)
+		if err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+
+		gotIntTest, _, err := dbClient.Scalar("SELECT intTest FROM go_TypeTest WHERE id=@p1", id)
+		if err != nil {
+			t.Fatalf("Scalar() error = %v", err)
+		}
+		if gotIntTest != int64(intTest) {
+			t.Fatalf("Insert() id = %v, intTest of the row = %v, want %v", id, gotIntTest, intTest)
+		}
+	}
+
+	t.Run("last_insert_id", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t)
+		assertInsertId(t, dbClient, 51)
+		assertInsertId(t, dbClient, 52)
+	})
+
+	t.Run("dialect", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t, sqlmer.WithInsertIdDialect(returningInsertIdDialect{"id"}))
+		assertInsertId(t, dbClient, 53)
+	})
+
+	t.Run("dialect_null", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t, sqlmer.WithInsertIdDialect(returningInsertIdDialect{"NULL"}))
+		if _, err := dbClient.InsertContext(context.Background(), sqlText, 54); !errors.Is(err, sqlmer.ErrGetInsertId) {
+			t.Fatalf("InsertContext() error = %v, want ErrGetInsertId", err)
+		}
+	})
+
+	t.Run("executing_error", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t)
+		if _, err := dbClient.Insert("INSERT INTO go_TypeTest(notExists) VALUES (1)"); !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("Insert() error = %v, want ErrExecutingSql", err)
+		}
+	})
+}
+
 func Test_AbstractDbClient_Exists(t *testing.T) {
 	dbClient := newSqliteDbClientForAbstractDbTest(t)
 
@@ -317,3 +374,469 @@ func Test_AbstractDbClient_Row_no_rows_not_wrapped(t *testing.T) {
 		t.Fatalf("Row().Scan() error = %v, do not want ErrExecutingSql", err)
 	}
 }
+
+func Test_AbstractDbClient_CreateTransactionContext(t *testing.T) {
+	dbClient := newSqliteDbClientForAbstractDbTest(t)
+
+	t.Run("read_only", func(t *testing.T) {
+		tx, err := dbClient.CreateTransactionContext(context.Background(), &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			t.Fatalf("CreateTransactionContext() error = %v", err)
+		}
+		defer tx.Close()
+
+		if _, err = tx.Execute("UPDATE go_TypeTest SET intTest=2 WHERE id=1"); !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("Execute() in read only transaction error = %v, want ErrExecutingSql", err)
+		}
+	})
+
+	t.Run("unsupported_isolation_level", func(t *testing.T) {
+		if _, err := dbClient.CreateTransactionContext(context.Background(), &sql.TxOptions{Isolation: sql.LevelReadUncommitted}); err == nil {
+			t.Fatal("CreateTransactionContext() error = nil, want error")
+		}
+	})
+
+	t.Run("canceled_context", func(t *testing.T) {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		cancelFunc()
+
+		if _, err := dbClient.CreateTransactionContext(ctx, nil); !errors.Is(err, context.Canceled) {
+			t.Fatalf("CreateTransactionContext() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("cancel_after_begin", func(t *testing.T) {
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		tx, err := dbClient.CreateTransactionContext(ctx, nil)
+		if err != nil {
+			t.Fatalf("CreateTransactionContext() error = %v", err)
+		}
+		defer tx.Close()
+
+		if _, err = tx.Execute("UPDATE go_TypeTest SET intTest=2 WHERE id=1"); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		cancelFunc() // 取消 context 后事务会被回滚。
+		if err = tx.Commit(); err == nil {
+			t.Fatal("Commit() error = nil, want error")
+		}
+
+		got, _, err := dbClient.Scalar("SELECT intTest FROM go_TypeTest WHERE id=1")
+		if err != nil {
+			t.Fatalf("Scalar() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, int64(1)) {
+			t.Fatalf("Scalar() = %v, want %v", got, int64(1))
+		}
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		tx, err := dbClient.CreateTransactionContext(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("CreateTransactionContext() error = %v", err)
+		}
+		defer tx.Close()
+
+		embeddedTx, err := tx.CreateTransactionContext(context.Background(), &sql.TxOptions{})
+		if err != nil {
+			t.Fatalf("CreateTransactionContext() embeddedly error = %v", err)
+		}
+		if embeddedTx != tx {
+			t.Fatal("CreateTransactionContext() embeddedly should reuse the outer transaction")
+		}
+		if err = embeddedTx.Close(); err != nil {
+			t.Fatalf("Close() embeddedly error = %v", err)
+		}
+
+		if err = tx.Commit(); err != nil {
+			t.Fatalf("Commit() error = %v", err)
+		}
+	})
+
+	t.Run("embedded_conflict", func(t *testing.T) {
+		for _, dbClient := range []sqlmer.DbClient{dbClient, newSqliteDbClientForAbstractDbTest(t, sqlmer.WithSavepoint(true))} {
+			tx, err := dbClient.CreateTransactionContext(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+			if err != nil {
+				t.Fatalf("CreateTransactionContext() error = %v", err)
+			}
+			defer tx.Close()
+
+			for _, opts := range []*sql.TxOptions{{ReadOnly: true}, {Isolation: sql.LevelReadCommitted}} {
+				if _, err := tx.CreateTransactionContext(context.Background(), opts); !errors.Is(err, sqlmer.ErrTran) {
+					t.Fatalf("CreateTransactionContext(%+v) embeddedly error = %v, want ErrTran", opts, err)
+				}
+			}
+
+			embeddedTx, err := tx.CreateTransactionContext(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+			if err != nil {
+				t.Fatalf("CreateTransactionContext() embeddedly error = %v", err)
+			}
+			if _, err := embeddedTx.CreateTransactionContext(context.Background(), &sql.TxOptions{ReadOnly: true}); !errors.Is(err, sqlmer.ErrTran) {
+				t.Fatalf("CreateTransactionContext() in nested transaction error = %v, want ErrTran", err)
+			}
+			if err = embeddedTx.Close(); err != nil {
+				t.Fatalf("Close() embeddedly error = %v", err)
+			}
+		}
+	})
+}
+
+func Test_AbstractDbClient_StmtCache(t *testing.T) {
+	dbClient := newSqliteDbClientForAbstractDbTest(t, sqlmer.WithStmtCache(2))
+	provider := dbClient.(sqlmer.StmtCacheStatsProvider)
+
+	assertStats := func(t *testing.T, want sqlmer.StmtCacheStats) {
+		t.Helper()
+		if got := provider.StmtCacheStats(); got != want {
+			t.Fatalf("StmtCacheStats() = %+v, want %+v", got, want)
+		}
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		for id := 1; id <= 3; id++ {
+			row, err := dbClient.Get("SELECT id FROM go_TypeTest WHERE id=@id", map[string]any{"id": id})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if row["id"] != int64(id) {
+				t.Fatalf("Get() id = %v, want %v", row["id"], id)
+			}
+		}
+		assertStats(t, sqlmer.StmtCacheStats{Hits: 2, Misses: 1, Size: 1, Capacity: 2})
+	})
+
+	t.Run("evict_in_use", func(t *testing.T) {
+		rows, err := dbClient.Rows("SELECT id FROM go_TypeTest ORDER BY id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		// 淘汰正在使用的语句，已返回的游标不受影响。
+		if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest=varcharTest WHERE id=@p1", 1); err != nil {
+			t.Fatal(err)
+		}
+		assertStats(t, sqlmer.StmtCacheStats{Hits: 2, Misses: 3, Size: 2, Capacity: 2})
+
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if count != 4 {
+			t.Fatalf("Rows() got %d rows, want 4", count)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		tx, err := dbClient.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+
+		// 命中的语句绑定到事务上执行。
+		if _, err := tx.Execute("UPDATE go_TypeTest SET varcharTest=varcharTest WHERE id=@p1", 1); err != nil {
+			t.Fatal(err)
+		}
+		assertStats(t, sqlmer.StmtCacheStats{Hits: 3, Misses: 3, Size: 2, Capacity: 2})
+
+		// 未命中的语句直接在事务上执行，不加入缓存。
+		if _, err := tx.Execute("UPDATE go_TypeTest SET varcharTest=@p1 WHERE id=@p2", "tx", 1); err != nil {
+			t.Fatal(err)
+		}
+		v, _, err := tx.Scalar("SELECT varcharTest FROM go_TypeTest WHERE id=1")
+		if err != nil || v != "tx" {
+			t.Fatalf("Scalar() = %v, %v, want tx, nil", v, err)
+		}
+		assertStats(t, sqlmer.StmtCacheStats{Hits: 3, Misses: 5, Size: 2, Capacity: 2})
+
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		v, _, err = dbClient.Scalar("SELECT varcharTest FROM go_TypeTest WHERE id=1")
+		if err != nil || v != "行1" {
+			t.Fatalf("Scalar() = %v, %v, want 行1, nil", v, err)
+		}
+	})
+
+	t.Run("prepare_error", func(t *testing.T) {
+		_, err := dbClient.Execute("UPDATE not_exists SET id=1")
+		if !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("Execute() error = %v, want ErrExecutingSql", err)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t)
+		if _, err := dbClient.Get("SELECT 1"); err != nil {
+			t.Fatal(err)
+		}
+		if got := dbClient.(sqlmer.StmtCacheStatsProvider).StmtCacheStats(); got != (sqlmer.StmtCacheStats{}) {
+			t.Fatalf("StmtCacheStats() = %+v, want zero", got)
+		}
+	})
+}
+
+func Test_AbstractDbClient_Close(t *testing.T) {
+	t.Run("wait_for_transactions", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t, sqlmer.WithStmtCache(2))
+		if err := dbClient.PingContext(context.Background()); err != nil {
+			t.Fatalf("PingContext() error = %v", err)
+		}
+		if _, err := dbClient.Get("SELECT 1"); err != nil {
+			t.Fatal(err)
+		}
+
+		tx, err := dbClient.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats := dbClient.Stats(); stats.InUse != 1 {
+			t.Fatalf("Stats().InUse = %d, want 1", stats.InUse)
+		}
+
+		closed := make(chan error, 1)
+		go func() { closed <- dbClient.Close() }()
+
+		select {
+		case err := <-closed:
+			t.Fatalf("Close() returned before the transaction completed, error = %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// 关闭过程中不允许开始新的事务，已开始的事务不受影响。
+		if _, err := dbClient.CreateTransaction(); !errors.Is(err, sqlmer.ErrClientClosed) {
+			t.Fatalf("CreateTransaction() error = %v, want ErrClientClosed", err)
+		}
+		if _, err := tx.Execute("UPDATE go_TypeTest SET varcharTest='closing' WHERE id=1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := <-closed; err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if err := dbClient.Close(); err != nil {
+			t.Fatalf("second Close() error = %v", err)
+		}
+		if stats := dbClient.(sqlmer.StmtCacheStatsProvider).StmtCacheStats(); stats.Size != 0 {
+			t.Fatalf("StmtCacheStats().Size = %d, want 0", stats.Size)
+		}
+		if err := dbClient.PingContext(context.Background()); err == nil {
+			t.Fatal("PingContext() on closed client should fail")
+		}
+	})
+
+	t.Run("rolled_back_by_context", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t)
+
+		// 事务的 context 被取消时，事务被自动回滚，不会阻塞 Close 。
+		ctx, cancel := context.WithCancel(context.Background())
+		if _, err := dbClient.CreateTransactionContext(ctx, nil); err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+
+		if err := dbClient.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	})
+
+	t.Run("context_done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		dbClient := newSqliteDbClientForAbstractDbTest(t, sqlmer.WithContext(ctx))
+
+		tx, err := dbClient.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+
+		if err := dbClient.Close(); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Close() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+func Test_AbstractDbClient_PoolSettings(t *testing.T) {
+	dbClient := newSqliteDbClientForAbstractDbTest(t,
+		sqlmer.WithMaxOpenConns(3),
+		sqlmer.WithMaxIdleConns(0),
+		sqlmer.WithConnMaxIdleTime(time.Minute),
+		sqlmer.WithConnMaxLifetime(time.Hour),
+	)
+	defer dbClient.Close()
+
+	if _, err := dbClient.Get("SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := dbClient.Stats()
+	if stats.MaxOpenConnections != 3 {
+		t.Fatalf("Stats().MaxOpenConnections = %d, want 3", stats.MaxOpenConnections)
+	}
+	if stats.Idle != 0 || stats.MaxIdleClosed == 0 {
+		t.Fatalf("Stats() = %+v, want no idle connections", stats)
+	}
+
+	config, err := sqlmer.NewDbClientConfig(sqlmer.WithConnMaxLifetime(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := config.ConnMaxLifetime(); !ok || d != 0 {
+		t.Fatalf("ConnMaxLifetime() = %v, %v, want 0, true", d, ok)
+	}
+
+	if config, err = sqlmer.NewDbClientConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.ConnMaxLifetime(); ok {
+		t.Fatal("ConnMaxLifetime() should not be set by default")
+	}
+}
+
+func Test_AbstractDbClient_ContextTx(t *testing.T) {
+	const readSql = "SELECT varcharTest FROM go_TypeTest WHERE id=1"
+	ctx := context.Background()
+
+	// repository 模拟未做修改、只接收 context 的下层代码。
+	repository := func(t *testing.T, ctx context.Context, dbClient sqlmer.DbClient) {
+		t.Helper()
+		if _, err := dbClient.ExecuteContext(ctx, "UPDATE go_TypeTest SET varcharTest=@p1 WHERE id=1", "ctx"); err != nil {
+			t.Fatal(err)
+		}
+
+		tx, err := dbClient.CreateTransactionContext(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+		if _, err := tx.ExecuteContext(ctx, "UPDATE go_TypeTest SET varcharTest='ctx' WHERE id=2"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t, sqlmer.WithContextTx(true))
+
+		tx, err := dbClient.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+		txCtx := sqlmer.WithTx(ctx, tx)
+		if got, ok := sqlmer.TxFromContext(txCtx); !ok || got != tx {
+			t.Fatalf("TxFromContext() = %v, %v, want the transaction", got, ok)
+		}
+
+		repository(t, txCtx, dbClient)
+
+		// 在同一个事务中可以读到未提交的修改。
+		v, _, err := dbClient.ScalarContext(txCtx, readSql)
+		if err != nil || v != "ctx" {
+			t.Fatalf("ScalarContext() = %v, %v, want ctx, nil", v, err)
+		}
+		rows, err := dbClient.SliceGetContext(txCtx, "SELECT id FROM go_TypeTest WHERE varcharTest='ctx'")
+		if err != nil || len(rows) != 2 {
+			t.Fatalf("SliceGetContext() = %v, %v, want 2 rows", rows, err)
+		}
+
+		// 加入的事务提交后，外层事务仍未提交，回滚后修改全部撤销。
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		if v, _, _ := dbClient.ScalarContext(ctx, readSql); v != "行1" {
+			t.Fatalf("ScalarContext() = %v, want 行1", v)
+		}
+		if ok, _ := dbClient.Exists("SELECT 1 FROM go_TypeTest WHERE varcharTest='ctx'"); ok {
+			t.Fatal("updates in the joined transaction should be rolled back")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		dbClient := newSqliteDbClientForAbstractDbTest(t)
+
+		tx, err := dbClient.CreateTransactionContext(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+
+		// 未开启时忽略 context 中的事务（已经回滚，若使用会报错）。
+		repository(t, sqlmer.WithTx(ctx, tx), dbClient)
+		if v, _, _ := dbClient.Scalar(readSql); v != "ctx" {
+			t.Fatalf("Scalar() = %v, want ctx", v)
+		}
+	})
+}
+
+func Test_AbstractDbClient_Observer(t *testing.T) {
+	var observations []sqlmer.SqlObservation
+	var counted int
+	dbClient := newSqliteDbClientForAbstractDbTest(t,
+		sqlmer.WithObserver(func(ctx context.Context, o sqlmer.SqlObservation) {
+			observations = append(observations, o)
+		}),
+		sqlmer.WithObserver(func(ctx context.Context, o sqlmer.SqlObservation) {
+			counted++
+		}),
+	)
+
+	if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest=@name WHERE id IN (@ids)", map[string]any{"name": "obs", "ids": []int{1, 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dbClient.Scalar("SELECT varcharTest FROM go_TypeTest WHERE id=@p1", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbClient.SliceGet("SELECT * FROM not_exists"); err == nil {
+		t.Fatal("SliceGet() should fail")
+	}
+	if _, err := dbClient.Get("SELECT @missing"); err == nil {
+		t.Fatal("Get() should fail")
+	}
+	tx, err := dbClient.CreateTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Close()
+	if _, err := tx.Insert("INSERT INTO go_TypeTest (intTest, tinyintTest, smallIntTest, bigIntTest, unsignedTest, varcharTest, charTest, charTextTest, dateTest, dateTimeTest, timestampTest, floatTest, doubleTest, decimalTest, bitTest) SELECT intTest, tinyintTest, smallIntTest, bigIntTest, unsignedTest, varcharTest, charTest, charTextTest, dateTest, dateTimeTest, timestampTest, floatTest, doubleTest, decimalTest, bitTest FROM go_TypeTest WHERE id=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(observations) != 5 || counted != 5 {
+		t.Fatalf("got %d and %d observations, want 5", len(observations), counted)
+	}
+
+	exec := observations[0]
+	if exec.FixedSQL != "UPDATE go_TypeTest SET varcharTest=? WHERE id IN (?,?)" || len(exec.FixedArgs) != 3 || len(exec.Args) != 1 || exec.Query || exec.InTx || exec.Err != nil {
+		t.Fatalf("exec observation = %+v", exec)
+	}
+	if n, _ := exec.Result.RowsAffected(); n != 2 {
+		t.Fatalf("exec observation RowsAffected = %d, want 2", n)
+	}
+
+	if scalar := observations[1]; !scalar.Query || scalar.FixedSQL != "SELECT varcharTest FROM go_TypeTest WHERE id=?" || scalar.Result != nil || scalar.Err != nil || scalar.Duration <= 0 {
+		t.Fatalf("scalar observation = %+v", scalar)
+	}
+	if failed := observations[2]; !errors.Is(failed.Err, sqlmer.ErrExecutingSql) || failed.FixedSQL == "" {
+		t.Fatalf("failed observation = %+v", failed)
+	}
+	if unbound := observations[3]; !errors.Is(unbound.Err, sqlmer.ErrParseParamFailed) || unbound.FixedSQL != "" || unbound.RawSQL != "SELECT @missing" {
+		t.Fatalf("unbound observation = %+v", unbound)
+	}
+	if insert := observations[4]; !insert.InTx || insert.Result == nil || insert.Err != nil {
+		t.Fatalf("insert observation = %+v", insert)
+	}
+}
@@ -0,0 +1,224 @@
+package sqlmer
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+var _ StmtCacheStatsProvider = (*AbstractDbClient)(nil)
+
+// StmtCacheStats 是语句缓存的统计信息。
+type StmtCacheStats struct {
+	Hits     uint64 // 命中缓存的次数。
+	Misses   uint64 // 未命中缓存、需要预编译语句的次数。
+	Size     int    // 当前缓存的语句数量。
+	Capacity int    // 缓存的容量，为 0 表示未开启语句缓存。
+}
+
+// StmtCacheStatsProvider 用于获取语句缓存（见 WithStmtCache ）的统计信息。
+// AbstractDbClient 实现了该接口。
+type StmtCacheStatsProvider interface {
+	// StmtCacheStats 返回语句缓存的统计信息。
+	StmtCacheStats() StmtCacheStats
+}
+
+// StmtCacheStats 返回语句缓存的统计信息，未开启语句缓存时，返回零值。
+func (client *AbstractDbClient) StmtCacheStats() StmtCacheStats {
+	if client.stmtCache == nil {
+		return StmtCacheStats{}
+	}
+	return client.stmtCache.stats()
+}
+
+// stmtCacheEntry 是语句缓存中的一项。
+type stmtCacheEntry struct {
+	sqlText string
+	stmt    *sql.Stmt
+	refs    int  // 正在使用该语句的调用数。
+	evicted bool // 是否已被淘汰，被淘汰且不再被使用时关闭语句。
+}
+
+// stmtCache 是绑定在连接池上的预编译语句 LRU 缓存，以绑定参数后的 SQL 为键。
+type stmtCache struct {
+	db       *sql.DB
+	capacity int
+
+	mu      sync.Mutex
+	lru     *list.List               // 元素为 *stmtCacheEntry ，最近使用的在前。
+	entries map[string]*list.Element // SQL 到 lru 中元素的映射。
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newStmtCache(db *sql.DB, capacity int) *stmtCache {
+	return &stmtCache{
+		db:       db,
+		capacity: capacity,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+	}
+}
+
+// acquire 用于获取 sqlText 对应的预编译语句，未命中时预编译并加入缓存；使用完毕后需要调用 release 。
+func (c *stmtCache) acquire(ctx context.Context, sqlText string) (*stmtCacheEntry, error) {
+	if entry := c.load(sqlText); entry != nil {
+		c.hits.Add(1)
+		return entry, nil
+	}
+
+	// 预编译需要访问数据库，不能在持有锁时进行。
+	c.misses.Add(1)
+	stmt, err := c.db.PrepareContext(ctx, sqlText)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 其它协程可能已经预编译了相同的语句。
+	if elem, ok := c.entries[sqlText]; ok {
+		stmt.Close()
+		c.lru.MoveToFront(elem)
+		entry := elem.Value.(*stmtCacheEntry)
+		entry.refs++
+		return entry, nil
+	}
+
+	entry := &stmtCacheEntry{sqlText: sqlText, stmt: stmt, refs: 1}
+	c.entries[sqlText] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.capacity {
+		oldest := c.lru.Remove(c.lru.Back()).(*stmtCacheEntry)
+		delete(c.entries, oldest.sqlText)
+		oldest.evicted = true
+		if oldest.refs == 0 {
+			oldest.stmt.Close()
+		}
+	}
+
+	return entry, nil
+}
+
+// load 用于从缓存中获取语句，未命中时返回 nil 。
+func (c *stmtCache) load(sqlText string) *stmtCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[sqlText]
+	if !ok {
+		return nil
+	}
+
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*stmtCacheEntry)
+	entry.refs++
+	return entry
+}
+
+// release 用于归还 acquire 获取的语句，已被淘汰的语句在不再被使用时关闭。
+func (c *stmtCache) release(entry *stmtCacheEntry) {
+	c.mu.Lock()
+	entry.refs--
+	needClose := entry.evicted && entry.refs == 0
+	c.mu.Unlock()
+
+	if needClose {
+		entry.stmt.Close()
+	}
+}
+
+// close 用于关闭并清空缓存的语句，正在使用的语句在归还时关闭。
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*stmtCacheEntry)
+		entry.evicted = true
+		if entry.refs == 0 {
+			entry.stmt.Close()
+		}
+	}
+	c.lru.Init()
+	clear(c.entries)
+}
+
+// stats 返回缓存的统计信息。
+func (c *stmtCache) stats() StmtCacheStats {
+	c.mu.Lock()
+	size := c.lru.Len()
+	c.mu.Unlock()
+
+	return StmtCacheStats{
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		Size:     size,
+		Capacity: c.capacity,
+	}
+}
+
+// cachedStmt 用于获取 sqlText 对应的缓存语句，使用完毕后需要调用返回的 release 函数归还。
+// 在事务中时，语句通过 tx.StmtContext 绑定到事务上；由于事务占用的可能是连接池中唯一的连接，
+// 事务中未命中缓存时不做预编译，返回 nil ，由调用方直接在事务上执行。
+func (client *AbstractDbClient) cachedStmt(ctx context.Context, sqlText string) (stmt *sqlen.StmtEnhance, release func(), err error) {
+	txExer, inTx := client.Exer.(*sqlen.TxEnhance)
+
+	var entry *stmtCacheEntry
+	if inTx {
+		if entry = client.stmtCache.load(sqlText); entry == nil {
+			client.stmtCache.misses.Add(1)
+			return nil, nil, nil
+		}
+		client.stmtCache.hits.Add(1)
+	} else if entry, err = client.stmtCache.acquire(ctx, sqlText); err != nil {
+		return nil, nil, err
+	}
+
+	sqlStmt := entry.stmt
+	if inTx {
+		sqlStmt = txExer.StmtContext(ctx, sqlStmt) // 事务内的语句在事务结束时由标准库关闭。
+	}
+	return sqlen.NewStmtEnhance(sqlStmt, client.Db), func() { client.stmtCache.release(entry) }, nil
+}
+
+// execContext 用于执行语句，开启了语句缓存时，使用缓存的预编译语句。
+func (client *AbstractDbClient) execContext(ctx context.Context, sqlText string, args []any) (sql.Result, error) {
+	if client.stmtCache != nil {
+		stmt, release, err := client.cachedStmt(ctx, sqlText)
+		if err != nil {
+			return nil, err
+		}
+		if stmt != nil {
+			defer release()
+			return stmt.ExecContext(ctx, args...)
+		}
+	}
+	return client.Exer.ExecContext(ctx, sqlText, args...)
+}
+
+// queryContext 用于执行查询，开启了语句缓存时，使用缓存的预编译语句。
+func (client *AbstractDbClient) queryContext(ctx context.Context, sqlText string, args []any) (*sqlen.EnhanceRows, error) {
+	if client.stmtCache != nil {
+		stmt, release, err := client.cachedStmt(ctx, sqlText)
+		if err != nil {
+			return nil, err
+		}
+		if stmt != nil {
+			defer release()
+			return stmt.EnhancedQueryContext(ctx, args...)
+		}
+	}
+	return client.Exer.EnhancedQueryContext(ctx, sqlText, args...)
+}
+
+// queryRowContext 用于执行单行查询，开启了语句缓存时，使用缓存的预编译语句。
+func (client *AbstractDbClient) queryRowContext(ctx context.Context, sqlText string, args []any) *sqlen.EnhanceRow {
+	return sqlen.NewEnhanceRow(client.queryContext(ctx, sqlText, args))
+}
@@ -1,6 +1,7 @@
 package sqlmer
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 )
@@ -13,12 +14,21 @@ type abstractTransactionKeeper struct {
 	*AbstractDbClient
 	Tx *sql.Tx
 
+	// 开始事务时的选项，用于检查嵌套事务的选项是否与其冲突。
+	opts *sql.TxOptions
+
+	// 用于在事务完结时释放事务的 context。
+	cancelFunc context.CancelFunc
+
 	// 当前事务是否已经完结，若完结则不允许再执行数据库操作。
 	transactionCompleted bool
 
 	// 事务的嵌套层级。TransactionKeeper 接口内嵌了 DbClient，也具有 CreateTransaction 方法。
 	// 刚创建的事务嵌套层级为 0，事务内再次创建事务时 +1，并返回（复用）当前实例。
 	embeddedLevel int
+
+	// 已创建的保存点数量，用于生成保存点名称，仅在开启 WithSavepoint 时使用。
+	savepointSeq int
 }
 
 // Commit 用于提交事务。
@@ -33,6 +43,7 @@ func (transKeeper *abstractTransactionKeeper) Commit() error {
 	}
 
 	transKeeper.transactionCompleted = true
+	defer transKeeper.cancelFunc()
 	return transKeeper.Tx.Commit()
 }
 
@@ -48,6 +59,7 @@ func (transKeeper *abstractTransactionKeeper) Rollback() error {
 	}
 
 	transKeeper.transactionCompleted = true
+	defer transKeeper.cancelFunc()
 	return transKeeper.Tx.Rollback()
 }
 
@@ -61,7 +73,43 @@ func (transKeeper *abstractTransactionKeeper) Close() error {
 }
 
 // CreateTransaction 用于开始一个事务。
+// 若开启了 WithSavepoint ，会在当前事务中设置一个保存点，并返回对应的嵌套事务。
 func (transKeeper *abstractTransactionKeeper) CreateTransaction() (TransactionKeeper, error) {
+	return transKeeper.CreateTransactionContext(context.Background(), nil)
+}
+
+// CreateTransactionContext 用于开始一个事务。
+// 在事务中调用时，会加入当前事务（与 CreateTransaction 一致），opts 与外层事务的设置冲突时返回 ErrTran ，见 checkNestedTxOptions ；
+// ctx 仅在开启 WithSavepoint 时用于设置保存点的语句。
+func (transKeeper *abstractTransactionKeeper) CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (TransactionKeeper, error) {
+	if err := checkNestedTxOptions(transKeeper.opts, opts); err != nil {
+		return nil, err
+	}
+
+	if transKeeper.config.savepointEnabled {
+		return transKeeper.createSavepoint(ctx)
+	}
+
 	transKeeper.embeddedLevel++
 	return transKeeper, nil
 }
+
+// checkNestedTxOptions 用于检查嵌套事务的选项是否与外层事务冲突，嵌套事务加入外层事务，无法使用自己的选项：
+//   - 指定了隔离级别（非 sql.LevelDefault ）的，需要与外层事务的隔离级别一致；
+//   - 要求只读的，外层事务也需要是只读的。
+func checkNestedTxOptions(outer *sql.TxOptions, nested *sql.TxOptions) error {
+	if nested == nil {
+		return nil
+	}
+	if outer == nil {
+		outer = &sql.TxOptions{}
+	}
+
+	if nested.Isolation != sql.LevelDefault && nested.Isolation != outer.Isolation {
+		return fmt.Errorf("%w: nested transaction isolation level %v conflicts with the outer transaction (%v)", ErrTran, nested.Isolation, outer.Isolation)
+	}
+	if nested.ReadOnly && !outer.ReadOnly {
+		return fmt.Errorf("%w: nested read-only transaction conflicts with the outer read-write transaction", ErrTran)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package sqlmer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// SavepointDialect 用于生成不同数据库的保存点语句。
+type SavepointDialect interface {
+	// CreateSavepoint 返回设置保存点的语句。
+	CreateSavepoint(name string) string
+
+	// RollbackToSavepoint 返回回滚到保存点的语句。
+	RollbackToSavepoint(name string) string
+
+	// ReleaseSavepoint 返回释放保存点的语句，若数据库不支持（无须）释放保存点，返回空字符串。
+	ReleaseSavepoint(name string) string
+}
+
+var _ SavepointDialect = StandardSavepointDialect{}
+
+// StandardSavepointDialect 是 SQL 标准的保存点语句，适用于 MySQL 、 SQLite 、 PostgreSQL 等数据库。
+type StandardSavepointDialect struct{}
+
+// CreateSavepoint 返回设置保存点的语句。
+func (StandardSavepointDialect) CreateSavepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// RollbackToSavepoint 返回回滚到保存点的语句。
+func (StandardSavepointDialect) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ReleaseSavepoint 返回释放保存点的语句。
+func (StandardSavepointDialect) ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+var _ DbClient = (*savepointTransactionKeeper)(nil)
+var _ TransactionKeeper = (*savepointTransactionKeeper)(nil)
+
+// savepointTransactionKeeper 是通过保存点实现的嵌套事务。
+type savepointTransactionKeeper struct {
+	*AbstractDbClient
+
+	// 最外层的事务。
+	root *abstractTransactionKeeper
+
+	// 保存点名称。
+	name string
+
+	// 当前嵌套事务是否已经完结（保存点已被释放或回滚）。
+	transactionCompleted bool
+}
+
+// createSavepoint 用于在事务中设置一个保存点，并返回对应的嵌套事务。
+func (transKeeper *abstractTransactionKeeper) createSavepoint(ctx context.Context) (TransactionKeeper, error) {
+	if transKeeper.transactionCompleted {
+		return nil, fmt.Errorf("%w: trans has already completed", ErrTran)
+	}
+
+	transKeeper.savepointSeq++
+	name := "sp_" + strconv.Itoa(transKeeper.savepointSeq)
+	if err := transKeeper.execSavepointSql(ctx, transKeeper.config.savepointDialect.CreateSavepoint(name)); err != nil {
+		return nil, err
+	}
+
+	return &savepointTransactionKeeper{
+		AbstractDbClient: transKeeper.AbstractDbClient,
+		root:             transKeeper,
+		name:             name,
+	}, nil
+}
+
+// execSavepointSql 用于在事务上执行保存点相关的语句，语句为空时不做任何操作。
+func (transKeeper *abstractTransactionKeeper) execSavepointSql(ctx context.Context, sqlText string) error {
+	if sqlText == "" {
+		return nil
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, transKeeper.GetExecTimeout())
+	defer cancelFunc()
+
+	if _, err := transKeeper.Tx.ExecContext(ctx, sqlText); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrTran, sqlText, err)
+	}
+	return nil
+}
+
+// Commit 用于提交嵌套事务，即释放保存点，语句的实际提交依然由最外层事务完成。
+func (transKeeper *savepointTransactionKeeper) Commit() error {
+	if transKeeper.transactionCompleted || transKeeper.root.transactionCompleted {
+		return fmt.Errorf("%w: trans has already completed", ErrTran)
+	}
+
+	transKeeper.transactionCompleted = true
+	return transKeeper.root.execSavepointSql(context.Background(), transKeeper.root.config.savepointDialect.ReleaseSavepoint(transKeeper.name))
+}
+
+// Rollback 用于回滚嵌套事务，即回滚到保存点，之后释放该保存点。
+func (transKeeper *savepointTransactionKeeper) Rollback() error {
+	if transKeeper.transactionCompleted || transKeeper.root.transactionCompleted {
+		return fmt.Errorf("%w: trans has already completed", ErrTran)
+	}
+
+	transKeeper.transactionCompleted = true
+	dialect := transKeeper.root.config.savepointDialect
+	if err := transKeeper.root.execSavepointSql(context.Background(), dialect.RollbackToSavepoint(transKeeper.name)); err != nil {
+		return err
+	}
+	return transKeeper.root.execSavepointSql(context.Background(), dialect.ReleaseSavepoint(transKeeper.name))
+}
+
+// Close 用于优雅关闭嵌套事务，若嵌套事务没有提交，则回滚到保存点。
+func (transKeeper *savepointTransactionKeeper) Close() error {
+	if transKeeper.transactionCompleted || transKeeper.root.transactionCompleted {
+		return nil
+	}
+	return transKeeper.Rollback()
+}
+
+// CreateTransaction 用于在当前事务中再设置一个保存点，并返回对应的嵌套事务。
+func (transKeeper *savepointTransactionKeeper) CreateTransaction() (TransactionKeeper, error) {
+	return transKeeper.CreateTransactionContext(context.Background(), nil)
+}
+
+// CreateTransactionContext 用于在当前事务中再设置一个保存点，并返回对应的嵌套事务。
+// opts 与最外层事务的设置冲突时返回 ErrTran ， ctx 用于设置保存点的语句。
+func (transKeeper *savepointTransactionKeeper) CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (TransactionKeeper, error) {
+	if transKeeper.transactionCompleted {
+		return nil, fmt.Errorf("%w: trans has already completed", ErrTran)
+	}
+	if err := checkNestedTxOptions(transKeeper.root.opts, opts); err != nil {
+		return nil, err
+	}
+	return transKeeper.root.createSavepoint(ctx)
+}
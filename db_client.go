@@ -2,6 +2,7 @@ package sqlmer
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/bunnier/sqlmer/sqlen"
@@ -20,12 +21,35 @@ type DbClient interface {
 	// GetExecTimeout 用于获取当前 DbClient 实例的执行超时时间。
 	GetExecTimeout() time.Duration
 
+	// PingContext 用于检查数据库连接是否可用，必要时会建立连接。
+	PingContext(ctx context.Context) error
+
+	// Stats 用于获取连接池的统计信息。
+	Stats() sql.DBStats
+
+	// Close 用于关闭 DbClient ，不再允许开始新的事务，并在进行中的事务完结后关闭连接池。
+	// 等待的时限由 WithContext 设置的 context 控制。在 TransactionKeeper 上调用时，关闭的是事务本身。
+	Close() error
+
 	// CreateTransaction 用于开始一个事务。
 	// returns:
 	//  @tran 返回一个实现了 TransactionKeeper（内嵌 DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
 	//  @err 创建事务时遇到的错误。
 	CreateTransaction() (tran TransactionKeeper, err error)
 
+	// CreateTransactionContext 用于开始一个事务。在事务中调用时，会加入当前事务，ctx 沿用外层事务的设置，
+	// opts 无法单独生效，与外层事务的设置冲突（隔离级别不同，或要求只读而外层事务不是只读）时返回错误。
+	// params:
+	//  @ctx 事务的 context，在事务提交或回滚前一直有效，若 ctx 被取消，事务将被回滚。
+	//  @opts 事务选项，用于指定隔离级别及是否只读，为 nil 时使用驱动的默认值。
+	// returns:
+	//  @tran 返回一个实现了 TransactionKeeper（内嵌 DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
+	//  @err 创建事务时遇到的错误。
+	// 可以通过 errors.Is 判断的特殊 err：
+	//  - sqlmer.ErrConnect: 当获取连接并开始事务的过程超过 GetConnTimeout 时返回该类错误。
+	//  - sqlmer.ErrTran: 在事务中调用，且 opts 与外层事务的设置冲突时返回该类错误。
+	CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (tran TransactionKeeper, err error)
+
 	// Execute 用于执行非查询SQL语句，并返回所影响的行数。
 	// params:
 	//  @sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
@@ -53,6 +77,33 @@ type DbClient interface {
 	//  - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 	ExecuteContext(ctx context.Context, sqlText string, args ...any) (rowsEffected int64, err error)
 
+	// Insert 用于执行插入语句，并返回新插入行的自增 id 。
+	// params:
+	//  @sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+	//  @args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+	// returns:
+	//  @lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+	//  @err 执行语句时遇到的错误。
+	// 可以通过 errors.Is 判断的特殊 err：
+	//  - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+	//  - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+	//  - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+	Insert(sqlText string, args ...any) (lastInsertId int64, err error)
+
+	// InsertContext 用于执行插入语句，并返回新插入行的自增 id 。
+	// params:
+	//  @ctx context。
+	//  @sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+	//  @args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+	// returns:
+	//  @lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+	//  @err 执行语句时遇到的错误。
+	// 可以通过 errors.Is 判断的特殊 err：
+	//  - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+	//  - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+	//  - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+	InsertContext(ctx context.Context, sqlText string, args ...any) (lastInsertId int64, err error)
+
 	// SizedExecute 用于执行非查询SQL语句，并断言所影响的行数。
 	// params:
 	//  @expectedSize 预期的影响行数，当
@@ -247,3 +298,41 @@ type TransactionKeeper interface {
 	// Close 用于优雅关闭事务，创建事务后可 defer 执行本方法。
 	Close() error
 }
+
+// RetryableErrorClassifier 用于判断错误是否为可以通过重试整个事务解决的错误（如死锁、序列化失败、数据库繁忙等）。
+// AbstractDbClient 实现了该接口，具体的判断逻辑由各驱动通过 WithRetryableErrorFunc 注入。
+type RetryableErrorClassifier interface {
+	// IsRetryableError 用于判断给定的错误是否可以通过重试事务解决。
+	IsRetryableError(err error) bool
+}
+
+// ParamLimitProvider 用于获取单条语句允许使用的最大参数个数及插入行数，用于如 DbClientEx.BatchInsert 这样需要对参数分批的场景。
+// AbstractDbClient 实现了该接口，具体的值由各驱动通过 WithMaxParamCount 、 WithMaxInsertRows 注入。
+type ParamLimitProvider interface {
+	// MaxParamCount 返回单条语句允许使用的最大参数个数，返回 0 表示未知。
+	MaxParamCount() int
+
+	// MaxInsertRows 返回单条 INSERT ... VALUES 语句允许插入的最大行数，返回 0 表示不限制。
+	MaxInsertRows() int
+}
+
+// StmtPreparer 用于创建支持命名参数的预编译语句，适用于同一语句需要反复执行的场景。
+// AbstractDbClient 实现了该接口，在事务中调用时，预编译语句绑定在该事务上。
+type StmtPreparer interface {
+	// Prepare 用于创建一个支持命名参数的预编译语句，使用完毕后需要调用 Stmt.Close 释放。
+	Prepare(ctx context.Context, sqlText string) (*Stmt, error)
+}
+
+// ScriptExecutor 用于执行包含多条语句的脚本（如建表、初始化数据的脚本）。
+// AbstractDbClient 实现了该接口，脚本的拆分规则由各驱动通过 WithSplitScriptFunc 注入。
+type ScriptExecutor interface {
+	// ExecuteScript 用于将脚本拆分为语句后逐条执行，返回每条执行成功的语句的结果。
+	ExecuteScript(ctx context.Context, script string, options ...ScriptOption) ([]ScriptResult, error)
+}
+
+// TransactionRetryReporter 用于接收 DbClientEx.Transaction 重试事务的通知。
+// DbClient 实现该接口时，每次事务因可重试的错误失败、即将重试前，都会调用 ReportTransactionRetry 。
+type TransactionRetryReporter interface {
+	// ReportTransactionRetry 用于报告一次失败的事务尝试， attempt 为失败的尝试次数（从 1 开始）， err 为导致失败的错误。
+	ReportTransactionRetry(attempt int, err error)
+}
@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/bunnier/sqlmer/internal/sqlscript"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 	"github.com/bunnier/sqlmer/sqlen"
 )
 
@@ -19,9 +21,34 @@ type DbClientConfig struct {
 	Dsn    string  // 连接字符串。
 	Db     *sql.DB // 数据库对象。
 
-	bindArgsFunc      BindSqlArgsFunc       // 用于处理 sql 语句和所给的参数。
-	getScanTypeFunc   sqlen.GetScanTypeFunc // 用于根据列信息获取用于 Scan 的类型。
-	unifyDataTypeFunc sqlen.UnifyDataTypeFn // 用于统一不同驱动在 Go 中的映射类型。
+	// 连接池设置，为 nil 表示未指定，沿用 sql.DB 的默认值（或 WithDb 传入的连接池上已有的设置）。
+	maxOpenConns    *int
+	maxIdleConns    *int
+	connMaxIdleTime *time.Duration
+	connMaxLifetime *time.Duration
+
+	bindArgsFunc        BindSqlArgsFunc       // 用于处理 sql 语句和所给的参数。
+	driverBindArgsFunc  BindSqlArgsFunc       // 驱动的参数绑定函数，不含参数合并逻辑。
+	parseParamNamesFunc ParseParamNamesFunc   // 用于解析 sql 语句中用到的命名参数名称。
+	getScanTypeFunc     sqlen.GetScanTypeFunc // 用于根据列信息获取用于 Scan 的类型。
+	unifyDataTypeFunc   sqlen.UnifyDataTypeFn // 用于统一不同驱动在 Go 中的映射类型。
+
+	savepointEnabled bool             // 嵌套事务是否通过保存点实现。
+	savepointDialect SavepointDialect // 用于生成不同数据库的保存点语句。
+
+	insertIdDialect InsertIdDialect // 用于定制 Insert 获取自增 id 的方式。
+	maxParamCount   int             // 单条语句允许使用的最大参数个数， 0 表示未知。
+	maxInsertRows   int             // 单条 INSERT ... VALUES 语句允许插入的最大行数， 0 表示不限制。
+
+	splitScriptFunc SplitScriptFunc // 用于将 ExecuteScript 的脚本拆分为语句。
+
+	stmtCacheCapacity int // 预编译语句缓存的容量， 0 表示不开启。
+
+	contextTxEnabled bool // *Context 版本的方法是否使用 context 中通过 WithTx 携带的事务。
+
+	observers []ObserverFunc // 语句执行的观察函数。
+
+	isRetryableErrorFunc IsRetryableErrorFunc // 用于判断错误是否可以通过重试事务解决。
 }
 
 // NewDbClientConfig 创建一个数据库连接配置。
@@ -41,6 +68,24 @@ func NewDbClientConfig(options ...DbClientOption) (*DbClientConfig, error) {
 			return columnType.ScanType()
 		},
 		unifyDataTypeFunc: func(columnType *sql.ColumnType, dest *any) {},
+		parseParamNamesFunc: func(sqlText string) []string {
+			names := make([]string, 0, 10)
+			for _, token := range sqltoken.Tokenize(sqlText, sqltoken.Standard) {
+				if token.Kind == sqltoken.NamedParam {
+					names = append(names, token.Name())
+				}
+			}
+			return names
+		},
+		savepointEnabled: false,
+		savepointDialect: StandardSavepointDialect{},
+		insertIdDialect:  LastInsertIdDialect{},
+		splitScriptFunc: func(script string) []string {
+			return sqlscript.Split(script, sqlscript.Standard)
+		},
+		isRetryableErrorFunc: func(err error) bool {
+			return false
+		},
 	}
 
 	var err error
@@ -52,7 +97,14 @@ func NewDbClientConfig(options ...DbClientOption) (*DbClientConfig, error) {
 
 	// 为 bindArgsFunc 注入参数合并逻辑。
 	oriBindArgsFunc := config.bindArgsFunc
+	config.driverBindArgsFunc = oriBindArgsFunc
 	config.bindArgsFunc = func(s string, i ...any) (string, []any, error) {
+		if len(i) == 1 {
+			if args, ok := i[0].(positionalArgs); ok { // 按位置绑定的参数，不做合并及切片展开。
+				return bindPositionalArgs(oriBindArgsFunc, s, args)
+			}
+		}
+
 		i, err := preHandleArgs(i...) // 进行 结构体/map/索引 等各种参数的合并处理。
 		if err != nil {
 			return "", nil, err
@@ -83,6 +135,72 @@ func WithConnTimeout(timeout time.Duration) DbClientOption {
 	}
 }
 
+// WithContext 用于为 DbClientConfig 设置上下文对象（默认为 context.Background() ）。
+// DbClient.Close 等待进行中的事务完结时，最多等待到该 context 结束。
+func WithContext(ctx context.Context) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.context = ctx
+		return nil
+	}
+}
+
+// WithMaxOpenConns 用于设置连接池的最大连接数，小于等于 0 表示不限制，见 sql.DB.SetMaxOpenConns 。
+func WithMaxOpenConns(n int) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.maxOpenConns = &n
+		return nil
+	}
+}
+
+// WithMaxIdleConns 用于设置连接池的最大空闲连接数，小于等于 0 表示不保留空闲连接，见 sql.DB.SetMaxIdleConns 。
+func WithMaxIdleConns(n int) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.maxIdleConns = &n
+		return nil
+	}
+}
+
+// WithConnMaxIdleTime 用于设置连接的最大空闲时间，小于等于 0 表示不限制，见 sql.DB.SetConnMaxIdleTime 。
+func WithConnMaxIdleTime(d time.Duration) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.connMaxIdleTime = &d
+		return nil
+	}
+}
+
+// WithConnMaxLifetime 用于设置连接的最大生命周期，小于等于 0 表示不限制，见 sql.DB.SetConnMaxLifetime 。
+// 对于 MySQL ，未指定时会根据数据库的 wait_timeout 自动设置。
+func WithConnMaxLifetime(d time.Duration) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.connMaxLifetime = &d
+		return nil
+	}
+}
+
+// ConnMaxLifetime 用于获取通过 WithConnMaxLifetime 指定的连接最大生命周期， ok 为 false 表示未指定。
+func (config *DbClientConfig) ConnMaxLifetime() (d time.Duration, ok bool) {
+	if config.connMaxLifetime == nil {
+		return 0, false
+	}
+	return *config.connMaxLifetime, true
+}
+
+// applyPoolSettings 用于将指定了的连接池设置应用到 db 上。
+func (config *DbClientConfig) applyPoolSettings(db *sql.DB) {
+	if config.maxOpenConns != nil {
+		db.SetMaxOpenConns(*config.maxOpenConns)
+	}
+	if config.maxIdleConns != nil {
+		db.SetMaxIdleConns(*config.maxIdleConns)
+	}
+	if config.connMaxIdleTime != nil {
+		db.SetConnMaxIdleTime(*config.connMaxIdleTime)
+	}
+	if config.connMaxLifetime != nil {
+		db.SetConnMaxLifetime(*config.connMaxLifetime)
+	}
+}
+
 // WithDb 用于用现有的 sql.DB 初始化 DbClientOption。
 func WithDb(db *sql.DB, driver string, dsn string) DbClientOption {
 	return func(config *DbClientConfig) error {
@@ -129,6 +247,18 @@ func WithBindArgsFunc(argsFunc BindSqlArgsFunc) DbClientOption {
 	}
 }
 
+// ParseParamNamesFunc 定义用于解析 sql 语句中用到的命名参数名称（按出现顺序，同名参数出现多次时有多项）的函数。
+type ParseParamNamesFunc func(sqlText string) []string
+
+// WithParseParamNamesFunc 用于为 DbClient 注入驱动相关的命名参数解析逻辑，默认按标准 SQL 的词法识别 @name 。
+// 通过 Prepare 创建的预编译语句在创建时用它一次性解析语句中的参数，之后的执行直接按参数名取值绑定。
+func WithParseParamNamesFunc(parseParamNames ParseParamNamesFunc) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.parseParamNamesFunc = parseParamNames
+		return nil
+	}
+}
+
 // WithBindArgsFunc 用于为 DbClientConfig 设置根据列信息获取 Scan 类型的函数。
 func WithGetScanTypeFunc(scanFunc sqlen.GetScanTypeFunc) DbClientOption {
 	return func(config *DbClientConfig) error {
@@ -136,3 +266,100 @@ func WithGetScanTypeFunc(scanFunc sqlen.GetScanTypeFunc) DbClientOption {
 		return nil
 	}
 }
+
+// WithSavepoint 用于选择嵌套事务是否通过保存点（SAVEPOINT）实现（默认为 false）。
+// 开启后，在事务中再次创建的事务会设置一个保存点：内层事务回滚时回滚到该保存点，提交时释放该保存点；
+// 未开启时，内层事务的提交与回滚均不做任何操作，交由最外层事务处理。
+func WithSavepoint(enabled bool) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.savepointEnabled = enabled
+		return nil
+	}
+}
+
+// WithSavepointDialect 用于为 DbClient 注入驱动相关的保存点语句生成逻辑。
+func WithSavepointDialect(dialect SavepointDialect) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.savepointDialect = dialect
+		return nil
+	}
+}
+
+// WithInsertIdDialect 用于为 DbClient 注入驱动相关的获取自增 id 的逻辑。
+func WithInsertIdDialect(dialect InsertIdDialect) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.insertIdDialect = dialect
+		return nil
+	}
+}
+
+// WithMaxParamCount 用于设置单条语句允许使用的最大参数个数（默认为 0 ，表示未知），用于 DbClientEx.BatchInsert 等需要对参数分批的场景。
+func WithMaxParamCount(count int) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.maxParamCount = count
+		return nil
+	}
+}
+
+// WithMaxInsertRows 用于设置单条 INSERT ... VALUES 语句允许插入的最大行数（默认为 0 ，表示不限制），用于 DbClientEx.BatchInsert 分批。
+func WithMaxInsertRows(count int) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.maxInsertRows = count
+		return nil
+	}
+}
+
+// WithStmtCache 用于开启预编译语句缓存（默认不开启）， capacity 为缓存的语句数量上限。
+// 开启后，语句按绑定参数后的 SQL 缓存预编译的 sql.Stmt ，重复执行时跳过数据库端的语句解析，缓存满时淘汰最久未使用的语句；
+// 在事务中执行时，缓存的语句通过 tx.StmtContext 绑定到事务上，未命中的语句直接执行，不加入缓存。
+// 命中情况可以通过 StmtCacheStatsProvider 获取。
+// 注意：IN 子句中切片参数的长度不同，会得到不同的 SQL ，分别占用缓存。
+func WithStmtCache(capacity int) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.stmtCacheCapacity = capacity
+		return nil
+	}
+}
+
+// WithContextTx 用于选择 *Context 版本的方法是否使用 context 中通过 WithTx 携带的事务（默认为 false）。
+// 开启后， context 中带有事务时，语句在该事务上执行而不是连接池上，CreateTransactionContext 则加入该事务。
+// 注意：context 中的事务需要来自同一个数据库；不带 context 的方法（如 Execute ）不受影响。
+func WithContextTx(enabled bool) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.contextTxEnabled = enabled
+		return nil
+	}
+}
+
+// WithObserver 用于添加语句执行的观察函数，可以多次使用以添加多个。
+// 观察函数在每条语句执行后调用，可以拿到参数绑定后实际执行的 SQL 和参数、耗时及执行结果，适用于慢日志、监控指标等场景。
+// 注意：通过 Prepare 创建的预编译语句上的执行不会通知观察函数。
+func WithObserver(observer ObserverFunc) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.observers = append(config.observers, observer)
+		return nil
+	}
+}
+
+// SplitScriptFunc 定义用于将包含多条语句的脚本拆分为语句的函数，返回的语句会被逐条发送给数据库。
+type SplitScriptFunc func(script string) []string
+
+// WithSplitScriptFunc 用于为 DbClient 注入驱动相关的脚本拆分逻辑（如 SQL Server 的 GO 、 MySQL 的 DELIMITER ），
+// 默认按 ; 拆分。
+func WithSplitScriptFunc(splitScript SplitScriptFunc) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.splitScriptFunc = splitScript
+		return nil
+	}
+}
+
+// IsRetryableErrorFunc 定义用于判断错误是否可以通过重试事务解决的函数。
+type IsRetryableErrorFunc func(err error) bool
+
+// WithRetryableErrorFunc 用于为 DbClient 注入驱动相关的可重试错误（如死锁、序列化失败等）判断逻辑。
+func WithRetryableErrorFunc(isRetryableError IsRetryableErrorFunc) DbClientOption {
+	return func(config *DbClientConfig) error {
+		config.isRetryableErrorFunc = isRetryableError
+		return nil
+	}
+}
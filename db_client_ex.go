@@ -3,7 +3,9 @@ package sqlmer
 import (
 	"io"
 	"reflect"
+	"sync"
 
+	"github.com/bunnier/sqlmer/sqlen"
 	"github.com/cmstar/go-conv"
 )
 
@@ -19,18 +21,8 @@ var _ DbClient = (*DbClientEx)(nil)
 
 // Extend 加强 DbClient ，提供强类型的转化方法。
 func Extend(raw DbClient) *DbClientEx {
-	// 提供 mysql 的 snake_case 名称的字段到 Go 的 CamelCase 字段的匹配。
-	dbConv := conv.Conv{
-		Conf: conv.Config{
-			FieldMatcherCreator: &conv.SimpleMatcherCreator{
-				Conf: conv.SimpleMatcherConfig{
-					Tag:            "conv",
-					CamelSnakeCase: true,
-				},
-			},
-		},
-	}
-	return &DbClientEx{raw, dbConv}
+	// 每个实例使用独立的 conv.Conv ，修改其配置不会影响其它实例；行到结构体的映射计划按字段匹配规则共享缓存，见 getStructMapper 。
+	return &DbClientEx{DbClient: raw, Conv: newDbConv()}
 }
 
 // GetStruct 获取一行的查询结果，转化并填充到 ptr 。 ptr 必须是 struct 类型的指针。
@@ -391,48 +383,140 @@ func (c *DbClientEx) ListType(elemTyp reflect.Type, query string, args ...any) (
 	}
 	defer rows.Close() // This error is ignored.
 
+	vList, err := scanList(rows, &c.Conv, elemTyp)
+	if err != nil {
+		return nil, err
+	}
+	return vList.Interface(), nil
+}
+
+// scanList 将游标中的每一行转换到 elemTyp 类型，返回转换后的元素的列表（ reflect.Value 形式的 slice ）。
+func scanList(rows *sqlen.EnhanceRows, converter *conv.Conv, elemTyp reflect.Type) (reflect.Value, error) {
+	scanRow := newRowScanner(converter, elemTyp)
+	vList := reflect.MakeSlice(reflect.SliceOf(elemTyp), 0, 0)
+
+	for rows.Next() {
+		item, err := scanRow(rows)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		vList = reflect.Append(vList, item)
+	}
+
+	err := rows.Err()
+	if err != nil && err != io.EOF {
+		return reflect.Value{}, err
+	}
+	return vList, nil
+}
+
+// rowScanner 用于将游标的当前行转换到目标类型。
+type rowScanner func(rows *sqlen.EnhanceRows) (reflect.Value, error)
+
+// newRowScanner 用于创建将游标的当前行转换到 elemTyp 类型的 rowScanner 。
+func newRowScanner(converter *conv.Conv, elemTyp reflect.Type) rowScanner {
 	underTyp := elemTyp
 	for underTyp.Kind() == reflect.Ptr {
 		underTyp = underTyp.Elem()
 	}
 	complex := !conv.IsSimpleType(underTyp)
-	vList := reflect.MakeSlice(reflect.SliceOf(elemTyp), 0, 0)
 
-	for rows.Next() {
+	// 目标类型是结构体的，直接将行填充到结构体字段，映射计划由 StructMapper 缓存。
+	if complex && underTyp.Kind() == reflect.Struct {
+		mapper := getStructMapper(converter)
+		return func(rows *sqlen.EnhanceRows) (reflect.Value, error) {
+			ptr := reflect.New(underTyp)
+			if err := rows.StructScan(mapper, converter.ConvertType, ptr.Interface()); err != nil {
+				return reflect.Value{}, err
+			}
+
+			// 元素类型是（多级）指针的，逐级取地址。
+			item := ptr
+			if elemTyp == underTyp {
+				item = ptr.Elem()
+			}
+			for item.Type() != elemTyp {
+				p := reflect.New(item.Type())
+				p.Elem().Set(item)
+				item = p
+			}
+			return item, nil
+		}
+	}
+
+	return func(rows *sqlen.EnhanceRows) (reflect.Value, error) {
 		var row any
 
-		// 目标类型是复杂类型的，统一（目前也只能）将行转到 map ，再从 map 转换。
+		// 其余复杂类型（如 map ），将行转到 map ，再从 map 转换。
 		// 非复杂类型（就是数字、字符串这些），则从每一行的第一列的值转换。
-		// 未来， conv 库应该支持类似字段迭代器的功能，就可以不走 map 了，节省一层转换开销。
 		if complex {
 			m, err := rows.MapScan()
 			if err != nil {
-				return nil, err
+				return reflect.Value{}, err
 			}
 			row = m
 		} else {
 			vals, err := rows.SliceScan()
 			if err != nil {
-				return nil, err
+				return reflect.Value{}, err
 			}
 			row = vals[0]
 		}
 
-		item, err := c.Conv.ConvertType(row, elemTyp)
+		item, err := converter.ConvertType(row, elemTyp)
 		if err != nil {
-			return nil, err
+			return reflect.Value{}, err
 		}
 
-		vList = reflect.Append(vList, reflect.ValueOf(item))
+		// ConvertType 对 nil 的结果（如 null 转到指针类型）返回 nil ，需转为目标类型的零值。
+		if item == nil {
+			return reflect.Zero(elemTyp), nil
+		}
+		return reflect.ValueOf(item), nil
 	}
+}
 
-	err = rows.Err()
-	if err != nil && err != io.EOF {
-		return nil, err
+// structMappers 缓存各字段匹配规则对应的 StructMapper ，使映射计划可以在 DbClientEx 实例（包括事务）间共享。
+// 键为 conv.SimpleMatcherConfig （ SimpleMatcherCreator 按配置共享）或其它 FieldMatcherCreator 本身。
+var structMappers sync.Map // conv.SimpleMatcherConfig | conv.FieldMatcherCreator -> *sqlen.StructMapper
+
+// getStructMapper 用于获取与给定的 conv.Conv 使用相同字段匹配规则的 StructMapper 。
+func getStructMapper(converter *conv.Conv) *sqlen.StructMapper {
+	creator := converter.Conf.FieldMatcherCreator
+	if creator == nil {
+		creator = defaultFieldMatcherCreator // 和 conv 一致，未指定时使用默认的 SimpleMatcherCreator 。
 	}
-	return vList.Interface(), nil
+
+	// SimpleMatcherCreator 的匹配结果只取决于其配置，按配置的副本缓存，
+	// 这样各实例可以共享映射计划，之后修改某个实例的配置也不会影响其它实例。
+	var key any = creator
+	if simpleCreator, ok := creator.(*conv.SimpleMatcherCreator); ok {
+		key = simpleCreator.Conf
+	}
+
+	newMapper := func() *sqlen.StructMapper {
+		if simpleCreator, ok := creator.(*conv.SimpleMatcherCreator); ok {
+			creator = &conv.SimpleMatcherCreator{Conf: simpleCreator.Conf}
+		}
+		return sqlen.NewStructMapper(func(structType reflect.Type, columnName string) (reflect.StructField, bool) {
+			return creator.GetMatcher(structType).MatchField(columnName)
+		})
+	}
+
+	// 无法作为 map key 的 creator 不做缓存。
+	if !reflect.TypeOf(key).Comparable() {
+		return newMapper()
+	}
+
+	if mapper, ok := structMappers.Load(key); ok {
+		return mapper.(*sqlen.StructMapper)
+	}
+	mapper, _ := structMappers.LoadOrStore(key, newMapper())
+	return mapper.(*sqlen.StructMapper)
 }
 
+var defaultFieldMatcherCreator conv.FieldMatcherCreator = new(conv.SimpleMatcherCreator)
+
 // MustListType 类似 ListType ，但出现错误时不返回 error ，而是 panic 。
 //
 // 注意，给定的 elemTyp 是元素的类型，返回的则是该元素的 slice ：
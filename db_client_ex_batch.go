@@ -0,0 +1,177 @@
+package sqlmer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxParamCount 是 DbClient 没有提供单条语句最大参数个数时使用的默认值（ SQLite 3.32.0 之前的默认限制）。
+const defaultMaxParamCount = 999
+
+// BatchInsert 用于将多行数据批量插入到表中，返回总共影响的行数。
+// 数据会按数据库单条语句的最大参数个数及插入行数（见 ParamLimitProvider ）分批，拼接为 INSERT INTO ... VALUES (...),(...) 语句，
+// 所有批次在同一个事务中执行，任一批次失败时，整体回滚。
+// 行中的值按位置绑定，原样作为参数值使用：切片不会作为 IN 参数展开，结构体也不会作为命名参数的来源。
+// params:
+//
+//	@ctx context。
+//	@table 表名，会原样拼接到语句中，需要转义的，由调用方自行转义。
+//	@columns 列名，会原样拼接到语句中，需要转义的，由调用方自行转义。
+//	@rows 要插入的数据，支持两种形式：
+//	  - [][]any ：每个元素是一行，其中的值按顺序和 columns 一一对应；
+//	  - 结构体（或其指针）的 slice ：按 columns 匹配结构体的字段，匹配规则同 Conv 的字段匹配（默认支持 conv 标签及驼峰、下划线的模糊匹配）。
+//
+// returns:
+//
+//	@rowsEffected 所有批次影响的行数之和。
+//	@err 执行过程中遇到的错误。
+func (c *DbClientEx) BatchInsert(ctx context.Context, table string, columns []string, rows any) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("dbClient: batch insert into %s: columns must not be empty", table)
+	}
+
+	values, err := c.batchInsertValues(columns, rows)
+	if err != nil {
+		return 0, fmt.Errorf("dbClient: batch insert into %s: %w", table, err)
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	maxParamCount, maxInsertRows := defaultMaxParamCount, 0
+	if provider, ok := c.DbClient.(ParamLimitProvider); ok {
+		if provider.MaxParamCount() > 0 {
+			maxParamCount = provider.MaxParamCount()
+		}
+		maxInsertRows = provider.MaxInsertRows()
+	}
+	batchSize := maxParamCount / len(columns)
+	if batchSize == 0 {
+		return 0, fmt.Errorf("dbClient: batch insert into %s: %d columns exceed the max param count %d", table, len(columns), maxParamCount)
+	}
+	if maxInsertRows > 0 {
+		batchSize = min(batchSize, maxInsertRows)
+	}
+
+	var rowsEffected int64
+	err = c.Transaction(ctx, func(tx *TransactionKeeperEx) error {
+		rowsEffected = 0 // 事务可能被重试，需要重新计数。
+
+		var sqlText string
+		for start := 0; start < len(values); start += batchSize {
+			batch := values[start:min(start+batchSize, len(values))]
+
+			// 除最后一批外，每批的行数相同，语句可以复用。
+			if sqlText == "" || len(batch) < batchSize {
+				sqlText = buildBatchInsertSql(table, columns, len(batch))
+			}
+
+			args := make(positionalArgs, 0, len(batch)*len(columns))
+			for _, row := range batch {
+				args = append(args, row...)
+			}
+
+			effected, err := tx.ExecuteContext(ctx, sqlText, args)
+			if err != nil {
+				return err
+			}
+			rowsEffected += effected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rowsEffected, nil
+}
+
+// buildBatchInsertSql 用于生成插入 rowCount 行数据的语句，参数使用 @p1...@pn 的索引占位符。
+func buildBatchInsertSql(table string, columns []string, rowCount int) string {
+	var sb strings.Builder
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(table)
+	sb.WriteString(" (")
+	sb.WriteString(strings.Join(columns, ", "))
+	sb.WriteString(") VALUES ")
+
+	paramIndex := 0
+	for i := 0; i < rowCount; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('(')
+		for j := range columns {
+			if j > 0 {
+				sb.WriteByte(',')
+			}
+			paramIndex++
+			sb.WriteString("@p")
+			sb.WriteString(strconv.Itoa(paramIndex))
+		}
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
+
+// batchInsertValues 用于将 BatchInsert 的 rows 参数统一转换为按 columns 排列的值。
+func (c *DbClientEx) batchInsertValues(columns []string, rows any) ([][]any, error) {
+	if rowSlice, ok := rows.([][]any); ok {
+		for i, row := range rowSlice {
+			if len(row) != len(columns) {
+				return nil, fmt.Errorf("row %d has %d values, want %d", i, len(row), len(columns))
+			}
+		}
+		return rowSlice, nil
+	}
+
+	rowsVal := reflect.ValueOf(rows)
+	if rowsVal.Kind() != reflect.Slice && rowsVal.Kind() != reflect.Array {
+		return nil, fmt.Errorf("rows must be [][]any or a slice of struct, got %T", rows)
+	}
+
+	structTyp := rowsVal.Type().Elem()
+	if structTyp.Kind() == reflect.Ptr {
+		structTyp = structTyp.Elem()
+	}
+	if structTyp.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rows must be [][]any or a slice of struct, got %T", rows)
+	}
+
+	// 按列名匹配字段，得到各列对应的字段路径。
+	creator := c.Conv.Conf.FieldMatcherCreator
+	if creator == nil {
+		creator = defaultFieldMatcherCreator
+	}
+	matcher := creator.GetMatcher(structTyp)
+	fieldIndexes := make([][]int, len(columns))
+	for i, column := range columns {
+		field, ok := matcher.MatchField(column)
+		if !ok {
+			return nil, fmt.Errorf("no field of %v matches column %s", structTyp, column)
+		}
+		fieldIndexes[i] = field.Index
+	}
+
+	values := make([][]any, 0, rowsVal.Len())
+	for i := 0; i < rowsVal.Len(); i++ {
+		rowVal := rowsVal.Index(i)
+		if rowVal.Kind() == reflect.Ptr {
+			if rowVal.IsNil() {
+				return nil, fmt.Errorf("row %d is nil", i)
+			}
+			rowVal = rowVal.Elem()
+		}
+
+		row := make([]any, len(columns))
+		for j, index := range fieldIndexes {
+			if fieldVal, err := rowVal.FieldByIndexErr(index); err == nil {
+				row[j] = fieldVal.Interface()
+			} // 字段所在的嵌入结构体指针为空时，值为 nil 。
+		}
+		values = append(values, row)
+	}
+	return values, nil
+}
@@ -2,6 +2,7 @@ package sqlmer
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/bunnier/sqlmer/sqlen"
 )
@@ -14,6 +15,14 @@ type MustDbClient interface {
 	//	@tran 返回一个TransactionKeeperEx 实例（实现了 TransactionKeeper、DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
 	MustCreateTransaction() (tran *TransactionKeeperEx)
 
+	// MustCreateTransactionContext 用于开始一个事务。在事务中调用时，会加入当前事务，ctx 和 opts 沿用外层事务的设置。
+	// params:
+	//  @ctx 事务的 context，在事务提交或回滚前一直有效，若 ctx 被取消，事务将被回滚。
+	//  @opts 事务选项，用于指定隔离级别及是否只读，为 nil 时使用驱动的默认值。
+	// returns:
+	//	@tran 返回一个TransactionKeeperEx 实例（实现了 TransactionKeeper、DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
+	MustCreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (tran *TransactionKeeperEx)
+
 	// MustExecute 用于执行非查询 sql 语句，并返回所影响的行数。
 	// params:
 	//  @sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
@@ -39,6 +48,31 @@ type MustDbClient interface {
 	//  - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 	MustExecuteContext(context context.Context, sqlText string, args ...any) (rowsEffected int64)
 
+	// MustInsert 用于执行插入语句，并返回新插入行的自增 id 。
+	// params:
+	//  @sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+	//  @args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+	// returns:
+	//  @lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+	// 可能 panic 出的 error （可以通过 errors.Is 判断）：
+	//  - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+	//  - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+	//  - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+	MustInsert(sqlText string, args ...any) (lastInsertId int64)
+
+	// MustInsertContext 用于执行插入语句，并返回新插入行的自增 id 。
+	// params:
+	//  @ctx context。
+	//  @sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+	//  @args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+	// returns:
+	//  @lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+	// 可能 panic 出的 error （可以通过 errors.Is 判断）：
+	//  - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+	//  - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+	//  - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+	MustInsertContext(context context.Context, sqlText string, args ...any) (lastInsertId int64)
+
 	// MustSizedExecute 用于执行非查询 sql 语句，并断言所影响的行数。
 	// params:
 	//  @expectedSize 预期的影响行数，当
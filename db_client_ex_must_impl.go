@@ -26,6 +26,23 @@ func (client *DbClientEx) MustCreateTransaction() *TransactionKeeperEx {
 	}
 }
 
+// MustCreateTransactionContext 用于开始一个事务。在事务中调用时，会加入当前事务，ctx 和 opts 沿用外层事务的设置。
+// params:
+//
+//	@ctx 事务的 context，在事务提交或回滚前一直有效，若 ctx 被取消，事务将被回滚。
+//	@opts 事务选项，用于指定隔离级别及是否只读，为 nil 时使用驱动的默认值。
+//
+// returns:
+//
+//	@tran 返回一个TransactionKeeperEx 实例（实现了 TransactionKeeper、DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
+func (client *DbClientEx) MustCreateTransactionContext(ctx context.Context, opts *sql.TxOptions) *TransactionKeeperEx {
+	if tx, err := client.CreateTransactionContext(ctx, opts); err != nil {
+		panic(err)
+	} else {
+		return ExtendTx(tx)
+	}
+}
+
 // MustExecute 用于执行非查询 sql 语句，并返回所影响的行数。
 // params:
 //
@@ -68,6 +85,48 @@ func (client *DbClientEx) MustExecuteContext(ctx context.Context, sqlText string
 	}
 }
 
+// MustInsert 用于执行插入语句，并返回新插入行的自增 id 。
+// params:
+//
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//	@args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+//
+// returns:
+//
+//	@lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+//
+// 可能 panic 出的 error （可以通过 errors.Is 判断）：
+//   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+//   - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+//   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+func (client *DbClientEx) MustInsert(sqlText string, args ...any) int64 {
+	ctx, _ := client.getExecTimeoutContext()
+	return client.MustInsertContext(ctx, sqlText, args...)
+}
+
+// MustInsertContext 用于执行插入语句，并返回新插入行的自增 id 。
+// params:
+//
+//	@ctx context。
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//	@args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+//
+// returns:
+//
+//	@lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+//
+// 可能 panic 出的 error （可以通过 errors.Is 判断）：
+//   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+//   - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+//   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+func (client *DbClientEx) MustInsertContext(ctx context.Context, sqlText string, args ...any) int64 {
+	if id, err := client.InsertContext(ctx, sqlText, args...); err != nil {
+		panic(err)
+	} else {
+		return id
+	}
+}
+
 // MustSizedExecute 用于执行非查询 sql 语句，并断言所影响的行数。
 // params:
 //
@@ -1,11 +1,17 @@
 package sqlmer_test
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/bunnier/sqlmer"
+	"github.com/cmstar/go-conv"
 )
 
 func getSqliteClientExForTest(t *testing.T) *sqlmer.DbClientEx {
@@ -965,6 +971,41 @@ func TestDbClientEx_ListOf(t *testing.T) {
 	})
 }
 
+func TestExtend_ConvPerClient(t *testing.T) {
+	raw := newSqliteDbClientForAbstractDbTest(t)
+	c1 := sqlmer.Extend(raw)
+	c2 := sqlmer.Extend(raw)
+	c2.Conv.Conf.FieldMatcherCreator.(*conv.SimpleMatcherCreator).Conf.Tag = "db"
+
+	type row struct {
+		Id    int
+		Value int `db:"number"`
+	}
+	const query = "SELECT id, intTest AS number FROM go_TypeTest WHERE id=1"
+
+	// 交替使用两个实例，各自的字段匹配规则互不影响。
+	for _, tc := range []struct {
+		client *sqlmer.DbClientEx
+		want   int
+	}{{c1, 0}, {c2, 1}, {c1, 0}} {
+		res, err := tc.client.ListOf(row{}, query)
+		if err != nil {
+			t.Fatalf("ListOf() error = %v", err)
+		}
+		if list := res.([]row); len(list) != 1 || list[0].Value != tc.want {
+			t.Fatalf("ListOf() = %v, want Value = %d", list, tc.want)
+		}
+
+		var r row
+		if _, err := tc.client.GetStruct(&r, query); err != nil {
+			t.Fatalf("GetStruct() error = %v", err)
+		}
+		if r.Value != tc.want {
+			t.Fatalf("GetStruct() = %v, want Value = %d", r, tc.want)
+		}
+	}
+}
+
 func TestDbClientEx_MustListOf(t *testing.T) {
 	defer func() {
 		if recover() == nil {
@@ -1024,4 +1065,415 @@ func TestTransactionKeeperEx(t *testing.T) {
 
 		c.MustExecute("UPDATE go_TypeTest SET intTest=1 WHERE id=1")
 	})
+
+	t.Run("context-read-only", func(t *testing.T) {
+		tran := c.MustCreateTransactionContext(context.Background(), &sql.TxOptions{ReadOnly: true})
+		defer tran.MustClose()
+
+		v, _ := tran.MustScalarInt("SELECT intTest FROM go_TypeTest WHERE id=1")
+		if *v != 1 {
+			t.Fatalf("want 1, got %v", *v)
+		}
+
+		if _, err := tran.Execute("UPDATE go_TypeTest SET intTest=2 WHERE id=1"); err == nil {
+			t.Fatal("expect error in read only transaction")
+		}
+	})
+}
+
+func TestDbClientEx_Transaction(t *testing.T) {
+	c := getSqliteClientExForTest(t)
+	ctx := context.Background()
+
+	getIntTest := func(t *testing.T) int {
+		t.Helper()
+		v, _ := c.MustScalarInt("SELECT intTest FROM go_TypeTest WHERE id=1")
+		return *v
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			tx.MustExecute("UPDATE go_TypeTest SET intTest=2 WHERE id=1")
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if v := getIntTest(t); v != 2 {
+			t.Fatalf("want 2, got %v", v)
+		}
+		c.MustExecute("UPDATE go_TypeTest SET intTest=1 WHERE id=1")
+	})
+
+	t.Run("rollback-on-error", func(t *testing.T) {
+		wantErr := errors.New("some error")
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			tx.MustExecute("UPDATE go_TypeTest SET intTest=2 WHERE id=1")
+			return wantErr
+		})
+		if err != wantErr {
+			t.Fatalf("want %v, got %v", wantErr, err)
+		}
+
+		if v := getIntTest(t); v != 1 {
+			t.Fatalf("want 1, got %v", v)
+		}
+	})
+
+	t.Run("rollback-and-repanic", func(t *testing.T) {
+		func() {
+			defer func() {
+				if r := recover(); r != "boom" {
+					t.Fatalf("want panic boom, got %v", r)
+				}
+			}()
+
+			c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+				tx.MustExecute("UPDATE go_TypeTest SET intTest=2 WHERE id=1")
+				panic("boom")
+			})
+		}()
+
+		if v := getIntTest(t); v != 1 {
+			t.Fatalf("want 1, got %v", v)
+		}
+	})
+
+	t.Run("recover-panic", func(t *testing.T) {
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			tx.MustExecute("UPDATE go_TypeTest SET intTest=2 WHERE id=1")
+			tx.MustExecute("UPDATE go_TypeTest SET notExistColumn=2 WHERE id=1") // Must 版本的 API 出错时 panic 。
+			return nil
+		}, sqlmer.WithRecoverPanic(true))
+		if !errors.Is(err, sqlmer.ErrTran) || !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("want ErrTran and ErrExecutingSql, got %v", err)
+		}
+
+		if v := getIntTest(t); v != 1 {
+			t.Fatalf("want 1, got %v", v)
+		}
+	})
+
+	t.Run("tx-options", func(t *testing.T) {
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			_, err := tx.Execute("UPDATE go_TypeTest SET intTest=2 WHERE id=1")
+			return err
+		}, sqlmer.WithTxOptions(&sql.TxOptions{ReadOnly: true}))
+		if !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("want ErrExecutingSql, got %v", err)
+		}
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		wantErr := errors.New("some error")
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			tx.MustExecute("UPDATE go_TypeTest SET intTest=2 WHERE id=1")
+
+			if err := tx.Transaction(ctx, func(embeddedTx *sqlmer.TransactionKeeperEx) error {
+				embeddedTx.MustExecute("UPDATE go_TypeTest SET intTest=3 WHERE id=1")
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			// 内层提交后，外层事务依然可用。
+			if v, _ := tx.MustScalarInt("SELECT intTest FROM go_TypeTest WHERE id=1"); *v != 3 {
+				t.Errorf("want 3, got %v", *v)
+			}
+
+			return tx.Transaction(ctx, func(embeddedTx *sqlmer.TransactionKeeperEx) error {
+				return wantErr
+			})
+		})
+		if err != wantErr {
+			t.Fatalf("want %v, got %v", wantErr, err)
+		}
+
+		if v := getIntTest(t); v != 1 {
+			t.Fatalf("want 1, got %v", v)
+		}
+	})
+}
+
+func TestDbClientEx_Transaction_retry(t *testing.T) {
+	errRetryable := errors.New("retryable error")
+	c := sqlmer.Extend(newSqliteDbClientForAbstractDbTest(t, sqlmer.WithRetryableErrorFunc(func(err error) bool {
+		return errors.Is(err, errRetryable)
+	})))
+	ctx := context.Background()
+
+	t.Run("succeed-after-retry", func(t *testing.T) {
+		attempts := 0
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			attempts++
+			tx.MustExecute("UPDATE go_TypeTest SET intTest=intTest+1 WHERE id=1")
+			if attempts < 3 {
+				return errRetryable
+			}
+			return nil
+		}, sqlmer.WithRetry(3, sqlmer.ConstantBackoff(time.Millisecond)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("want 3 attempts, got %v", attempts)
+		}
+
+		// 失败的尝试都已回滚，只有最后一次生效。
+		if v, _ := c.MustScalarInt("SELECT intTest FROM go_TypeTest WHERE id=1"); *v != 2 {
+			t.Fatalf("want 2, got %v", *v)
+		}
+		c.MustExecute("UPDATE go_TypeTest SET intTest=1 WHERE id=1")
+	})
+
+	t.Run("exceed-max-attempts", func(t *testing.T) {
+		attempts := 0
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			attempts++
+			return errRetryable
+		}, sqlmer.WithRetry(2, nil))
+		if err != errRetryable {
+			t.Fatalf("want %v, got %v", errRetryable, err)
+		}
+		if attempts != 2 {
+			t.Fatalf("want 2 attempts, got %v", attempts)
+		}
+	})
+
+	t.Run("not-retryable", func(t *testing.T) {
+		wantErr := errors.New("some error")
+		attempts := 0
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			attempts++
+			return wantErr
+		}, sqlmer.WithRetry(3, nil))
+		if err != wantErr {
+			t.Fatalf("want %v, got %v", wantErr, err)
+		}
+		if attempts != 1 {
+			t.Fatalf("want 1 attempt, got %v", attempts)
+		}
+	})
+
+	t.Run("context-canceled-while-waiting", func(t *testing.T) {
+		ctx, cancelFunc := context.WithTimeout(ctx, time.Millisecond*10)
+		defer cancelFunc()
+
+		attempts := 0
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			attempts++
+			return errRetryable
+		}, sqlmer.WithRetry(3, sqlmer.ConstantBackoff(time.Minute)))
+		if !errors.Is(err, errRetryable) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("want errRetryable and context.DeadlineExceeded, got %v", err)
+		}
+		if attempts != 1 {
+			t.Fatalf("want 1 attempt, got %v", attempts)
+		}
+	})
+
+	t.Run("embedded-not-retry", func(t *testing.T) {
+		attempts := 0
+		err := c.Transaction(ctx, func(tx *sqlmer.TransactionKeeperEx) error {
+			return tx.Transaction(ctx, func(embeddedTx *sqlmer.TransactionKeeperEx) error {
+				attempts++
+				return errRetryable
+			}, sqlmer.WithRetry(3, nil))
+		})
+		if err != errRetryable {
+			t.Fatalf("want %v, got %v", errRetryable, err)
+		}
+		if attempts != 1 {
+			t.Fatalf("want 1 attempt, got %v", attempts)
+		}
+	})
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := sqlmer.ExponentialBackoff(time.Millisecond*10, time.Millisecond*50)
+	for attempt, want := range map[int]time.Duration{
+		1: time.Millisecond * 10,
+		2: time.Millisecond * 20,
+		3: time.Millisecond * 40,
+		4: time.Millisecond * 50,
+		9: time.Millisecond * 50,
+	} {
+		if got := backoff(attempt); got != want {
+			t.Errorf("attempt %v: want %v, got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestDbClientEx_BatchInsert(t *testing.T) {
+	ctx := context.Background()
+
+	// 每条语句最多 6 个参数，即 3 列时每批 2 行。
+	newClient := func(t *testing.T, options ...sqlmer.DbClientOption) *sqlmer.DbClientEx {
+		options = append([]sqlmer.DbClientOption{sqlmer.WithMaxParamCount(6)}, options...)
+		c := sqlmer.Extend(newSqliteDbClientForAbstractDbTest(t, options...))
+		c.MustExecute(`CREATE TABLE go_BatchTest (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, age INTEGER, nick_name TEXT)`)
+		return c
+	}
+
+	assertRows := func(t *testing.T, c *sqlmer.DbClientEx, want []map[string]any) {
+		t.Helper()
+		// SQLite 驱动按首行的值推断列的 Scan 类型，之后行中的 NULL 无法读取，这里转为 -1 和空字符串。
+		got := c.MustSliceGet("SELECT name, IFNULL(age, -1) AS age, IFNULL(nick_name, '') AS nick_name FROM go_BatchTest ORDER BY id")
+		if len(got) == 0 && len(want) == 0 {
+			return
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+
+	t.Run("slice", func(t *testing.T) {
+		c := newClient(t)
+
+		rows := [][]any{
+			{"a", 1, "na"},
+			{"b", nil, nil},
+			{"c", 3, sql.NullString{String: "nc", Valid: true}},
+			{"d", 4, "nd"},
+			{"e", 5, "ne"},
+		}
+		effected, err := c.BatchInsert(ctx, "go_BatchTest", []string{"name", "age", "nick_name"}, rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if effected != 5 {
+			t.Fatalf("want 5, got %v", effected)
+		}
+
+		assertRows(t, c, []map[string]any{
+			{"name": "a", "age": int64(1), "nick_name": "na"},
+			{"name": "b", "age": int64(-1), "nick_name": ""},
+			{"name": "c", "age": int64(3), "nick_name": "nc"},
+			{"name": "d", "age": int64(4), "nick_name": "nd"},
+			{"name": "e", "age": int64(5), "nick_name": "ne"},
+		})
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		type Base struct {
+			Name string
+		}
+		type row struct {
+			*Base
+			Age  *int
+			Nick string `conv:"nick_name"`
+		}
+
+		c := newClient(t)
+		age := 2
+		rows := []*row{
+			{Base: &Base{Name: "a"}, Nick: "na"},
+			{Base: &Base{Name: "b"}, Age: &age, Nick: "nb"},
+			{Base: &Base{Name: "c"}, Nick: "nc"},
+		}
+		effected, err := c.BatchInsert(ctx, "go_BatchTest", []string{"name", "age", "nick_name"}, rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if effected != 3 {
+			t.Fatalf("want 3, got %v", effected)
+		}
+
+		assertRows(t, c, []map[string]any{
+			{"name": "a", "age": int64(-1), "nick_name": "na"},
+			{"name": "b", "age": int64(2), "nick_name": "nb"},
+			{"name": "c", "age": int64(-1), "nick_name": "nc"},
+		})
+	})
+
+	t.Run("value_args", func(t *testing.T) {
+		c := newClient(t)
+
+		// 切片、结构体类型的值原样交给驱动，不作为 IN 参数展开，也不作为命名参数合并。
+		rows := [][]any{
+			{batchTestNames{"a", "b"}, 1, sql.NullString{String: "na", Valid: true}},
+			{batchTestNames{"c"}, nil, sql.NullString{}},
+		}
+		effected, err := c.BatchInsert(ctx, "go_BatchTest", []string{"name", "age", "nick_name"}, rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if effected != 2 {
+			t.Fatalf("want 2, got %v", effected)
+		}
+
+		assertRows(t, c, []map[string]any{
+			{"name": "a,b", "age": int64(1), "nick_name": "na"},
+			{"name": "c", "age": int64(-1), "nick_name": ""},
+		})
+	})
+
+	t.Run("row_limit", func(t *testing.T) {
+		var statements int
+		c := newClient(t, sqlmer.WithMaxInsertRows(2), sqlmer.WithObserver(func(ctx context.Context, observation sqlmer.SqlObservation) {
+			statements++
+		}))
+		statements = 0 // 不统计建表语句。
+
+		// 1 列时参数个数允许每批 6 行，受行数限制，每批只能插入 2 行。
+		rows := [][]any{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}
+		effected, err := c.BatchInsert(ctx, "go_BatchTest", []string{"name"}, rows)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if effected != 5 || statements != 3 {
+			t.Fatalf("want 5 rows in 3 statements, got %v rows in %v statements", effected, statements)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		c := newClient(t)
+
+		// 最后一批违反了非空约束，之前的批次也应回滚。
+		rows := [][]any{{"a", 1, "na"}, {"b", 2, "nb"}, {"c", 3, "nc"}, {nil, 4, "nd"}}
+		if _, err := c.BatchInsert(ctx, "go_BatchTest", []string{"name", "age", "nick_name"}, rows); !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("want ErrExecutingSql, got %v", err)
+		}
+		assertRows(t, c, nil)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		c := newClient(t)
+		effected, err := c.BatchInsert(ctx, "go_BatchTest", []string{"name"}, [][]any{})
+		if err != nil || effected != 0 {
+			t.Fatalf("want 0 nil, got %v %v", effected, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c := newClient(t)
+
+		tests := []struct {
+			name    string
+			columns []string
+			rows    any
+		}{
+			{"no_columns", nil, [][]any{{"a"}}},
+			{"value_count", []string{"name", "age"}, [][]any{{"a"}}},
+			{"not_slice", []string{"name"}, "a"},
+			{"not_struct", []string{"name"}, []int{1}},
+			{"no_field", []string{"name", "notExists"}, []struct{ Name string }{{"a"}}},
+			{"too_many_columns", []string{"a", "b", "c", "d", "e", "f", "g"}, [][]any{{1, 2, 3, 4, 5, 6, 7}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := c.BatchInsert(ctx, "go_BatchTest", tt.columns, tt.rows); err == nil {
+					t.Fatal("expect error")
+				}
+			})
+		}
+	})
+}
+
+// batchTestNames 是一个切片类型的 driver.Valuer ，用于测试 BatchInsert 不会展开切片类型的值。
+type batchTestNames []string
+
+func (n batchTestNames) Value() (driver.Value, error) {
+	return strings.Join(n, ","), nil
 }
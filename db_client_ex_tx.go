@@ -1,5 +1,12 @@
 package sqlmer
 
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
 var _ TransactionKeeper = (*TransactionKeeperEx)(nil)
 
 // TransactionKeeperEx 扩展 TransactionKeeper ，增加 DbClientEx 的功能和 Must 版本的事务 API。
@@ -26,6 +33,15 @@ func (c *DbClientEx) CreateTransactionEx() (*TransactionKeeperEx, error) {
 	}
 }
 
+// CreateTransactionExContext 基于 DbClient.CreateTransactionContext 创建一个 TransactionKeeperEx 实例。
+func (c *DbClientEx) CreateTransactionExContext(ctx context.Context, opts *sql.TxOptions) (*TransactionKeeperEx, error) {
+	if tx, err := c.DbClient.CreateTransactionContext(ctx, opts); err != nil {
+		return nil, err
+	} else {
+		return ExtendTx(tx), nil
+	}
+}
+
 // MustCreateTransactionEx（和 MustCreateTransaction 一致） 用于开始一个事务。
 // returns:
 //
@@ -34,6 +50,119 @@ func (c *DbClientEx) MustCreateTransactionEx() *TransactionKeeperEx {
 	return c.MustCreateTransaction()
 }
 
+// TransactionOption 是 DbClientEx.Transaction 的可选配置。
+type TransactionOption func(config *transactionConfig)
+
+// transactionConfig 是 DbClientEx.Transaction 的配置。
+type transactionConfig struct {
+	txOptions    *sql.TxOptions // 事务选项，为 nil 时使用驱动的默认值。
+	recoverPanic bool           // 是否将 panic 转为 error 返回。
+	maxAttempts  int            // 最大尝试次数，包含第一次执行。
+	backoff      BackoffFunc    // 重试前的等待时间，为 nil 时立即重试。
+}
+
+// WithTxOptions 用于为 DbClientEx.Transaction 指定事务选项（隔离级别及是否只读）。
+func WithTxOptions(opts *sql.TxOptions) TransactionOption {
+	return func(config *transactionConfig) {
+		config.txOptions = opts
+	}
+}
+
+// WithRecoverPanic 用于选择 DbClientEx.Transaction 在 fn 中发生 panic 时，是否将 panic 转为 error 返回（默认为 false）。
+// 无论是否开启，发生 panic 时事务都会先被回滚；未开启时，回滚后会继续抛出原 panic 。
+func WithRecoverPanic(recoverPanic bool) TransactionOption {
+	return func(config *transactionConfig) {
+		config.recoverPanic = recoverPanic
+	}
+}
+
+// Transaction 用于在一个事务中执行 fn ，fn 返回 nil 时提交事务，返回 error 时回滚事务并返回该 error 。
+// params:
+//
+//	@ctx 事务的 context，在事务提交或回滚前一直有效。
+//	@fn 在事务中执行的函数，可以在其中使用 Must 版本的 API 。
+//	@options 可选配置，见 WithTxOptions 、 WithRecoverPanic 、 WithRetry 。
+//
+// returns:
+//
+//	@err fn 返回的 error ，或创建、提交、回滚事务时遇到的错误。
+//
+// 当 fn 发生 panic 时，事务会被回滚，之后根据 WithRecoverPanic 的配置继续抛出 panic 或转为 error 返回，
+// 转换得到的 error 可以通过 errors.Is(err, sqlmer.ErrTran) 判断，若 panic 的值本身是 error ，也可以通过 errors.Is/As 访问。
+//
+// 在事务中（TransactionKeeperEx 上）调用时，遵循嵌套事务的语义：未开启 WithSavepoint 时，
+// 内层的提交与回滚均交由最外层事务处理，因此内层 fn 返回的 error 需要继续向外返回，以使最外层事务回滚。
+//
+// 通过 WithRetry 可以在事务因死锁、序列化失败等可重试的错误（由驱动判断，见 RetryableErrorClassifier ）失败时，
+// 重新开始事务并再次执行 fn ，因此 fn 需要能够安全地重复执行。嵌套事务中（包括 ctx 中带有 WithTx 设置的事务时）不会重试，
+// 可重试的错误需要返回给最外层事务处理。
+func (c *DbClientEx) Transaction(ctx context.Context, fn func(tx *TransactionKeeperEx) error, options ...TransactionOption) error {
+	config := &transactionConfig{maxAttempts: 1}
+	for _, option := range options {
+		option(config)
+	}
+
+	// 可重试的错误（如死锁）通常已导致整个事务被回滚，嵌套事务（包括可能加入的 context 中的事务）无法单独重试。
+	_, embedded := c.DbClient.(TransactionKeeper)
+	if _, inContextTx := TxFromContext(ctx); embedded || inContextTx {
+		return c.runTransaction(ctx, fn, config)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.runTransaction(ctx, fn, config)
+		if err == nil || attempt >= config.maxAttempts || !c.isRetryableError(err) {
+			return err
+		}
+
+		if reporter, ok := c.DbClient.(TransactionRetryReporter); ok {
+			reporter.ReportTransactionRetry(attempt, err)
+		}
+
+		if waitErr := waitBackoff(ctx, config.backoff, attempt); waitErr != nil {
+			return errors.Join(err, waitErr)
+		}
+	}
+}
+
+// runTransaction 用于在一个事务中执行一次 fn 。
+func (c *DbClientEx) runTransaction(ctx context.Context, fn func(tx *TransactionKeeperEx) error, config *transactionConfig) (err error) {
+	tx, err := c.CreateTransactionExContext(ctx, config.txOptions)
+	if err != nil {
+		return err
+	}
+	defer tx.Close() // 嵌套事务依赖 Close 维护嵌套层级，因此总是需要 Close 。
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		rollbackErr := tx.Rollback()
+		if !config.recoverPanic {
+			panic(r)
+		}
+
+		if panicErr, ok := r.(error); ok {
+			err = fmt.Errorf("%w: panic in transaction: %w", ErrTran, panicErr)
+		} else {
+			err = fmt.Errorf("%w: panic in transaction: %v", ErrTran, r)
+		}
+		if rollbackErr != nil {
+			err = errors.Join(err, rollbackErr)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // MustCommit 用于提交事务。
 func (transKeeper *TransactionKeeperEx) MustCommit() {
 	if err := transKeeper.Commit(); err != nil {
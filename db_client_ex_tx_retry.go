@@ -0,0 +1,67 @@
+package sqlmer
+
+import (
+	"context"
+	"time"
+)
+
+// BackoffFunc 用于计算事务重试前的等待时间， attempt 为已失败的尝试次数（从 1 开始）。
+type BackoffFunc func(attempt int) time.Duration
+
+// ConstantBackoff 返回一个每次重试前都等待固定时间的 BackoffFunc 。
+func ConstantBackoff(interval time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return interval
+	}
+}
+
+// ExponentialBackoff 返回一个指数增长的 BackoffFunc ：第 1 次重试前等待 initial ，之后每次翻倍，最多等待 max 。
+func ExponentialBackoff(initial time.Duration, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		interval := initial
+		for i := 1; i < attempt && interval < max; i++ {
+			interval *= 2
+		}
+		if interval > max {
+			interval = max
+		}
+		return interval
+	}
+}
+
+// WithRetry 用于为 DbClientEx.Transaction 开启失败重试。
+// params:
+//
+//	@maxAttempts 最大尝试次数（包含第一次执行），小于等于 1 时不重试。
+//	@backoff 用于计算每次重试前的等待时间，为 nil 时立即重试。
+//
+// 仅当错误被驱动判断为可重试（如 MySQL 1213/1205 、 SqlServer 1205 、 SQLite SQLITE_BUSY 等）时才会重试。
+func WithRetry(maxAttempts int, backoff BackoffFunc) TransactionOption {
+	return func(config *transactionConfig) {
+		config.maxAttempts = maxAttempts
+		config.backoff = backoff
+	}
+}
+
+// isRetryableError 用于判断错误是否可以通过重试事务解决，判断逻辑由 DbClient 实现的 RetryableErrorClassifier 提供。
+func (c *DbClientEx) isRetryableError(err error) bool {
+	classifier, ok := c.DbClient.(RetryableErrorClassifier)
+	return ok && classifier.IsRetryableError(err)
+}
+
+// waitBackoff 用于在重试前等待，若等待期间 ctx 被取消，返回 ctx 的错误。
+func waitBackoff(ctx context.Context, backoff BackoffFunc, attempt int) error {
+	if backoff == nil {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
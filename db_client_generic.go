@@ -0,0 +1,136 @@
+package sqlmer
+
+import (
+	"context"
+	"io"
+	"iter"
+	"reflect"
+
+	"github.com/cmstar/go-conv"
+)
+
+// convProvider 用于获取 DbClient 所使用的 conv.Conv 实例， DbClientEx 及 TransactionKeeperEx 实现了该接口。
+type convProvider interface {
+	getConv() *conv.Conv
+}
+
+// getConv 返回当前实例所使用的 conv.Conv 实例。
+func (c *DbClientEx) getConv() *conv.Conv {
+	return &c.Conv
+}
+
+// getClientConv 用于获取给定的 DbClient 所使用的 conv.Conv 实例。
+// 若 client 是 DbClientEx （或 TransactionKeeperEx ），使用其 Conv 字段；否则使用与 DbClientEx 默认配置一致的实例。
+func getClientConv(client DbClient) *conv.Conv {
+	if provider, ok := client.(convProvider); ok {
+		return provider.getConv()
+	}
+	return &dbConv
+}
+
+// List 将查询结果的每一行转换到 T 类型，返回转换后的元素的列表。若查询没有命中行，返回空集。
+// params:
+//
+//	@ctx context。
+//	@client 执行查询的 DbClient ，若为 DbClientEx 则使用其 Conv 进行类型转换。
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//	@args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+//
+// T 为结构体（或 map 等复杂类型）时，将整行转换到 T ；为数字、字符串等简单类型时，将每一行的第一列转换到 T 。
+//
+//	users, err := sqlmer.List[User](ctx, client, "SELECT id, name FROM user WHERE age>@p1", 18)
+//	ids, err := sqlmer.List[int](ctx, client, "SELECT id FROM user")
+func List[T any](ctx context.Context, client DbClient, sqlText string, args ...any) ([]T, error) {
+	rows, err := client.RowsContext(ctx, sqlText, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() // This error is ignored.
+
+	vList, err := scanList(rows, getClientConv(client), reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return vList.Interface().([]T), nil
+}
+
+// MustList 类似 List ，但出现错误时不返回 error ，而是 panic 。
+func MustList[T any](ctx context.Context, client DbClient, sqlText string, args ...any) []T {
+	list, err := List[T](ctx, client, sqlText, args...)
+	if err != nil {
+		panic(err)
+	}
+	return list
+}
+
+// Get 获取一行的查询结果，并转换到 T 类型， T 通常是结构体类型。
+// 若查询没有命中行，返回 T 的零值和 ok=false 。
+//
+//	user, ok, err := sqlmer.Get[User](ctx, client, "SELECT id, name FROM user WHERE id=@p1", 1)
+func Get[T any](ctx context.Context, client DbClient, sqlText string, args ...any) (value T, ok bool, err error) {
+	m, err := client.GetContext(ctx, sqlText, args...)
+	if err != nil || m == nil {
+		return
+	}
+
+	if err = getClientConv(client).Convert(m, &value); err != nil {
+		return
+	}
+	return value, true, nil
+}
+
+// Scalar 查询第一行第一列，并转换到 T 类型。
+// 若查询没有命中行，返回 T 的零值和 ok=false ；若有结果但值是 null ，则返回 T 的零值和 ok=true ，
+// 需要区分 null 时， T 可以使用指针类型，此时值为 null 会得到空指针。
+//
+//	count, _, err := sqlmer.Scalar[int](ctx, client, "SELECT COUNT(1) FROM user")
+//	name, ok, err := sqlmer.Scalar[*string](ctx, client, "SELECT name FROM user WHERE id=@p1", 1)
+func Scalar[T any](ctx context.Context, client DbClient, sqlText string, args ...any) (value T, ok bool, err error) {
+	v, ok, err := client.ScalarContext(ctx, sqlText, args...)
+	if !ok || err != nil {
+		return
+	}
+
+	err = getClientConv(client).Convert(v, &value)
+	return
+}
+
+// Iter 返回逐行读取查询结果的迭代器，每一行转换到 T 类型，适用于不便将结果全部加载到内存的大结果集。
+// 转换规则同 List 。迭代结束（包括在循环中提前退出）后游标会被自动关闭；
+// 查询或转换出现错误时，会给出 T 的零值和该错误并结束迭代。
+//
+//	for user, err := range sqlmer.Iter[User](ctx, client, "SELECT id, name FROM user") {
+//		if err != nil {
+//			return err
+//		}
+//		// 使用 user 。
+//	}
+func Iter[T any](ctx context.Context, client DbClient, sqlText string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := client.RowsContext(ctx, sqlText, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close() // This error is ignored.
+
+		scanRow := newRowScanner(getClientConv(client), reflect.TypeOf((*T)(nil)).Elem())
+		for rows.Next() {
+			item, err := scanRow(rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			value, _ := item.Interface().(T) // T 为接口类型且值为 nil 时，得到 T 的零值。
+			if !yield(value, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil && err != io.EOF {
+			yield(zero, err)
+		}
+	}
+}
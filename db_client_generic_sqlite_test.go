@@ -0,0 +1,293 @@
+package sqlmer_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/cmstar/go-conv"
+)
+
+type genericRowType struct {
+	Id           string
+	IntTest      *int
+	V            string `conv:"varcharTest"`
+	CharTest     *string
+	NullTextTest *string
+	DateTimeTest time.Time
+}
+
+func TestList(t *testing.T) {
+	c := getSqliteClientExForTest(t)
+	ctx := context.Background()
+
+	t.Run("struct", func(t *testing.T) {
+		query := `SELECT id, intTest, varcharTest, charTest, nullTextTest, dateTimeTest FROM go_TypeTest WHERE id IN (@p1) ORDER BY id`
+		list, err := sqlmer.List[genericRowType](ctx, c, query, []int{1, 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 2 {
+			t.Fatalf("expect length=2, got %v", len(list))
+		}
+
+		v := list[0]
+		if v.Id != "1" || *v.IntTest != 1 || v.V != "行1" || *v.CharTest != "行1char" || v.NullTextTest != nil {
+			t.Fatalf("unexpected row: %+v", v)
+		}
+		if v.DateTimeTest.UnixNano() != time.Date(2021, 7, 1, 15, 38, 50, 425000000, time.UTC).UnixNano() {
+			t.Fatalf("expect DateTimeTest=2021-07-01 15:38:50.425, got %v", v.DateTimeTest)
+		}
+		if list[1].Id != "2" {
+			t.Fatalf("expect ID=2, got %v", list[1].Id)
+		}
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		list, err := sqlmer.List[*genericRowType](ctx, c, `SELECT id, varcharTest FROM go_TypeTest WHERE id=1`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || list[0].Id != "1" || list[0].V != "行1" {
+			t.Fatalf("unexpected list: %v", list)
+		}
+	})
+
+	t.Run("scanner-field", func(t *testing.T) {
+		type row struct {
+			Id           int
+			NullTextTest sql.NullString
+			VarcharTest  sql.NullString
+		}
+
+		list, err := sqlmer.List[**row](ctx, c, `SELECT id, nullTextTest, varcharTest FROM go_TypeTest WHERE id=1`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 {
+			t.Fatalf("expect length=1, got %v", len(list))
+		}
+
+		v := **list[0]
+		if v.Id != 1 || v.NullTextTest.Valid || v.VarcharTest != (sql.NullString{String: "行1", Valid: true}) {
+			t.Fatalf("unexpected row: %+v", v)
+		}
+	})
+
+	t.Run("simple", func(t *testing.T) {
+		list, err := sqlmer.List[int](ctx, c, `SELECT id FROM go_TypeTest WHERE id IN (@p1, @p2, @p3, @p4) ORDER BY id`, 1, 2, 3, 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expect := []int{1, 2, 3, 4}
+		if !reflect.DeepEqual(expect, list) {
+			t.Fatalf("expect %v, got %v", expect, list)
+		}
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		list, err := sqlmer.List[genericRowType](ctx, c, `SELECT id FROM go_TypeTest WHERE id IN (-1)`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if list == nil || len(list) != 0 {
+			t.Fatalf("expect empty list, got %v", list)
+		}
+	})
+
+	t.Run("raw-client", func(t *testing.T) {
+		list, err := sqlmer.List[genericRowType](ctx, c.DbClient, `SELECT id, varcharTest FROM go_TypeTest WHERE id=1`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || list[0].V != "行1" {
+			t.Fatalf("unexpected list: %v", list)
+		}
+	})
+
+	t.Run("custom-conv", func(t *testing.T) {
+		type row struct {
+			Name string `db:"varcharTest"`
+		}
+
+		ex := sqlmer.Extend(c.DbClient)
+		ex.Conv = conv.Conv{
+			Conf: conv.Config{
+				FieldMatcherCreator: &conv.SimpleMatcherCreator{
+					Conf: conv.SimpleMatcherConfig{Tag: "db"},
+				},
+			},
+		}
+
+		list, err := sqlmer.List[row](ctx, ex, `SELECT varcharTest FROM go_TypeTest WHERE id=1`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(list) != 1 || list[0].Name != "行1" {
+			t.Fatalf("unexpected list: %v", list)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		tx := c.MustCreateTransaction()
+		defer tx.MustClose()
+
+		list, err := sqlmer.List[int](ctx, tx, `SELECT id FROM go_TypeTest WHERE id=1`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual([]int{1}, list) {
+			t.Fatalf("expect [1], got %v", list)
+		}
+	})
+}
+
+func TestMustList(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("should panic")
+		}
+	}()
+
+	c := getSqliteClientExForTest(t)
+	sqlmer.MustList[int](context.Background(), c, "error-sql")
+}
+
+func TestGet(t *testing.T) {
+	c := getSqliteClientExForTest(t)
+	ctx := context.Background()
+
+	t.Run("hit", func(t *testing.T) {
+		v, ok, err := sqlmer.Get[genericRowType](ctx, c, `SELECT id, intTest, varcharTest FROM go_TypeTest WHERE id=@id`, map[string]any{"id": 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ok {
+			t.Fatal("expect ok=true")
+		}
+		if v.Id != "1" || *v.IntTest != 1 || v.V != "行1" {
+			t.Fatalf("unexpected row: %+v", v)
+		}
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		v, ok, err := sqlmer.Get[*genericRowType](ctx, c, `SELECT id FROM go_TypeTest WHERE id=-1`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok || v != nil {
+			t.Fatalf("expect ok=false and nil, got %v %v", ok, v)
+		}
+	})
+}
+
+func TestScalar(t *testing.T) {
+	c := getSqliteClientExForTest(t)
+	ctx := context.Background()
+
+	t.Run("int", func(t *testing.T) {
+		v, ok, err := sqlmer.Scalar[int](ctx, c, `SELECT intTest FROM go_TypeTest WHERE id=1`)
+		if err != nil || !ok || v != 1 {
+			t.Fatalf("expect 1 true nil, got %v %v %v", v, ok, err)
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		v, ok, err := sqlmer.Scalar[string](ctx, c, `SELECT intTest FROM go_TypeTest WHERE id=1`)
+		if err != nil || !ok || v != "1" {
+			t.Fatalf("expect 1 true nil, got %v %v %v", v, ok, err)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		v, ok, err := sqlmer.Scalar[*string](ctx, c, `SELECT nullTextTest FROM go_TypeTest WHERE id=1`)
+		if err != nil || !ok || v != nil {
+			t.Fatalf("expect nil true nil, got %v %v %v", v, ok, err)
+		}
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		v, ok, err := sqlmer.Scalar[int](ctx, c, `SELECT intTest FROM go_TypeTest WHERE id=-1`)
+		if err != nil || ok || v != 0 {
+			t.Fatalf("expect 0 false nil, got %v %v %v", v, ok, err)
+		}
+	})
+
+	t.Run("convert-error", func(t *testing.T) {
+		if _, _, err := sqlmer.Scalar[int](ctx, c, `SELECT varcharTest FROM go_TypeTest WHERE id=1`); err == nil {
+			t.Fatal("expect error")
+		}
+	})
+}
+
+func TestIter(t *testing.T) {
+	c := getSqliteClientExForTest(t)
+	ctx := context.Background()
+
+	t.Run("struct", func(t *testing.T) {
+		var ids []string
+		for v, err := range sqlmer.Iter[genericRowType](ctx, c, `SELECT id, varcharTest FROM go_TypeTest WHERE id IN (@p1) ORDER BY id`, []int{1, 2}) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids = append(ids, v.Id)
+		}
+		if !reflect.DeepEqual([]string{"1", "2"}, ids) {
+			t.Fatalf("expect [1 2], got %v", ids)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		var ids []int
+		for v, err := range sqlmer.Iter[int](ctx, c, `SELECT id FROM go_TypeTest ORDER BY id`) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids = append(ids, v)
+			if len(ids) == 2 {
+				break
+			}
+		}
+		if !reflect.DeepEqual([]int{1, 2}, ids) {
+			t.Fatalf("expect [1 2], got %v", ids)
+		}
+	})
+
+	t.Run("null-pointer", func(t *testing.T) {
+		for v, err := range sqlmer.Iter[*string](ctx, c, `SELECT nullTextTest FROM go_TypeTest WHERE id=1`) {
+			if err != nil || v != nil {
+				t.Fatalf("expect nil nil, got %v %v", v, err)
+			}
+		}
+	})
+
+	t.Run("query-error", func(t *testing.T) {
+		count := 0
+		for _, err := range sqlmer.Iter[int](ctx, c, "error-sql") {
+			count++
+			if err == nil {
+				t.Fatal("expect error")
+			}
+		}
+		if count != 1 {
+			t.Fatalf("expect 1 iteration, got %v", count)
+		}
+	})
+
+	t.Run("convert-error", func(t *testing.T) {
+		count := 0
+		for _, err := range sqlmer.Iter[int](ctx, c, `SELECT varcharTest FROM go_TypeTest ORDER BY id`) {
+			count++
+			if err == nil {
+				t.Fatal("expect error")
+			}
+		}
+		if count != 1 {
+			t.Fatalf("expect 1 iteration, got %v", count)
+		}
+	})
+}
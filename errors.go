@@ -17,12 +17,21 @@ var (
 	// ErrGetEffectedRows 当数据库不支持获取影响行数时候，会返回改类型的错误。
 	ErrGetEffectedRows = errors.New("dbClient: the db driver do not support getting effected rows")
 
+	// ErrGetInsertId 当执行成功，但无法获取到自增 id 时候，会返回该类型的错误。
+	ErrGetInsertId = errors.New("dbClient: failed to get the last insert id")
+
 	// ErrSqlParamParse 解析 SQL 语句中的参数遇到错误时候，会返回该类型错误。
 	ErrParseParamFailed = errors.New("dbClient: failed to parse named params")
 
 	// ErrExpectedSizeWrong 当执行语句时候，没有影响到预期行数，返回该类型错误。
 	ErrExpectedSizeWrong = errors.New("dbClient: effected rows was wrong")
 
+	// ErrStmtClosed 当在已关闭的 Stmt 上执行语句时，返回该类型错误。
+	ErrStmtClosed = errors.New("dbClient: statement is closed")
+
+	// ErrClientClosed 当在已关闭的 DbClient 上开始事务时，返回该类型错误。
+	ErrClientClosed = errors.New("dbClient: client is closed")
+
 	// ErrExecutingSql 当执行 SQL 语句执行时遇到错误，返回该类型错误。
 	ErrExecutingSql = errors.New("dbClient: failed to execute sql")
 )
@@ -68,6 +77,24 @@ func (e *SqlContextError) Is(target error) bool {
 	return e.IsExecutingSQL && target == ErrExecutingSql
 }
 
+// ScriptError 是 ExecuteScript 执行脚本中的某条语句失败时返回的错误。
+// Err 为执行该语句遇到的错误（通常为 *SqlContextError ），可通过 errors.Is 、 errors.As 访问。
+type ScriptError struct {
+	Index     int    // 失败的语句在拆分后的语句中的序号（从 0 开始）。
+	Statement string // 失败的语句。
+	Err       error
+}
+
+// Error 返回包含语句序号的错误信息。
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("dbClient: script statement %d failed: %s", e.Index, e.Err.Error())
+}
+
+// Unwrap 返回执行语句遇到的错误，用于错误链上的 Is / As。
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
 // getExecutingSqlError 用于生成一个带着 SQL 和参数列表的 ErrExecutingSql。
 // 错误内容中包含了：原始传入的 SQL，解析后的 SQL，参数列表。
 func getExecutingSqlError(err error, rawSql string, fixedSql string, params []any) error {
@@ -152,9 +152,9 @@ func ormDemo() {
 
 	// 获取一个列表。
 	//
-	// 由于 Golang 不支持方法级的泛型，这里需要通过第一个参数传入一个模板（这里是 Row{} ），指定需要将数据行映射到什么类型；
-	// API 返回模板类型的 slice ，以 any 表示，需要再进行类型转换（这里是 []Row ）。
-	rows := clientEx.MustListOf(Row{}, "SELECT * FROM demo").([]Row)
+	// sqlmer.List/MustList 是包级的泛型函数，通过类型参数指定需要将数据行映射到什么类型（这里是 Row ），直接返回 []Row 。
+	// 传入 DbClientEx 时，会使用其 Conv 进行类型转换。
+	rows := sqlmer.MustList[Row](context.Background(), clientEx, "SELECT * FROM demo")
 	fmt.Println("Rows:")
 	for _, v := range rows {
 		fmt.Printf("%v\n", v)
@@ -164,8 +164,15 @@ func ormDemo() {
 	// {1 rui 1 SCORES:1,3,5,7}
 	// {2 bao 2 SCORES:2,4,6,8}
 
-	// 模板可以是 struct 也可以是其指针，指针的写法：
-	// clientEx.MustListOf(new(Row), "SELECT * FROM demo").([]*Row)
+	// 类型参数可以是 struct 也可以是其指针，指针的写法：
+	// sqlmer.MustList[*Row](context.Background(), clientEx, "SELECT * FROM demo")
+	//
+	// 另外 sqlmer.Get 、 sqlmer.Scalar 分别用于获取一行及第一行第一列：
+	// row, ok, err := sqlmer.Get[Row](ctx, clientEx, "SELECT * FROM demo WHERE Id=@p1", 1)
+	// count, _, err := sqlmer.Scalar[int](ctx, clientEx, "SELECT COUNT(1) FROM demo")
+	//
+	// 非泛型的 ListOf/ListType 依然可用，返回值需要进行类型断言：
+	// clientEx.MustListOf(Row{}, "SELECT * FROM demo").([]Row)
 }
 
 // 演示如何在 ORM 过程中，如果 struct 的目标字段的类型和数据库的类型不能兼容时，如何通过代码定制转换过程。
@@ -6,6 +6,7 @@ require (
 	github.com/cmstar/go-conv v0.6.6
 	github.com/denisenkom/go-mssqldb v0.12.3
 	github.com/go-sql-driver/mysql v1.9.3
+	github.com/lib/pq v1.10.9
 	github.com/ncruces/go-sqlite3 v0.30.4
 	golang.org/x/sync v0.19.0
 )
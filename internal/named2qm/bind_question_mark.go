@@ -4,11 +4,12 @@ package named2qm
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 )
 
 // 是 SQL 命名参数解析后的结果。
@@ -19,23 +20,26 @@ type ParsedResult struct {
 
 // QuestionMarkSqlBinder 是支持 ? 占位符驱动的命名参数绑定器。
 type QuestionMarkSqlBinder struct {
-	cache *twoGenCache
+	cache   *twoGenCache
+	dialect sqltoken.Dialect
 }
 
-// NewQuestionMarkSqlBinder 用于创建一个带有独立缓存的绑定器。
-func NewQuestionMarkSqlBinder(cacheCapacity int) (*QuestionMarkSqlBinder, error) {
+// NewQuestionMarkSqlBinder 用于创建一个带有独立缓存的绑定器， dialect 用于识别语句中的字符串、引用标识符及注释。
+func NewQuestionMarkSqlBinder(cacheCapacity int, dialect sqltoken.Dialect) (*QuestionMarkSqlBinder, error) {
 	if cacheCapacity <= 0 {
 		return nil, fmt.Errorf("cache capacity must be greater than 0")
 	}
 
 	return &QuestionMarkSqlBinder{
-		cache: newTwoGenCache(cacheCapacity),
+		cache:   newTwoGenCache(cacheCapacity),
+		dialect: dialect,
 	}, nil
 }
 
-// defaultQuestionMarkBinder 是默认的包级绑定器。
+// defaultQuestionMarkBinder 是默认的包级绑定器，使用标准 SQL 的方言。
 var defaultQuestionMarkBinder = &QuestionMarkSqlBinder{
-	cache: parsedSqlCache,
+	cache:   parsedSqlCache,
+	dialect: sqltoken.Standard,
 }
 
 // 分析 SQL 语句，提取用到的命名参数名称（按顺序），并将 @ 占位参数转换为驱动支持的 ? 形式。
@@ -53,55 +57,22 @@ func (binder *QuestionMarkSqlBinder) ParseNamedSqlToQuestionMark(sqlText string)
 	}
 
 	names := make([]string, 0, 10) // 存放 SQL 中所有的参数名称。
-
 	fixedSqlTextBuilder := strings.Builder{}
-	paramNameBuilder := strings.Builder{}
-
-	inName := false   // 标示当前字符是否正处于参数名称之中。
-	inString := false // 标示当前字符是否正处于字符串之中。
-
-	lastIndex := utf8.RuneCountInString(sqlText) - 1 // SQL 语句的最后一个字符索引位置。
-
-	for i, currentRune := range sqlText {
-		switch {
-		// 遇到字符串开始/结束符号 ' 需要切换字符串状态。
-		case currentRune == '\'':
-			inString = !inString
-			fixedSqlTextBuilder.WriteRune(currentRune)
-
-		// 如果当前状态正处于字符串中，字符无须特殊处理。
-		case inString:
-			fixedSqlTextBuilder.WriteRune(currentRune)
-
-		// @ 符号标示参数名称部分开始。
-		case currentRune == '@':
-			// 连续 2 个 @ 可以用来转义，需要跳出参数名作用域。
-			if inName && i > 0 && sqlText[i-1] == '@' {
-				fixedSqlTextBuilder.WriteRune(currentRune)
-				inName = false
-				continue
-			}
-			inName = true
-			paramNameBuilder.Reset()
-
-		// 当前状态正处于参数名中，且当前字符合法，应作为参数名的一部分。
-		case inName && isLegalParamNameCharter(currentRune):
-			paramNameBuilder.WriteRune(currentRune)
-			if lastIndex == i { // 如果是最后一个字符，直接写入并结束。
-				fixedSqlTextBuilder.WriteString("?")
-				names = append(names, paramNameBuilder.String())
-			}
 
-		// 当前状态正处于参数名中，且当前字符不是合法参数字符，标示着参数名范围结束。
-		case inName && !isLegalParamNameCharter(currentRune):
-			inName = false
-			fixedSqlTextBuilder.WriteString("?")
-			fixedSqlTextBuilder.WriteRune(currentRune)
-			names = append(names, paramNameBuilder.String())
+	for _, token := range sqltoken.Tokenize(sqlText, binder.dialect) {
+		switch token.Kind {
+		// 命名参数替换为 ? 占位符。
+		case sqltoken.NamedParam:
+			fixedSqlTextBuilder.WriteByte('?')
+			names = append(names, token.Name())
 
-		// 非上述情况即为普通 SQL 字符部分，无须特殊处理。
+		// 连续 2 个 @ 用来转义，输出为 1 个 @ 。
+		case sqltoken.EscapedAt:
+			fixedSqlTextBuilder.WriteByte('@')
+
+		// 字符串、标识符、注释等其余部分原样输出。
 		default:
-			fixedSqlTextBuilder.WriteRune(currentRune)
+			fixedSqlTextBuilder.WriteString(token.Text)
 		}
 	}
 
@@ -113,6 +84,11 @@ func (binder *QuestionMarkSqlBinder) ParseNamedSqlToQuestionMark(sqlText string)
 	return parsedResult
 }
 
+// ParseParamNames 返回 SQL 语句中用到的命名参数名称（按顺序），与 BindQuestionMarkArgs 共用解析缓存。
+func (binder *QuestionMarkSqlBinder) ParseParamNames(sqlText string) []string {
+	return binder.ParseNamedSqlToQuestionMark(sqlText).Names
+}
+
 // 对使用 ? 占位符的驱动（如 MySQL、SQLite）做参数预处理。
 // 若唯一参数为 map，则按 SQL 中的 @name 顺序绑定；否则按 @p1、@p2 形式从 args 切片取位置参数。
 func BindQuestionMarkArgs(sqlText string, args ...any) (string, []any, error) {
@@ -137,7 +113,7 @@ func (binder *QuestionMarkSqlBinder) BindQuestionMarkArgs(sqlText string, args .
 				return "", nil, fmt.Errorf("%w:\nlack of parameter\nsql = %s", sqlmer.ErrParseParamFailed, namedParsedResult.Sql)
 			}
 		}
-		namedParsedResult.Sql, resultArgs = extendInParams(namedParsedResult.Sql, resultArgs)
+		namedParsedResult.Sql, resultArgs = extendInParams(namedParsedResult.Sql, resultArgs, binder.dialect)
 		return namedParsedResult.Sql, resultArgs, nil
 	}
 
@@ -165,66 +141,62 @@ func (binder *QuestionMarkSqlBinder) BindQuestionMarkArgs(sqlText string, args .
 		resultArgs = append(resultArgs, args[index])
 	}
 
-	namedParsedResult.Sql, resultArgs = extendInParams(namedParsedResult.Sql, resultArgs)
+	namedParsedResult.Sql, resultArgs = extendInParams(namedParsedResult.Sql, resultArgs, binder.dialect)
 	return namedParsedResult.Sql, resultArgs, nil
 }
 
 // extendInParams 处理 SQL IN 子句的参数展开，将切片类型的参数展开为多个问号占位符。
-func extendInParams(sqlText string, params []any) (string, []any) {
+// 字符串、引用标识符及注释中的 ? 不是占位符，会原样保留。
+func extendInParams(sqlText string, params []any, dialect sqltoken.Dialect) (string, []any) {
+	// 没有需要展开的参数时，语句和参数都无须变化。
+	if !slices.ContainsFunc(params, isInParam) {
+		return sqlText, params
+	}
+
 	newParams := make([]any, 0, len(params))
 	var newSqlBuilder strings.Builder
 
 	paramIndex := 0
-	inString := false
-	for _, r := range sqlText {
-		if r == '\'' {
-			inString = !inString
-			newSqlBuilder.WriteRune(r)
+	for _, token := range sqltoken.Tokenize(sqlText, dialect) {
+		if token.Kind != sqltoken.QuestionMark || paramIndex >= len(params) { // 非占位符，或后面没参数了，无需判断了。
+			newSqlBuilder.WriteString(token.Text)
 			continue
 		}
 
-		if inString {
-			newSqlBuilder.WriteRune(r)
-			continue
-		}
+		param := params[paramIndex]
+		paramIndex++
 
-		if r != '?' {
-			newSqlBuilder.WriteRune(r)
+		if !isInParam(param) {
+			newSqlBuilder.WriteByte('?')
+			newParams = append(newParams, param)
 			continue
 		}
 
-		if paramIndex >= len(params) { // 后面没参数了，无需判断了。
-			newSqlBuilder.WriteRune(r)
+		paramValue := reflect.ValueOf(param)
+		paramLen := paramValue.Len()
+		if paramLen == 0 {
+			// 空切片替换为 SQL 不可能条件。
+			newSqlBuilder.WriteString("NULL")
 			continue
 		}
 
-		param := params[paramIndex]
-		paramValue := reflect.ValueOf(param)
-		paramIndex++
-
-		// 处理切片类型。
-		// 排除 []byte，因为虽然 []byte 也是切片类型，但它是二进制数据，不应该被展开。
-		if (paramValue.Kind() == reflect.Slice || paramValue.Kind() == reflect.Array) && !paramValue.Type().ConvertibleTo(reflect.TypeOf([]byte{})) {
-			paramLen := paramValue.Len()
-			if paramLen == 0 {
-				// 空切片替换为 SQL 不可能条件。
-				newSqlBuilder.WriteString("NULL")
-				continue
-			}
-
-			// 生成占位符。
-			placeholders := strings.Repeat(",?", paramLen)
-			newSqlBuilder.WriteString(placeholders[1:])
+		// 生成占位符。
+		placeholders := strings.Repeat(",?", paramLen)
+		newSqlBuilder.WriteString(placeholders[1:])
 
-			// 展开参数。
-			for i := 0; i < paramLen; i++ {
-				newParams = append(newParams, paramValue.Index(i).Interface())
-			}
-		} else {
-			newSqlBuilder.WriteRune('?')
-			newParams = append(newParams, param)
+		// 展开参数。
+		for i := 0; i < paramLen; i++ {
+			newParams = append(newParams, paramValue.Index(i).Interface())
 		}
 	}
 
 	return newSqlBuilder.String(), newParams
 }
+
+// isInParam 用于判断参数是否为需要展开的切片类型。
+// 排除 []byte，因为虽然 []byte 也是切片类型，但它是二进制数据，不应该被展开。
+func isInParam(param any) bool {
+	paramValue := reflect.ValueOf(param)
+	return (paramValue.Kind() == reflect.Slice || paramValue.Kind() == reflect.Array) &&
+		!paramValue.Type().ConvertibleTo(reflect.TypeOf([]byte{}))
+}
@@ -3,6 +3,8 @@ package named2qm
 import (
 	"reflect"
 	"testing"
+
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 )
 
 func Test_extendInParams_single(t *testing.T) {
@@ -11,7 +13,7 @@ func Test_extendInParams_single(t *testing.T) {
 	expSQL := "select 1 from t where id = ?"
 	expParams := []any{1}
 
-	gotSQL, gotParams := extendInParams(sql, params)
+	gotSQL, gotParams := extendInParams(sql, params, sqltoken.Standard)
 	if gotSQL != expSQL {
 		t.Errorf("expected sql=%s, got=%s", expSQL, gotSQL)
 	}
@@ -26,7 +28,7 @@ func Test_extendInParams_slice(t *testing.T) {
 	expSQL := "select 1 from t where id in (?,?,?)"
 	expParams := []any{1, 2, 3}
 
-	gotSQL, gotParams := extendInParams(sql, params)
+	gotSQL, gotParams := extendInParams(sql, params, sqltoken.Standard)
 	if gotSQL != expSQL {
 		t.Errorf("expected sql=%s, got=%s", expSQL, gotSQL)
 	}
@@ -41,7 +43,7 @@ func Test_extendInParams_single_with_slice(t *testing.T) {
 	expSQL := "select 1 from t where id!=? AND id in (?,?,?)"
 	expParams := []any{5, 1, 2, 3}
 
-	gotSQL, gotParams := extendInParams(sql, params)
+	gotSQL, gotParams := extendInParams(sql, params, sqltoken.Standard)
 	if gotSQL != expSQL {
 		t.Errorf("expected sql=%s, got=%s", expSQL, gotSQL)
 	}
@@ -56,7 +58,7 @@ func Test_extendInParams_empty_slice(t *testing.T) {
 	expSQL := "select 1 from t where id in (NULL)"
 	expParams := []any{}
 
-	gotSQL, gotParams := extendInParams(sql, params)
+	gotSQL, gotParams := extendInParams(sql, params, sqltoken.Standard)
 	if gotSQL != expSQL {
 		t.Errorf("expected sql=%s, got=%s", expSQL, gotSQL)
 	}
@@ -71,7 +73,22 @@ func Test_extendInParams_regular(t *testing.T) {
 	expSQL := "select 1 from t where name = ? and age = ?"
 	expParams := []any{"Alice", 30}
 
-	gotSQL, gotParams := extendInParams(sql, params)
+	gotSQL, gotParams := extendInParams(sql, params, sqltoken.Standard)
+	if gotSQL != expSQL {
+		t.Errorf("expected sql=%s, got=%s", expSQL, gotSQL)
+	}
+	if !reflect.DeepEqual(gotParams, expParams) {
+		t.Errorf("expected params=%v, got=%v", expParams, gotParams)
+	}
+}
+
+func Test_extendInParams_question_mark_in_comment(t *testing.T) {
+	sql := "select 1 from t /* ? */ where name = '?' and id in (?) -- ?"
+	params := []any{[]int{1, 2}}
+	expSQL := "select 1 from t /* ? */ where name = '?' and id in (?,?) -- ?"
+	expParams := []any{1, 2}
+
+	gotSQL, gotParams := extendInParams(sql, params, sqltoken.Standard)
 	if gotSQL != expSQL {
 		t.Errorf("expected sql=%s, got=%s", expSQL, gotSQL)
 	}
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bunnier/sqlmer/internal/sqltoken"
+
 	"golang.org/x/sync/errgroup"
 )
 
@@ -56,6 +58,35 @@ func Test_ParseNamedSqlToQuestionMark_string_literal_param(t *testing.T) {
 	}
 }
 
+func Test_ParseNamedSqlToQuestionMark_comment_and_quote(t *testing.T) {
+	inputSql := "SELECT \"@a\" FROM go_TypeTest -- @b\nWHERE /* @c */ id=@id AND varcharTest='it''s @d'"
+	expectedSql := "SELECT \"@a\" FROM go_TypeTest -- @b\nWHERE /* @c */ id=? AND varcharTest='it''s @d'"
+	expectedParams := "id"
+
+	result := ParseNamedSqlToQuestionMark(inputSql)
+	if result.Sql != expectedSql || strings.Join(result.Names, ",") != expectedParams {
+		t.Errorf("expected sql=%s, param=%s\nActual sql=%s, param=%s",
+			expectedSql, expectedParams, result.Sql, strings.Join(result.Names, ","))
+	}
+}
+
+func Test_ParseNamedSqlToQuestionMark_mysql_dialect(t *testing.T) {
+	binder, err := NewQuestionMarkSqlBinder(10, sqltoken.MySql)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputSql := "SELECT `@a` FROM go_TypeTest # @b\nWHERE id=@id AND varcharTest='\\'@c'"
+	expectedSql := "SELECT `@a` FROM go_TypeTest # @b\nWHERE id=? AND varcharTest='\\'@c'"
+	expectedParams := "id"
+
+	result := binder.ParseNamedSqlToQuestionMark(inputSql)
+	if result.Sql != expectedSql || strings.Join(result.Names, ",") != expectedParams {
+		t.Errorf("expected sql=%s, param=%s\nActual sql=%s, param=%s",
+			expectedSql, expectedParams, result.Sql, strings.Join(result.Names, ","))
+	}
+}
+
 func Test_ParseNamedSqlToQuestionMark_multiple_params(t *testing.T) {
 	inputSql := "SELECT * FROM go_TypeTest WHERE id=@id AND varcharTest=@varcharTest"
 	expectedSql := "SELECT * FROM go_TypeTest WHERE id=? AND varcharTest=?"
@@ -4,8 +4,8 @@ import (
 	"sync"
 )
 
-// 是双代缓存每代的最大条目数，内存上限为 2 倍该值。
-const _DefaultCacheCapacity = 4096
+// DefaultCacheCapacity 是双代缓存每代的最大条目数，内存上限为 2 倍该值。
+const DefaultCacheCapacity = 4096
 
 //	一个基于双代淘汰策略的有界缓存。
 //
@@ -56,24 +56,4 @@ func (c *twoGenCache) store(key string, value ParsedResult) {
 }
 
 // parsedSqlCache 是 ParseNamedSql 使用的包级共享缓存实例。
-var parsedSqlCache = newTwoGenCache(_DefaultCacheCapacity)
-
-// 用于初始化合法字符集合 map，用于快速筛选合法字符。
-var onceInitParamNameMap = sync.Once{}
-
-// 定义参数名允许的字符。
-var legalParamNameCharactersMap map[rune]struct{}
-
-// isLegalParamNameCharter 用于快速判断某个字符是否是占位符参数合法字符。
-func isLegalParamNameCharter(r rune) bool {
-	onceInitParamNameMap.Do(func() {
-		const legalParamNameCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
-		legalParamNameCharactersMap = make(map[rune]struct{}, len(legalParamNameCharacters))
-		for _, r := range legalParamNameCharacters {
-			legalParamNameCharactersMap[r] = struct{}{}
-		}
-	})
-
-	_, ok := legalParamNameCharactersMap[r]
-	return ok
-}
+var parsedSqlCache = newTwoGenCache(DefaultCacheCapacity)
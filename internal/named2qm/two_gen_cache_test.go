@@ -2,6 +2,8 @@ package named2qm
 
 import (
 	"testing"
+
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 )
 
 func Test_twoGenCache_hit_in_hot(t *testing.T) {
@@ -72,19 +74,19 @@ func Test_twoGenCache_miss(t *testing.T) {
 }
 
 func Test_NewQuestionMarkBinder_invalid_capacity(t *testing.T) {
-	_, err := NewQuestionMarkSqlBinder(0)
+	_, err := NewQuestionMarkSqlBinder(0, sqltoken.Standard)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 }
 
 func Test_QuestionMarkBinder_has_independent_cache(t *testing.T) {
-	binder1, err := NewQuestionMarkSqlBinder(1)
+	binder1, err := NewQuestionMarkSqlBinder(1, sqltoken.Standard)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	binder2, err := NewQuestionMarkSqlBinder(1)
+	binder2, err := NewQuestionMarkSqlBinder(1, sqltoken.Standard)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,204 @@
+// Package sqlscript 提供按数据库方言将包含多条语句的脚本拆分为单条语句（批）的逻辑，
+// 拆分时会跳过字符串、引用标识符及注释中的分隔符。
+package sqlscript
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bunnier/sqlmer/internal/sqltoken"
+)
+
+// Rules 描述了不同数据库拆分脚本的规则。
+type Rules struct {
+	Dialect          sqltoken.Dialect // 词法分析使用的方言。
+	Delimiter        string           // 语句分隔符，为空时不按分隔符拆分。
+	BatchSeparator   bool             // 是否按单独一行的 GO [count] 拆分批（SQL Server 客户端工具的约定）。
+	DelimiterCommand bool             // 是否支持单独一行的 DELIMITER xx 修改分隔符（MySQL 客户端的约定）。
+	TriggerBody      bool             // CREATE TRIGGER 语句的 BEGIN ... END 中的分隔符是否不拆分（SQLite）。
+}
+
+// 各数据库的拆分规则。
+var (
+	Standard  = Rules{Dialect: sqltoken.Standard, Delimiter: ";"}
+	MySql     = Rules{Dialect: sqltoken.MySql, Delimiter: ";", DelimiterCommand: true}
+	Sqlite    = Rules{Dialect: sqltoken.Sqlite, Delimiter: ";", TriggerBody: true}
+	SqlServer = Rules{Dialect: sqltoken.SqlServer, BatchSeparator: true} // 同一批中的多条语句一起发送给数据库。
+	Postgres  = Rules{Dialect: sqltoken.Postgres, Delimiter: ";"}
+)
+
+var (
+	batchSeparatorPattern   = regexp.MustCompile(`(?i)^GO(?:\s+(\d+))?$`)
+	delimiterCommandPattern = regexp.MustCompile(`(?i)^DELIMITER\s+(\S+)$`)
+)
+
+// Split 按 rules 将脚本拆分为语句，返回的语句去掉了首尾的空白字符及分隔符，只包含注释和空白字符的语句会被忽略。
+func Split(script string, rules Rules) []string {
+	s := &splitter{rules: rules, delimiter: rules.Delimiter}
+	tokens := sqltoken.Tokenize(script, rules.Dialect)
+
+	lineStart := true // 当前记号是否从行首开始。
+	for i, token := range tokens {
+		if token.Kind != sqltoken.Text {
+			s.endWord()
+			s.current.WriteString(token.Text)
+			if token.Kind != sqltoken.LineComment && token.Kind != sqltoken.BlockComment {
+				s.hasContent = true
+			}
+			lineStart = false
+			continue
+		}
+
+		// 记号中最后一行之后若紧跟着行注释，该行依然是完整的一行，如 GO -- comment 。
+		lastLineComplete := i+1 == len(tokens) || tokens[i+1].Kind == sqltoken.LineComment
+		s.scanText(token.Text, lineStart, lastLineComplete)
+		lineStart = strings.HasSuffix(token.Text, "\n")
+	}
+
+	s.endWord()
+	s.flush(1)
+	return s.statements
+}
+
+// splitter 保存拆分过程中的状态。
+type splitter struct {
+	rules      Rules
+	delimiter  string // 当前的分隔符，可能被 DELIMITER 命令修改。
+	statements []string
+
+	current    strings.Builder // 当前语句。
+	hasContent bool            // 当前语句是否包含注释和空白字符以外的内容。
+
+	word    strings.Builder // 正在读取的单词。
+	head    []string        // 当前语句的前 3 个单词（大写），用于识别 CREATE [TEMP] TRIGGER 。
+	trigger bool            // 当前语句是否为 CREATE TRIGGER 语句。
+	depth   int             // CREATE TRIGGER 语句中 BEGIN / CASE ... END 的嵌套层数。
+}
+
+// scanText 用于处理普通文本记号，识别其中的分隔符及单独成行的命令。
+func (s *splitter) scanText(text string, lineStart bool, lastLineComplete bool) {
+	for pos := 0; pos < len(text); {
+		c := text[pos]
+		if !sqltoken.IsParamNameChar(c) {
+			s.endWord()
+		}
+
+		if pos == 0 && lineStart || pos > 0 && text[pos-1] == '\n' {
+			lineEnd := strings.IndexByte(text[pos:], '\n')
+			if lineEnd >= 0 || lastLineComplete {
+				if lineEnd < 0 {
+					lineEnd = len(text) - pos
+				}
+				if s.command(strings.TrimSpace(text[pos : pos+lineEnd])) {
+					pos += lineEnd
+					continue
+				}
+			}
+		}
+
+		if s.delimiter != "" && strings.HasPrefix(text[pos:], s.delimiter) && !(s.trigger && s.depth > 0) {
+			s.flush(1)
+			pos += len(s.delimiter)
+			continue
+		}
+
+		s.current.WriteByte(c)
+		if sqltoken.IsParamNameChar(c) {
+			s.word.WriteByte(c)
+		}
+		if !isSpace(c) {
+			s.hasContent = true
+		}
+		pos++
+	}
+	// 文本记号结束时，单词也随之结束。
+	s.endWord()
+}
+
+// command 用于处理单独成行的 GO 、 DELIMITER 命令，不是命令时返回 false 。
+func (s *splitter) command(line string) bool {
+	if s.rules.BatchSeparator {
+		if matches := batchSeparatorPattern.FindStringSubmatch(line); matches != nil {
+			count := 1
+			if matches[1] != "" {
+				count, _ = strconv.Atoi(matches[1])
+			}
+			s.flush(count)
+			return true
+		}
+	}
+
+	if s.rules.DelimiterCommand {
+		if matches := delimiterCommandPattern.FindStringSubmatch(line); matches != nil {
+			s.flush(1)
+			s.delimiter = matches[1]
+			return true
+		}
+	}
+
+	return false
+}
+
+// endWord 用于结束正在读取的单词，并据此识别 CREATE TRIGGER 语句及其中 BEGIN ... END 的嵌套。
+func (s *splitter) endWord() {
+	if s.word.Len() == 0 {
+		return
+	}
+	word := strings.ToUpper(s.word.String())
+	s.word.Reset()
+	if !s.rules.TriggerBody {
+		return
+	}
+
+	if len(s.head) < 3 {
+		s.head = append(s.head, word)
+		s.trigger = isCreateTrigger(s.head)
+		return
+	}
+
+	if s.trigger {
+		switch word {
+		case "BEGIN", "CASE":
+			s.depth++
+		case "END":
+			s.depth--
+		}
+	}
+}
+
+// isCreateTrigger 用于根据语句开头的单词判断是否为 CREATE [TEMP | TEMPORARY] TRIGGER 语句。
+func isCreateTrigger(head []string) bool {
+	if len(head) < 2 || head[0] != "CREATE" {
+		return false
+	}
+	if head[1] == "TRIGGER" {
+		return true
+	}
+	return len(head) == 3 && (head[1] == "TEMP" || head[1] == "TEMPORARY") && head[2] == "TRIGGER"
+}
+
+// flush 用于结束当前语句，有内容时重复 count 次加入结果。
+func (s *splitter) flush(count int) {
+	if s.hasContent {
+		statement := strings.TrimSpace(s.current.String())
+		for i := 0; i < count; i++ {
+			s.statements = append(s.statements, statement)
+		}
+	}
+
+	s.current.Reset()
+	s.hasContent = false
+	s.head = s.head[:0]
+	s.trigger = false
+	s.depth = 0
+}
+
+// isSpace 用于判断字符是否为空白字符。
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package sqlscript
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Split(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  Rules
+		script string
+		want   []string
+	}{
+		{
+			name:   "delimiter",
+			rules:  Standard,
+			script: "CREATE TABLE t (id INT);\n\nINSERT INTO t VALUES (1) ; ;\nSELECT 1",
+			want:   []string{"CREATE TABLE t (id INT)", "INSERT INTO t VALUES (1)", "SELECT 1"},
+		},
+		{
+			name:   "quotes_and_comments",
+			rules:  Standard,
+			script: "INSERT INTO t VALUES ('a;b', \"c;d\"); -- e;f\n/* g;h */ SELECT 1;\n-- only comment;",
+			want:   []string{"INSERT INTO t VALUES ('a;b', \"c;d\")", "-- e;f\n/* g;h */ SELECT 1"},
+		},
+		{
+			name:   "mysql_delimiter",
+			rules:  MySql,
+			script: "DROP PROCEDURE IF EXISTS p;\nDELIMITER $$\nCREATE PROCEDURE p()\nBEGIN\n  SELECT 'a$$b';\n  SELECT 2;\nEND$$\ndelimiter ;\nCALL p();",
+			want: []string{
+				"DROP PROCEDURE IF EXISTS p",
+				"CREATE PROCEDURE p()\nBEGIN\n  SELECT 'a$$b';\n  SELECT 2;\nEND",
+				"CALL p()",
+			},
+		},
+		{
+			name:   "mysql_escape",
+			rules:  MySql,
+			script: "SELECT 'a\\';b'; # c;d\nSELECT @x;",
+			want:   []string{"SELECT 'a\\';b'", "# c;d\nSELECT @x"},
+		},
+		{
+			name:   "sqlserver_go",
+			rules:  SqlServer,
+			script: "CREATE TABLE t (id INT); INSERT INTO t VALUES (1);\nGO\nCREATE PROCEDURE p AS\nBEGIN\n  SELECT 'GO';\nEND\r\ngo -- comment\nINSERT INTO t VALUES (2)\nGO 2\n  GO  \nSELECT @@ROWCOUNT -- GO\n/*\nGO\n*/",
+			want: []string{
+				"CREATE TABLE t (id INT); INSERT INTO t VALUES (1);",
+				"CREATE PROCEDURE p AS\nBEGIN\n  SELECT 'GO';\nEND",
+				"-- comment\nINSERT INTO t VALUES (2)",
+				"-- comment\nINSERT INTO t VALUES (2)",
+				"SELECT @@ROWCOUNT -- GO\n/*\nGO\n*/",
+			},
+		},
+		{
+			name:   "sqlserver_go_in_identifier",
+			rules:  SqlServer,
+			script: "SELECT 1 AS [\nGO\n]\nGOTO label",
+			want:   []string{"SELECT 1 AS [\nGO\n]\nGOTO label"},
+		},
+		{
+			name:   "sqlite_trigger",
+			rules:  Sqlite,
+			script: "CREATE TABLE t (id INTEGER, n INTEGER);\nCREATE TEMP TRIGGER tr AFTER INSERT ON t\nBEGIN\n  UPDATE t SET n = CASE WHEN n IS NULL THEN 0 ELSE n END;\n  SELECT 1;\nEND;\nBEGIN; END;",
+			want: []string{
+				"CREATE TABLE t (id INTEGER, n INTEGER)",
+				"CREATE TEMP TRIGGER tr AFTER INSERT ON t\nBEGIN\n  UPDATE t SET n = CASE WHEN n IS NULL THEN 0 ELSE n END;\n  SELECT 1;\nEND",
+				"BEGIN",
+				"END",
+			},
+		},
+		{
+			name:   "postgres_dollar_quote",
+			rules:  Postgres,
+			script: "CREATE FUNCTION f() RETURNS int AS $body$\nBEGIN\n  RETURN 1;\nEND;\n$body$ LANGUAGE plpgsql;\nSELECT f();",
+			want: []string{
+				"CREATE FUNCTION f() RETURNS int AS $body$\nBEGIN\n  RETURN 1;\nEND;\n$body$ LANGUAGE plpgsql",
+				"SELECT f()",
+			},
+		},
+		{
+			name:   "empty",
+			rules:  Standard,
+			script: " ;\n-- comment\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Split(tt.script, tt.rules); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
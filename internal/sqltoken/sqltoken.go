@@ -0,0 +1,262 @@
+// Package sqltoken 提供了按数据库方言切分 SQL 语句的词法分析，可以识别字符串、引用标识符、注释及命名参数，
+// 供各驱动的命名参数解析共用，避免将注释、字符串中的 @ 、 ? 误认为参数。
+package sqltoken
+
+import "strings"
+
+// Kind 是记号的类型。
+type Kind int
+
+const (
+	Text         Kind = iota // 普通 SQL 文本。
+	String                   // 字符串字面量，如 'it''s' 、 MySQL 中的 "text" 、 PostgreSQL 中的 $$text$$ 。
+	QuotedIdent              // 引用标识符，如 "name" 、 `name` 、 [name] 。
+	LineComment              // 行注释，如 -- comment 、 MySQL 中的 # comment ，不含结尾的换行符。
+	BlockComment             // 块注释，如 /* comment */ 。
+	NamedParam               // 命名参数，如 @name 。
+	EscapedAt                // 用于转义 @ 的 @@ 。
+	QuestionMark             // ? 占位符。
+)
+
+// Token 是 SQL 语句中的一个记号。
+type Token struct {
+	Kind Kind
+	Text string // 记号在原语句中的文本，所有记号的 Text 按顺序拼接即为原语句。
+}
+
+// Name 返回命名参数的名称（不含 @ ），其它类型的记号返回空字符串。
+func (t Token) Name() string {
+	if t.Kind != NamedParam {
+		return ""
+	}
+	return t.Text[1:]
+}
+
+// Dialect 描述了不同数据库在词法上的差异。
+type Dialect struct {
+	BackslashEscape       bool // 字符串中的 \ 是否为转义符（MySQL）。
+	DoubleQuoteString     bool // " 引用的是否为字符串而不是标识符（MySQL 默认的 SQL 模式）。
+	Backtick              bool // 是否支持 `name` 形式的引用标识符（MySQL、SQLite）。
+	Bracket               bool // 是否支持 [name] 形式的引用标识符（SQL Server、SQLite）。
+	HashComment           bool // 是否支持 # 开头的行注释（MySQL）。
+	DashCommentNeedsSpace bool // -- 后是否必须跟空白字符才构成注释（MySQL）。
+	NestedComment         bool // 块注释是否可以嵌套（SQL Server、PostgreSQL）。
+	DollarQuote           bool // 是否支持 $tag$...$tag$ 形式的字符串（PostgreSQL）。
+}
+
+// 各数据库的方言。
+var (
+	Standard  = Dialect{}
+	MySql     = Dialect{BackslashEscape: true, DoubleQuoteString: true, Backtick: true, HashComment: true, DashCommentNeedsSpace: true}
+	Sqlite    = Dialect{Backtick: true, Bracket: true}
+	SqlServer = Dialect{Bracket: true, NestedComment: true}
+	Postgres  = Dialect{NestedComment: true, DollarQuote: true}
+)
+
+// Tokenize 按方言将 SQL 语句切分为记号。
+// 未闭合的字符串、标识符、注释会一直延续到语句结尾；相邻的普通文本会合并为一个 Text 记号。
+func Tokenize(sqlText string, dialect Dialect) []Token {
+	tokens := make([]Token, 0, 8)
+	textStart := 0 // 当前尚未输出的普通文本的起始位置。
+
+	emit := func(kind Kind, start, end int) {
+		if textStart < start {
+			tokens = append(tokens, Token{Text, sqlText[textStart:start]})
+		}
+		tokens = append(tokens, Token{kind, sqlText[start:end]})
+		textStart = end
+	}
+
+	// 所有需要识别的符号都是 ASCII 字符，不会出现在 UTF-8 多字节字符的编码中，因此可以按字节扫描。
+	for i := 0; i < len(sqlText); {
+		c := sqlText[i]
+		switch {
+		case c == '\'':
+			end := scanQuoted(sqlText, i, '\'', dialect.BackslashEscape)
+			emit(String, i, end)
+			i = end
+
+		case c == '"':
+			end := scanQuoted(sqlText, i, '"', dialect.BackslashEscape && dialect.DoubleQuoteString)
+			if dialect.DoubleQuoteString {
+				emit(String, i, end)
+			} else {
+				emit(QuotedIdent, i, end)
+			}
+			i = end
+
+		case c == '`' && dialect.Backtick:
+			end := scanQuoted(sqlText, i, '`', false)
+			emit(QuotedIdent, i, end)
+			i = end
+
+		case c == '[' && dialect.Bracket:
+			end := scanQuoted(sqlText, i, ']', false)
+			emit(QuotedIdent, i, end)
+			i = end
+
+		case c == '-' && strings.HasPrefix(sqlText[i:], "--") &&
+			(!dialect.DashCommentNeedsSpace || i+2 == len(sqlText) || isSpace(sqlText[i+2])):
+			end := scanLineComment(sqlText, i)
+			emit(LineComment, i, end)
+			i = end
+
+		case c == '#' && dialect.HashComment:
+			end := scanLineComment(sqlText, i)
+			emit(LineComment, i, end)
+			i = end
+
+		case c == '/' && strings.HasPrefix(sqlText[i:], "/*"):
+			end := scanBlockComment(sqlText, i, dialect.NestedComment)
+			emit(BlockComment, i, end)
+			i = end
+
+		case c == '$' && dialect.DollarQuote:
+			end, ok := scanDollarQuoted(sqlText, i)
+			if !ok {
+				i++
+				continue
+			}
+			emit(String, i, end)
+			i = end
+
+		case c == '@' && i+1 < len(sqlText) && sqlText[i+1] == '@':
+			emit(EscapedAt, i, i+2)
+			i += 2
+
+		case c == '@':
+			end := i + 1
+			for end < len(sqlText) && IsParamNameChar(sqlText[end]) {
+				end++
+			}
+			if end == i+1 { // 后面没有合法的参数名字符，不是参数，如 PostgreSQL 的 @> 操作符。
+				i++
+				continue
+			}
+			emit(NamedParam, i, end)
+			i = end
+
+		case c == '?':
+			emit(QuestionMark, i, i+1)
+			i++
+
+		default:
+			i++
+		}
+	}
+
+	if textStart < len(sqlText) {
+		tokens = append(tokens, Token{Text, sqlText[textStart:]})
+	}
+	return tokens
+}
+
+// TrimStatementEnd 用于去掉语句末尾的注释、空白及 ; ，返回剩余的语句，以便在语句末尾追加子句。
+func TrimStatementEnd(tokens []Token) string {
+	end := len(tokens)
+	lastText := ""
+	for ; end > 0; end-- {
+		token := tokens[end-1]
+		if token.Kind == LineComment || token.Kind == BlockComment {
+			continue
+		}
+		if token.Kind == Text {
+			if lastText = strings.TrimRight(token.Text, "; \t\r\n"); lastText == "" {
+				continue
+			}
+			end-- // 最后一个非空的普通文本去掉末尾部分后单独拼接。
+		}
+		break
+	}
+
+	var sb strings.Builder
+	for _, token := range tokens[:end] {
+		sb.WriteString(token.Text)
+	}
+	sb.WriteString(lastText)
+	return sb.String()
+}
+
+// IsParamNameChar 用于判断某个字符是否可以作为参数名称的一部分（字母、数字和下划线）。
+func IsParamNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// scanQuoted 返回从 start 处的引号开始、到 closing 结束的引用内容的结束位置（不含）。
+// 连续 2 个 closing 视为转义；backslashEscape 为 true 时， \ 会转义其后的一个字符。
+func scanQuoted(sqlText string, start int, closing byte, backslashEscape bool) int {
+	for i := start + 1; i < len(sqlText); i++ {
+		switch sqlText[i] {
+		case '\\':
+			if backslashEscape {
+				i++
+			}
+		case closing:
+			if i+1 < len(sqlText) && sqlText[i+1] == closing {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(sqlText)
+}
+
+// scanLineComment 返回从 start 开始的行注释的结束位置（不含换行符）。
+func scanLineComment(sqlText string, start int) int {
+	if end := strings.IndexAny(sqlText[start:], "\r\n"); end >= 0 {
+		return start + end
+	}
+	return len(sqlText)
+}
+
+// scanBlockComment 返回从 start 处的 /* 开始的块注释的结束位置（不含）。
+func scanBlockComment(sqlText string, start int, nested bool) int {
+	depth := 0
+	for i := start; i+1 < len(sqlText); i++ {
+		switch {
+		case sqlText[i] == '/' && sqlText[i+1] == '*' && (depth == 0 || nested):
+			depth++
+			i++
+		case sqlText[i] == '*' && sqlText[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(sqlText)
+}
+
+// scanDollarQuoted 返回从 start 处的 $tag$ 开始的字符串的结束位置（不含）；
+// 若 start 处不是合法的 $tag$ （如 $1 占位符），返回 false 。
+func scanDollarQuoted(sqlText string, start int) (int, bool) {
+	tagEnd := start + 1
+	for tagEnd < len(sqlText) && sqlText[tagEnd] != '$' {
+		c := sqlText[tagEnd]
+		// 标签的规则同不带引号的标识符，但不能以数字开头。
+		if !IsParamNameChar(c) && c < 0x80 || tagEnd == start+1 && c >= '0' && c <= '9' {
+			return 0, false
+		}
+		tagEnd++
+	}
+	if tagEnd == len(sqlText) {
+		return 0, false
+	}
+
+	tag := sqlText[start : tagEnd+1]
+	if end := strings.Index(sqlText[tagEnd+1:], tag); end >= 0 {
+		return tagEnd + 1 + end + len(tag), true
+	}
+	return len(sqlText), true
+}
+
+// isSpace 用于判断字符是否为空白字符。
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package sqltoken
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Tokenize(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		sqlText string
+		want    []Token
+	}{
+		{
+			name:    "params",
+			dialect: Standard,
+			sqlText: "SELECT * FROM t WHERE id=@id AND name=@p_1",
+			want: []Token{
+				{Text, "SELECT * FROM t WHERE id="}, {NamedParam, "@id"}, {Text, " AND name="}, {NamedParam, "@p_1"},
+			},
+		},
+		{
+			name:    "escaped_at",
+			dialect: Standard,
+			sqlText: "@@id @@@p1 @> @",
+			want: []Token{
+				{EscapedAt, "@@"}, {Text, "id "}, {EscapedAt, "@@"}, {NamedParam, "@p1"}, {Text, " @> @"},
+			},
+		},
+		{
+			name:    "doubled_quote",
+			dialect: Standard,
+			sqlText: "'it''s @a' @b",
+			want:    []Token{{String, "'it''s @a'"}, {Text, " "}, {NamedParam, "@b"}},
+		},
+		{
+			name:    "double_quote_ident",
+			dialect: Standard,
+			sqlText: `"a""@b" ?`,
+			want:    []Token{{QuotedIdent, `"a""@b"`}, {Text, " "}, {QuestionMark, "?"}},
+		},
+		{
+			name:    "comments",
+			dialect: Standard,
+			sqlText: "-- @a ?\n/* @b ? */@c",
+			want:    []Token{{LineComment, "-- @a ?"}, {Text, "\n"}, {BlockComment, "/* @b ? */"}, {NamedParam, "@c"}},
+		},
+		{
+			name:    "unterminated",
+			dialect: Standard,
+			sqlText: "@a '@b",
+			want:    []Token{{NamedParam, "@a"}, {Text, " "}, {String, "'@b"}},
+		},
+		{
+			name:    "mysql_backslash",
+			dialect: MySql,
+			sqlText: `'a\'@b' "c\"@d" @e`,
+			want:    []Token{{String, `'a\'@b'`}, {Text, " "}, {String, `"c\"@d"`}, {Text, " "}, {NamedParam, "@e"}},
+		},
+		{
+			name:    "mysql_backtick_and_hash",
+			dialect: MySql,
+			sqlText: "`@a` # @b\n@c",
+			want:    []Token{{QuotedIdent, "`@a`"}, {Text, " "}, {LineComment, "# @b"}, {Text, "\n"}, {NamedParam, "@c"}},
+		},
+		{
+			name:    "mysql_dash_without_space",
+			dialect: MySql,
+			sqlText: "1--@a",
+			want:    []Token{{Text, "1--"}, {NamedParam, "@a"}},
+		},
+		{
+			name:    "standard_backslash",
+			dialect: Standard,
+			sqlText: `'a\' @b`,
+			want:    []Token{{String, `'a\'`}, {Text, " "}, {NamedParam, "@b"}},
+		},
+		{
+			name:    "bracket",
+			dialect: SqlServer,
+			sqlText: "[a]]@b] @c",
+			want:    []Token{{QuotedIdent, "[a]]@b]"}, {Text, " "}, {NamedParam, "@c"}},
+		},
+		{
+			name:    "nested_comment",
+			dialect: SqlServer,
+			sqlText: "/* /* @a */ @b */@c",
+			want:    []Token{{BlockComment, "/* /* @a */ @b */"}, {NamedParam, "@c"}},
+		},
+		{
+			name:    "not_nested_comment",
+			dialect: MySql,
+			sqlText: "/* /* @a */@b",
+			want:    []Token{{BlockComment, "/* /* @a */"}, {NamedParam, "@b"}},
+		},
+		{
+			name:    "dollar_quote",
+			dialect: Postgres,
+			sqlText: "$1 $$@a$$ $tag$ $$ @b $tag$ @c",
+			want: []Token{
+				{Text, "$1 "}, {String, "$$@a$$"}, {Text, " "}, {String, "$tag$ $$ @b $tag$"}, {Text, " "}, {NamedParam, "@c"},
+			},
+		},
+		{
+			name:    "unicode", // 参数名只支持 ASCII 字符，非 ASCII 字符之前的 @ 不是参数。
+			dialect: Standard,
+			sqlText: "'行@a' 列=@列1",
+			want:    []Token{{String, "'行@a'"}, {Text, " 列=@列1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tokenize(tt.sqlText, tt.dialect)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Token_Name(t *testing.T) {
+	if got := (Token{NamedParam, "@id"}).Name(); got != "id" {
+		t.Errorf("Name() = %v, want id", got)
+	}
+	if got := (Token{Text, "@id"}).Name(); got != "" {
+		t.Errorf("Name() = %v, want empty", got)
+	}
+}
+
+func Test_TrimStatementEnd(t *testing.T) {
+	tests := []struct {
+		sqlText string
+		want    string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT 1;  \n", "SELECT 1"},
+		{"SELECT 1; -- note\n", "SELECT 1"},
+		{"SELECT 1 /* a */ ; ; /* b */", "SELECT 1"},
+		{"SELECT ';' -- x", "SELECT ';'"},
+		{"SELECT /* ; */ 1", "SELECT /* ; */ 1"},
+		{"-- only comment", ""},
+	}
+
+	for _, tt := range tests {
+		if got := TrimStatementEnd(Tokenize(tt.sqlText, Standard)); got != tt.want {
+			t.Errorf("TrimStatementEnd(%q) = %q, want %q", tt.sqlText, got, tt.want)
+		}
+	}
+}
+
+func FuzzTokenize(f *testing.F) {
+	seeds := []string{
+		"SELECT * FROM t WHERE id=@id",
+		"SELECT '@a''b', \"@c\"\"d\", `@e`, [@f]]g] FROM t WHERE x IN (@p1) -- @g\n AND y=?",
+		"/* /* @a */ */ @b # @c\n@@ROWCOUNT",
+		`'a\'@b' "c\"@d" 1--@e`,
+		"$1 $$@a$$ $tag$@b$tag$ $x",
+		"'unterminated @a",
+		"/* unterminated @a",
+		"@",
+		"@@@",
+		"'行@a' 列=@列1",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	dialects := []Dialect{Standard, MySql, Sqlite, SqlServer, Postgres}
+	f.Fuzz(func(t *testing.T, sqlText string) {
+		for _, dialect := range dialects {
+			tokens := Tokenize(sqlText, dialect)
+
+			var sb strings.Builder
+			for i, token := range tokens {
+				if token.Text == "" {
+					t.Fatalf("Tokenize(%q) token %d is empty", sqlText, i)
+				}
+				if token.Kind == Text && i > 0 && tokens[i-1].Kind == Text {
+					t.Fatalf("Tokenize(%q) got adjacent text tokens at %d", sqlText, i)
+				}
+				if token.Kind == NamedParam && (len(token.Text) < 2 || token.Text[0] != '@') {
+					t.Fatalf("Tokenize(%q) got an invalid param %q", sqlText, token.Text)
+				}
+				sb.WriteString(token.Text)
+			}
+
+			// 切分不能丢失或改变任何字符。
+			if sb.String() != sqlText {
+				t.Fatalf("Tokenize(%q) joined = %q", sqlText, sb.String())
+			}
+		}
+	})
+}
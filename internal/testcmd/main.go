@@ -9,6 +9,7 @@ import (
 	"github.com/bunnier/sqlmer/internal/testenv"
 	"github.com/bunnier/sqlmer/mssql"
 	"github.com/bunnier/sqlmer/mysql"
+	"github.com/bunnier/sqlmer/postgres"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -60,6 +61,15 @@ func Prepare() {
 		return testenv.CreateMysqlSchema(db)
 	})
 
+	// 初始化 PostgreSQL 测试表。
+	errgroup.Go(func() error {
+		db, err := getDb(postgres.DriverName, testenv.TestConf.Postgres)
+		if err != nil {
+			return err
+		}
+		return testenv.CreatePostgresSchema(db)
+	})
+
 	if err := errgroup.Wait(); err != nil {
 		log.Fatal(err)
 	}
@@ -87,6 +97,15 @@ func Clean() {
 		return testenv.DropMysqlSchema(db)
 	})
 
+	// 销毁 PostgreSQL 测试表。
+	errgroup.Go(func() error {
+		db, err := getDb(postgres.DriverName, testenv.TestConf.Postgres)
+		if err != nil {
+			return err
+		}
+		return testenv.DropPostgresSchema(db)
+	})
+
 	if err := errgroup.Wait(); err != nil {
 		log.Fatal(err)
 	}
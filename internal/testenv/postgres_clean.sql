@@ -0,0 +1 @@
+DROP TABLE go_TypeTest;
\ No newline at end of file
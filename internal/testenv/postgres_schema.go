@@ -0,0 +1,97 @@
+package testenv
+
+import "database/sql"
+
+var postgresSchemas []schema = []schema{
+	{
+		Create: `
+CREATE TABLE go_TypeTest (
+	id SERIAL PRIMARY KEY,
+	intTest INT NOT NULL,
+	smallIntTest SMALLINT NOT NULL,
+	bigIntTest BIGINT NOT NULL,
+	varcharTest VARCHAR(10) NOT NULL,
+	charTest CHAR(10) NOT NULL,
+	charTextTest TEXT NOT NULL,
+	dateTest DATE NOT NULL,
+	timestampTest TIMESTAMP NOT NULL,
+	timestamptzTest TIMESTAMPTZ NOT NULL,
+	realTest REAL NOT NULL,
+	doubleTest DOUBLE PRECISION NOT NULL,
+	numericTest NUMERIC(15, 10) NOT NULL,
+	boolTest BOOLEAN NOT NULL,
+	uuidTest UUID NOT NULL,
+	jsonbTest JSONB NOT NULL,
+	intArrayTest INT[] NOT NULL,
+	textArrayTest TEXT[] NOT NULL,
+	byteaTest BYTEA NOT NULL,
+
+	nullIntTest INT NULL,
+	nullVarcharTest VARCHAR(10) NULL,
+	nullTimestamptzTest TIMESTAMPTZ NULL,
+	nullNumericTest NUMERIC(15, 10) NULL,
+	nullUuidTest UUID NULL,
+	nullJsonbTest JSONB NULL
+);
+`,
+		Drop: `DROP TABLE go_TypeTest`,
+	},
+	{
+		Create: `
+INSERT INTO go_TypeTest(intTest, smallIntTest, bigIntTest, varcharTest, charTest, charTextTest, dateTest, timestampTest, timestamptzTest, realTest, doubleTest, numericTest, boolTest, uuidTest, jsonbTest, intArrayTest, textArrayTest, byteaTest)
+VALUES (1, 1, 1, '行1', '行1char', '行1text', '2021-07-01', '2021-07-01 15:38:50.425', '2021-07-01 15:38:50.425+00', 1.456, 1.15678, 1.45678999, TRUE, '00000000-0000-0000-0000-000000000001', '{"a": 1}', '{1,2,3}', '{"a","b"}', '\x01');
+`,
+		Drop: ``,
+	},
+	{
+		Create: `
+INSERT INTO go_TypeTest(intTest, smallIntTest, bigIntTest, varcharTest, charTest, charTextTest, dateTest, timestampTest, timestamptzTest, realTest, doubleTest, numericTest, boolTest, uuidTest, jsonbTest, intArrayTest, textArrayTest, byteaTest)
+VALUES (2, 2, 2, '行2', '行2char', '行2text', '2021-07-02', '2021-07-02 15:38:50.425', '2021-07-02 15:38:50.425+00', 2.456, 2.15678, 2.45678999, TRUE, '00000000-0000-0000-0000-000000000002', '{"a": 2}', '{2,3,4}', '{"b","c"}', '\x02');
+`,
+		Drop: ``,
+	},
+	{
+		Create: `
+INSERT INTO go_TypeTest(intTest, smallIntTest, bigIntTest, varcharTest, charTest, charTextTest, dateTest, timestampTest, timestamptzTest, realTest, doubleTest, numericTest, boolTest, uuidTest, jsonbTest, intArrayTest, textArrayTest, byteaTest,
+	nullIntTest, nullVarcharTest, nullTimestamptzTest, nullNumericTest, nullUuidTest, nullJsonbTest)
+VALUES (3, 3, 3, '行3', '行3char', '行3text', '2021-07-03', '2021-07-03 15:38:50.425', '2021-07-03 15:38:50.425+00', 3.456, 3.15678, 3.45678999, FALSE, '00000000-0000-0000-0000-000000000003', '{"a": 3}', '{3,4,5}', '{"c","d"}', '\x03',
+	3, '行3', '2021-07-03 15:38:50.425+00', 3.45678999, '00000000-0000-0000-0000-000000000003', '{"a": 3}');
+`,
+		Drop: ``,
+	},
+}
+
+// CreatePostgresSchema 用于初始化 PostgreSQL 的测试用表结构。
+func CreatePostgresSchema(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, schema := range postgresSchemas {
+		_, err := tx.Exec(schema.Create)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DropPostgresSchema 用于销毁 PostgreSQL 的测试用表结构。
+func DropPostgresSchema(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, schema := range postgresSchemas {
+		if schema.Drop == "" {
+			continue
+		}
+		_, err := tx.Exec(schema.Drop)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
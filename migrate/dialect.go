@@ -0,0 +1,260 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+)
+
+// Dialect 用于处理迁移在不同数据库上的差异：迁移记录表的建表语句，以及防止并发执行迁移的锁。
+type Dialect interface {
+	// CreateTableSql 返回创建迁移记录表的语句，表已存在时不能报错。
+	// 表包含 version （主键）、 name 、 applied_at 三列， table 已经过校验，可以直接拼接到语句中。
+	CreateTableSql(table string) string
+
+	// Lock 用于获取名为 name 的迁移锁， timeout 为等待锁的超时时间，小于等于 0 时一直等待。
+	// 返回的 runner 用于执行迁移， release 用于释放锁；锁被其它进程占用超时时，返回的错误需包装 ErrLocked 。
+	Lock(ctx context.Context, dbClient sqlmer.DbClient, name string, timeout time.Duration) (runner sqlmer.DbClient, release func() error, err error)
+}
+
+var _ Dialect = MySqlDialect{}
+var _ Dialect = SqlServerDialect{}
+var _ Dialect = SqliteDialect{}
+var _ Dialect = PostgresDialect{}
+var _ sqlmer.ScriptExecutor = (*savepointTx)(nil)
+
+// MySqlDialect 是 MySQL 的 Dialect ，通过 GET_LOCK 加锁。
+//
+// GET_LOCK 的锁属于会话，因此加锁时会通过一个事务占用一个连接直到释放锁，迁移本身在另一个连接上执行，
+// 连接池的最大连接数不能小于 2 。另外， MySQL 的 DDL 语句会隐式提交事务，包含 DDL 的迁移失败时无法完整回滚。
+type MySqlDialect struct{}
+
+// CreateTableSql 返回创建迁移记录表的语句。
+func (MySqlDialect) CreateTableSql(table string) string {
+	return "CREATE TABLE IF NOT EXISTS " + table + " (" +
+		"version BIGINT NOT NULL PRIMARY KEY, " +
+		"name VARCHAR(255) NOT NULL, " +
+		"applied_at DATETIME(6) NOT NULL)"
+}
+
+// Lock 用于通过 GET_LOCK 获取迁移锁。
+func (MySqlDialect) Lock(ctx context.Context, dbClient sqlmer.DbClient, name string, timeout time.Duration) (sqlmer.DbClient, func() error, error) {
+	tx, err := dbClient.CreateTransactionContext(context.WithoutCancel(ctx), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("migrate: begin lock transaction: %w", err)
+	}
+
+	seconds := -1 // 负数表示一直等待。
+	if timeout > 0 {
+		seconds = int(math.Ceil(timeout.Seconds()))
+	}
+	acquired, _, err := tx.ScalarContext(ctx, "SELECT GET_LOCK(@p1, @p2)", name, seconds)
+	if err != nil {
+		tx.Close()
+		return nil, nil, fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+	if n, _ := toInt64(acquired); n != 1 { // 0 为超时， NULL 为出错（如连接被 kill ）。
+		tx.Close()
+		return nil, nil, fmt.Errorf("%w: GET_LOCK(%q) returned %v", ErrLocked, name, acquired)
+	}
+
+	release := func() error {
+		_, err := tx.Execute("DO RELEASE_LOCK(@p1)", name)
+		return errors.Join(err, tx.Commit())
+	}
+	return dbClient, release, nil
+}
+
+// SqlServerDialect 是 SQL Server 的 Dialect ，通过 sp_getapplock 加锁。
+//
+// 锁的所有者是一个专门的事务，因此加锁时会占用一个连接直到释放锁，迁移本身在另一个连接上执行，
+// 连接池的最大连接数不能小于 2 。
+type SqlServerDialect struct{}
+
+// CreateTableSql 返回创建迁移记录表的语句。
+func (SqlServerDialect) CreateTableSql(table string) string {
+	return "IF OBJECT_ID(N'" + table + "', N'U') IS NULL CREATE TABLE " + table + " (" +
+		"version BIGINT NOT NULL PRIMARY KEY, " +
+		"name NVARCHAR(255) NOT NULL, " +
+		"applied_at DATETIME2 NOT NULL)"
+}
+
+// Lock 用于通过 sp_getapplock 获取迁移锁。
+func (SqlServerDialect) Lock(ctx context.Context, dbClient sqlmer.DbClient, name string, timeout time.Duration) (sqlmer.DbClient, func() error, error) {
+	tx, err := dbClient.CreateTransactionContext(context.WithoutCancel(ctx), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("migrate: begin lock transaction: %w", err)
+	}
+
+	milliseconds := int64(-1) // -1 表示一直等待。
+	if timeout > 0 {
+		milliseconds = timeout.Milliseconds()
+	}
+	// 语句中的 @name 都会被绑定为参数，因此这里借用 @result 参数接收存储过程的返回值，避免声明局部变量。
+	result, _, err := tx.ScalarContext(ctx,
+		"EXEC @result = sp_getapplock @resource, 'Exclusive', 'Transaction', @timeout; SELECT @result",
+		map[string]any{"result": 0, "resource": name, "timeout": milliseconds})
+	if err != nil {
+		tx.Close()
+		return nil, nil, fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+	if n, ok := toInt64(result); !ok || n < 0 { // 0 、 1 表示成功，负数为超时、死锁等失败。
+		tx.Close()
+		return nil, nil, fmt.Errorf("%w: sp_getapplock(%q) returned %v", ErrLocked, name, result)
+	}
+
+	// 锁的所有者是事务，事务结束时锁随之释放。
+	return dbClient, tx.Commit, nil
+}
+
+// SqliteDialect 是 SQLite 的 Dialect ，通过排他事务（ BEGIN EXCLUSIVE ）加锁。
+//
+// 所有迁移都在加锁的事务中执行，每个迁移使用的事务为其中的保存点（与 DbClient 是否开启 WithSavepoint 无关），
+// 释放锁时提交整个事务。
+// 等待锁的时间由连接的 busy_timeout 及 DbClient 的连接超时时间决定，不受 timeout 控制。
+type SqliteDialect struct{}
+
+// CreateTableSql 返回创建迁移记录表的语句。
+func (SqliteDialect) CreateTableSql(table string) string {
+	return "CREATE TABLE IF NOT EXISTS " + table + " (" +
+		"version INTEGER NOT NULL PRIMARY KEY, " +
+		"name TEXT NOT NULL, " +
+		"applied_at TIMESTAMP NOT NULL)"
+}
+
+// Lock 用于通过排他事务获取迁移锁。
+func (SqliteDialect) Lock(ctx context.Context, dbClient sqlmer.DbClient, _ string, _ time.Duration) (sqlmer.DbClient, func() error, error) {
+	// 驱动将 LevelLinearizable 映射为 BEGIN EXCLUSIVE 。
+	tx, err := dbClient.CreateTransactionContext(context.WithoutCancel(ctx), &sql.TxOptions{Isolation: sql.LevelLinearizable})
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrLocked, err)
+	}
+	return savepointRunner{tx}, tx.Commit, nil
+}
+
+// savepointRunner 是在加锁的事务上执行迁移的 DbClient ，其创建的事务为保存点。
+type savepointRunner struct {
+	sqlmer.TransactionKeeper
+}
+
+// CreateTransaction 用于设置一个保存点，并返回对应的事务。
+func (r savepointRunner) CreateTransaction() (sqlmer.TransactionKeeper, error) {
+	return r.CreateTransactionContext(context.Background(), nil)
+}
+
+// CreateTransactionContext 用于设置一个保存点，并返回对应的事务， opts 被忽略。
+func (r savepointRunner) CreateTransactionContext(ctx context.Context, _ *sql.TxOptions) (sqlmer.TransactionKeeper, error) {
+	const name = "sqlmer_migration" // 迁移逐个执行，同一时刻至多有一个保存点。
+	if _, err := r.ExecuteContext(ctx, savepointDialect.CreateSavepoint(name)); err != nil {
+		return nil, err
+	}
+	return &savepointTx{TransactionKeeper: r.TransactionKeeper, name: name}, nil
+}
+
+// savepointDialect 是 SQLite 使用的保存点语句。
+var savepointDialect sqlmer.StandardSavepointDialect
+
+// savepointTx 是通过保存点实现的事务：提交时释放保存点，回滚时回滚到保存点。
+type savepointTx struct {
+	sqlmer.TransactionKeeper
+	name      string
+	completed bool
+}
+
+// Commit 用于释放保存点。
+func (tx *savepointTx) Commit() error {
+	if tx.completed {
+		return fmt.Errorf("%w: trans has already completed", sqlmer.ErrTran)
+	}
+	tx.completed = true
+	_, err := tx.Execute(savepointDialect.ReleaseSavepoint(tx.name))
+	return err
+}
+
+// Rollback 用于回滚到保存点，之后释放该保存点。
+func (tx *savepointTx) Rollback() error {
+	if tx.completed {
+		return fmt.Errorf("%w: trans has already completed", sqlmer.ErrTran)
+	}
+	tx.completed = true
+	if _, err := tx.Execute(savepointDialect.RollbackToSavepoint(tx.name)); err != nil {
+		return err
+	}
+	_, err := tx.Execute(savepointDialect.ReleaseSavepoint(tx.name))
+	return err
+}
+
+// ExecuteScript 用于在加锁的事务中执行包含多条语句的脚本。
+func (tx *savepointTx) ExecuteScript(ctx context.Context, script string, options ...sqlmer.ScriptOption) ([]sqlmer.ScriptResult, error) {
+	return sqlmer.Extend(tx.TransactionKeeper).ExecuteScript(ctx, script, options...)
+}
+
+// Close 用于在保存点没有释放时回滚到保存点。
+func (tx *savepointTx) Close() error {
+	if tx.completed {
+		return nil
+	}
+	return tx.Rollback()
+}
+
+// PostgresDialect 是 PostgreSQL 的 Dialect ，通过 pg_advisory_xact_lock 加锁。
+//
+// 锁的所有者是一个专门的事务，因此加锁时会占用一个连接直到释放锁，迁移本身在另一个连接上执行，
+// 连接池的最大连接数不能小于 2 。
+type PostgresDialect struct{}
+
+// CreateTableSql 返回创建迁移记录表的语句。
+func (PostgresDialect) CreateTableSql(table string) string {
+	return "CREATE TABLE IF NOT EXISTS " + table + " (" +
+		"version BIGINT NOT NULL PRIMARY KEY, " +
+		"name VARCHAR(255) NOT NULL, " +
+		"applied_at TIMESTAMPTZ NOT NULL)"
+}
+
+// Lock 用于通过 pg_advisory_xact_lock 获取迁移锁，锁的键为 hashtext(name) 。
+func (PostgresDialect) Lock(ctx context.Context, dbClient sqlmer.DbClient, name string, timeout time.Duration) (sqlmer.DbClient, func() error, error) {
+	tx, err := dbClient.CreateTransactionContext(context.WithoutCancel(ctx), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("migrate: begin lock transaction: %w", err)
+	}
+
+	// lock_timeout 对咨询锁同样生效， 0 表示一直等待， SET LOCAL 的效果仅限于当前事务。
+	lockTimeout := strconv.FormatInt(max(timeout.Milliseconds(), 0), 10)
+	if _, err := tx.ExecuteContext(ctx, "SELECT set_config('lock_timeout', @p1, true)", lockTimeout); err != nil {
+		tx.Close()
+		return nil, nil, fmt.Errorf("migrate: set lock timeout: %w", err)
+	}
+	if _, err := tx.ExecuteContext(ctx, "SELECT pg_advisory_xact_lock(hashtext(@p1))", name); err != nil {
+		tx.Close()
+		return nil, nil, fmt.Errorf("%w: %w", ErrLocked, err)
+	}
+
+	// 咨询锁随事务结束释放。
+	return dbClient, tx.Commit, nil
+}
+
+// toInt64 用于将 Scalar 返回的整数结果转为 int64 。
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case []byte:
+		n, err := strconv.ParseInt(string(v), 10, 64)
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,99 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationFilePattern 用于匹配迁移文件名： NNN_name.up.sql 或 NNN_name.down.sql 。
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration 是一个版本的迁移。
+type Migration struct {
+	Version int64  // 版本号，即文件名开头的数字。
+	Name    string // 名称，即文件名中版本号与 .up.sql / .down.sql 之间的部分。
+	Up      string // .up.sql 文件的内容。
+	Down    string // .down.sql 文件的内容。
+
+	hasDown bool // 是否存在 .down.sql 文件。
+}
+
+// HasDown 用于判断该版本是否有 .down.sql 文件，没有的版本不能回滚。
+func (m Migration) HasDown() bool {
+	return m.hasDown
+}
+
+// String 返回 NNN_name 形式的描述。
+func (m Migration) String() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus 是一个版本的迁移状态。
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool      // 是否已经执行。
+	AppliedAt time.Time // 执行的时间，未执行时为零值。
+}
+
+// loadMigrations 用于读取 fsys 根目录下的迁移文件，按版本号升序返回，不匹配文件名格式的文件会被忽略。
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read migrations: %w", err)
+	}
+
+	migrations := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool) // 空的 .up.sql 文件也是合法的，因此需要单独记录文件是否存在。
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidMigration, entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := migrations[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			migrations[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: version %d is used by both %s and %s", ErrInvalidMigration, version, migration.Name, matches[2])
+		}
+
+		if duplicated := (matches[3] == "up" && hasUp[version]) || (matches[3] == "down" && migration.hasDown); duplicated {
+			return nil, fmt.Errorf("%w: duplicate %s file for version %d", ErrInvalidMigration, matches[3], version)
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+			hasUp[version] = true
+		} else {
+			migration.Down = string(content)
+			migration.hasDown = true
+		}
+	}
+
+	result := make([]Migration, 0, len(migrations))
+	for version, migration := range migrations {
+		if !hasUp[version] {
+			return nil, fmt.Errorf("%w: %s has no .up.sql file", ErrInvalidMigration, migration)
+		}
+		result = append(result, *migration)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
@@ -0,0 +1,357 @@
+// Package migrate 提供基于 SQL 文件的数据库结构迁移：
+// 从 fs.FS （可以是 embed.FS ）中读取 NNN_name.up.sql / NNN_name.down.sql 文件，按版本号顺序执行
+// （文件内容通过 sqlmer.ScriptExecutor 按驱动的规则拆分为语句逐条执行），
+// 并将已执行的版本记录在迁移记录表（默认为 sqlmer_migrations ）中。
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+)
+
+// ErrLocked 当无法获取迁移锁（如其它进程正在执行迁移且等待超时）时，返回该类型错误。
+var ErrLocked = errors.New("migrate: failed to acquire the migration lock")
+
+// ErrInvalidMigration 当迁移文件不合法（如版本号重复、缺少 .up.sql 文件）时，返回该类型错误。
+var ErrInvalidMigration = errors.New("migrate: invalid migration")
+
+// ErrUnknownVersion 当目标版本或需要回滚的已执行版本没有对应的迁移文件时，返回该类型错误。
+var ErrUnknownVersion = errors.New("migrate: unknown version")
+
+// ErrNoDownMigration 当需要回滚的版本没有 .down.sql 文件时，返回该类型错误。
+var ErrNoDownMigration = errors.New("migrate: no down migration")
+
+// DefaultTableName 是默认的迁移记录表名称，也是默认的迁移锁名称。
+const DefaultTableName = "sqlmer_migrations"
+
+// DefaultLockTimeout 是默认的等待迁移锁的超时时间。
+const DefaultLockTimeout = time.Minute
+
+// identifierPattern 用于校验迁移记录表的名称，名称会直接拼接到语句中，因此仅允许普通标识符（可带 schema 前缀）。
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Migrator 用于执行迁移。
+type Migrator struct {
+	dbClient   sqlmer.DbClient
+	dialect    Dialect
+	migrations []Migration // 按版本号升序。
+
+	tableName   string
+	lockName    string // 为空时使用 tableName 。
+	lockTimeout time.Duration
+}
+
+// Option 是 NewMigrator 的可选配置。
+type Option func(m *Migrator) error
+
+// WithTableName 用于设置迁移记录表的名称，默认为 DefaultTableName 。
+func WithTableName(tableName string) Option {
+	return func(m *Migrator) error {
+		if !identifierPattern.MatchString(tableName) {
+			return fmt.Errorf("migrate: invalid table name %q", tableName)
+		}
+		m.tableName = tableName
+		return nil
+	}
+}
+
+// WithLockName 用于设置迁移锁的名称，默认与迁移记录表的名称相同。
+// 共用一个数据库，但使用不同迁移记录表的多组迁移，可以使用同一个锁名称以串行执行。
+func WithLockName(lockName string) Option {
+	return func(m *Migrator) error {
+		m.lockName = lockName
+		return nil
+	}
+}
+
+// WithLockTimeout 用于设置等待迁移锁的超时时间，默认为 DefaultLockTimeout ，小于等于 0 时一直等待。
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) error {
+		m.lockTimeout = timeout
+		return nil
+	}
+}
+
+// NewMigrator 用于创建一个 Migrator 。
+// params:
+//
+//	@dbClient 执行迁移的 DbClient 。
+//	@fsys 迁移文件所在的文件系统，读取根目录下的 NNN_name.up.sql / NNN_name.down.sql 文件，
+//	      其余文件被忽略，子目录可以通过 fs.Sub 指定。
+//	@dialect 数据库对应的 Dialect ，如 MySqlDialect{} 、 SqlServerDialect{} 、 SqliteDialect{} 、 PostgresDialect{} 。
+//	@options 可选配置，见 WithTableName 、 WithLockName 、 WithLockTimeout 。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - ErrInvalidMigration: 迁移文件不合法。
+func NewMigrator(dbClient sqlmer.DbClient, fsys fs.FS, dialect Dialect, options ...Option) (*Migrator, error) {
+	m := &Migrator{
+		dbClient:    dbClient,
+		dialect:     dialect,
+		tableName:   DefaultTableName,
+		lockTimeout: DefaultLockTimeout,
+	}
+	for _, option := range options {
+		if err := option(m); err != nil {
+			return nil, err
+		}
+	}
+	if m.lockName == "" {
+		m.lockName = m.tableName
+	}
+
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	m.migrations = migrations
+	return m, nil
+}
+
+// Migrations 返回所有的迁移，按版本号升序排列。
+func (m *Migrator) Migrations() []Migration {
+	return append([]Migration(nil), m.migrations...)
+}
+
+// Up 用于执行所有未执行的迁移，返回本次执行的迁移。
+// 每个迁移在单独的事务中执行，出错时停止，返回出错前已经执行的迁移及错误。
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.run(ctx, func(applied map[int64]time.Time) ([]step, error) {
+		return m.upSteps(applied, m.lastVersion()), nil
+	})
+}
+
+// Down 用于回滚最后执行的一个版本，返回本次回滚的迁移，没有已执行的版本时，返回空切片。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - ErrUnknownVersion: 需要回滚的版本没有对应的迁移文件。
+//   - ErrNoDownMigration: 需要回滚的版本没有 .down.sql 文件。
+func (m *Migrator) Down(ctx context.Context) ([]Migration, error) {
+	return m.run(ctx, func(applied map[int64]time.Time) ([]step, error) {
+		versions := sortedVersions(applied)
+		if len(versions) == 0 {
+			return nil, nil
+		}
+		last := versions[len(versions)-1]
+		return m.downSteps(applied, last-1)
+	})
+}
+
+// To 用于迁移到指定的版本：回滚所有大于 version 的已执行版本，并执行所有小于等于 version 的未执行版本。
+// version 为 0 时，回滚所有版本。返回本次执行（回滚）的迁移。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - ErrUnknownVersion: version 不为 0 且没有对应的迁移文件，或需要回滚的版本没有对应的迁移文件。
+//   - ErrNoDownMigration: 需要回滚的版本没有 .down.sql 文件。
+func (m *Migrator) To(ctx context.Context, version int64) ([]Migration, error) {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.run(ctx, func(applied map[int64]time.Time) ([]step, error) {
+		steps, err := m.downSteps(applied, version)
+		if err != nil {
+			return nil, err
+		}
+		return append(steps, m.upSteps(applied, version)...), nil
+	})
+}
+
+// Status 用于获取所有版本的迁移状态，按版本号升序排列。
+// 迁移记录表中存在，但没有对应迁移文件的版本也会被列出。
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.createTable(ctx, m.dbClient); err != nil {
+		return nil, err
+	}
+	applied, names, err := m.applied(ctx, m.dbClient)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		result = append(result, MigrationStatus{Version: migration.Version, Name: migration.Name, Applied: ok, AppliedAt: appliedAt})
+	}
+	for version, appliedAt := range applied {
+		if _, ok := m.find(version); !ok {
+			result = append(result, MigrationStatus{Version: version, Name: names[version], Applied: true, AppliedAt: appliedAt})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// step 是一次迁移中的一步：执行或回滚一个版本。
+type step struct {
+	migration Migration
+	down      bool
+}
+
+// upSteps 返回执行所有小于等于 target 的未执行版本的步骤。
+func (m *Migrator) upSteps(applied map[int64]time.Time, target int64) []step {
+	var steps []step
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= target {
+			steps = append(steps, step{migration: migration})
+		}
+	}
+	return steps
+}
+
+// downSteps 返回按版本号降序回滚所有大于 target 的已执行版本的步骤。
+func (m *Migrator) downSteps(applied map[int64]time.Time, target int64) ([]step, error) {
+	var steps []step
+	versions := sortedVersions(applied)
+	for i := len(versions) - 1; i >= 0 && versions[i] > target; i-- {
+		migration, ok := m.find(versions[i])
+		if !ok {
+			return nil, fmt.Errorf("%w: applied version %d has no migration file", ErrUnknownVersion, versions[i])
+		}
+		if !migration.hasDown {
+			return nil, fmt.Errorf("%w: %s", ErrNoDownMigration, migration)
+		}
+		steps = append(steps, step{migration: migration, down: true})
+	}
+	return steps, nil
+}
+
+// run 用于在迁移锁的保护下，执行 plan 根据已执行版本生成的步骤。
+func (m *Migrator) run(ctx context.Context, plan func(applied map[int64]time.Time) ([]step, error)) (done []Migration, err error) {
+	runner, release, err := m.dialect.Lock(ctx, m.dbClient, m.lockName, m.lockTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if releaseErr := release(); releaseErr != nil {
+			err = errors.Join(err, fmt.Errorf("migrate: release lock: %w", releaseErr))
+		}
+	}()
+
+	if err := m.createTable(ctx, runner); err != nil {
+		return nil, err
+	}
+	applied, _, err := m.applied(ctx, runner)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := plan(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	done = make([]Migration, 0, len(steps))
+	for _, s := range steps {
+		if err := m.runStep(ctx, runner, s); err != nil {
+			return done, err
+		}
+		done = append(done, s.migration)
+	}
+	return done, nil
+}
+
+// runStep 用于在一个事务中执行（回滚）一个版本，并更新迁移记录表。
+func (m *Migrator) runStep(ctx context.Context, runner sqlmer.DbClient, s step) error {
+	action, sqlText := "apply", s.migration.Up
+	if s.down {
+		action, sqlText = "revert", s.migration.Down
+	}
+
+	tx, err := runner.CreateTransactionContext(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate: %s %s: %w", action, s.migration, err)
+	}
+	defer tx.Close()
+
+	if err := executeScript(ctx, tx, sqlText); err != nil {
+		return fmt.Errorf("migrate: %s %s: %w", action, s.migration, err)
+	}
+
+	if s.down {
+		_, err = tx.ExecuteContext(ctx, "DELETE FROM "+m.tableName+" WHERE version = @p1", s.migration.Version)
+	} else {
+		_, err = tx.ExecuteContext(ctx, "INSERT INTO "+m.tableName+" (version, name, applied_at) VALUES (@p1, @p2, @p3)",
+			s.migration.Version, s.migration.Name, time.Now())
+	}
+	if err != nil {
+		return fmt.Errorf("migrate: record %s %s: %w", action, s.migration, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migrate: %s %s: %w", action, s.migration, err)
+	}
+	return nil
+}
+
+// executeScript 用于执行迁移文件的内容：按驱动的规则拆分为语句（如 SQL Server 的 GO 、 MySQL 的 DELIMITER ）逐条执行，
+// 语句不做参数绑定，其中的 @var 等原样发送给数据库。
+func executeScript(ctx context.Context, dbClient sqlmer.DbClient, script string) error {
+	executor, ok := dbClient.(sqlmer.ScriptExecutor)
+	if !ok {
+		return fmt.Errorf("migrate: %T does not support executing scripts", dbClient)
+	}
+	_, err := executor.ExecuteScript(ctx, script)
+	return err
+}
+
+// createTable 用于创建迁移记录表（如果不存在）。
+func (m *Migrator) createTable(ctx context.Context, dbClient sqlmer.DbClient) error {
+	if _, err := dbClient.ExecuteContext(ctx, m.dialect.CreateTableSql(m.tableName)); err != nil {
+		return fmt.Errorf("migrate: create table %s: %w", m.tableName, err)
+	}
+	return nil
+}
+
+// applied 用于读取迁移记录表，返回已执行的版本及其执行时间和名称。
+func (m *Migrator) applied(ctx context.Context, dbClient sqlmer.DbClient) (map[int64]time.Time, map[int64]string, error) {
+	rows, err := dbClient.SliceGetContext(ctx, "SELECT version, name, applied_at FROM "+m.tableName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("migrate: read table %s: %w", m.tableName, err)
+	}
+
+	applied := make(map[int64]time.Time, len(rows))
+	names := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		version, ok := toInt64(row["version"])
+		if !ok {
+			return nil, nil, fmt.Errorf("migrate: read table %s: unexpected version %v", m.tableName, row["version"])
+		}
+		appliedAt, _ := row["applied_at"].(time.Time)
+		applied[version] = appliedAt
+		names[version] = fmt.Sprint(row["name"])
+	}
+	return applied, names, nil
+}
+
+// find 用于查找指定版本的迁移。
+func (m *Migrator) find(version int64) (Migration, bool) {
+	i := sort.Search(len(m.migrations), func(i int) bool { return m.migrations[i].Version >= version })
+	if i < len(m.migrations) && m.migrations[i].Version == version {
+		return m.migrations[i], true
+	}
+	return Migration{}, false
+}
+
+// lastVersion 返回最大的版本号，没有迁移时返回 0 。
+func (m *Migrator) lastVersion() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// sortedVersions 返回升序排列的已执行版本。
+func sortedVersions(applied map[int64]time.Time) []int64 {
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
@@ -0,0 +1,239 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/testenv"
+)
+
+// testMigrations 是测试用的迁移文件，其中 3 没有 .down.sql 文件， 2 、 3 包含多条语句， 3 中的 @name 不做参数绑定（未绑定的参数为 NULL ）。
+var testMigrations = fstest.MapFS{
+	"001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")},
+	"001_create_users.down.sql": {Data: []byte("DROP TABLE users")},
+	"002_add_email.up.sql": {Data: []byte(`ALTER TABLE users ADD COLUMN email TEXT;
+CREATE TRIGGER users_email AFTER INSERT ON users
+BEGIN
+  UPDATE users SET email = lower(email) WHERE id = NEW.id;
+END;`)},
+	"002_add_email.down.sql": {Data: []byte("DROP TRIGGER users_email;\nALTER TABLE users DROP COLUMN email;")},
+	"003_seed.up.sql":        {Data: []byte("INSERT INTO users (name, email) VALUES ('a', 'A@example.com');\nUPDATE users SET name = coalesce(@name, name);")},
+	"README.md":              {Data: []byte("ignored")},
+}
+
+func newSqliteClient(t *testing.T, options ...sqlmer.DbClientOption) sqlmer.DbClient {
+	t.Helper()
+	dbClient, err := testenv.NewSqliteTempClient(t.TempDir(), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbClient.Close() })
+	return dbClient
+}
+
+func newTestMigrator(t *testing.T, fsys fstest.MapFS, options ...Option) (*Migrator, sqlmer.DbClient) {
+	t.Helper()
+	dbClient := newSqliteClient(t)
+	m, err := NewMigrator(dbClient, fsys, SqliteDialect{}, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, dbClient
+}
+
+// versions 返回迁移的版本号。
+func versions(migrations []Migration) []int64 {
+	result := make([]int64, 0, len(migrations))
+	for _, migration := range migrations {
+		result = append(result, migration.Version)
+	}
+	return result
+}
+
+// appliedVersions 返回已执行的版本号。
+func appliedVersions(t *testing.T, m *Migrator) []int64 {
+	t.Helper()
+	status, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result []int64
+	for _, s := range status {
+		if s.Applied {
+			result = append(result, s.Version)
+		}
+	}
+	return result
+}
+
+func equalVersions(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	m, dbClient := newTestMigrator(t, testMigrations)
+	ctx := context.Background()
+
+	done, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(done); !equalVersions(got, []int64{1, 2, 3}) {
+		t.Fatalf("Up() = %v, want [1 2 3]", got)
+	}
+	if email, _, err := dbClient.Scalar("SELECT email FROM users WHERE name = 'a'"); err != nil || email != "a@example.com" {
+		t.Fatalf("Scalar() = %v, %v, want the seeded row", email, err)
+	}
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 3 || status[0].Name != "create_users" || !status[2].Applied || status[2].AppliedAt.IsZero() {
+		t.Errorf("Status() = %+v, want 3 applied migrations", status)
+	}
+
+	if done, err := m.Up(ctx); err != nil || len(done) != 0 {
+		t.Errorf("Up() again = %v, %v, want nothing to do", done, err)
+	}
+
+	// 3 没有 .down.sql 文件，不能回滚。
+	if _, err := m.Down(ctx); !errors.Is(err, ErrNoDownMigration) {
+		t.Fatalf("Down() error = %v, want ErrNoDownMigration", err)
+	}
+	if _, err := m.To(ctx, 1); !errors.Is(err, ErrNoDownMigration) {
+		t.Fatalf("To(1) error = %v, want ErrNoDownMigration", err)
+	}
+	if got := appliedVersions(t, m); !equalVersions(got, []int64{1, 2, 3}) {
+		t.Errorf("applied versions = %v, want nothing reverted", got)
+	}
+}
+
+func TestMigrator_To(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, file := range testMigrations {
+		fsys[name] = file
+	}
+	fsys["003_seed.down.sql"] = &fstest.MapFile{Data: []byte("DELETE FROM users")}
+	m, dbClient := newTestMigrator(t, fsys)
+	ctx := context.Background()
+
+	if done, err := m.To(ctx, 2); err != nil || !equalVersions(versions(done), []int64{1, 2}) {
+		t.Fatalf("To(2) = %v, %v, want [1 2]", versions(done), err)
+	}
+	if done, err := m.Up(ctx); err != nil || !equalVersions(versions(done), []int64{3}) {
+		t.Fatalf("Up() = %v, %v, want [3]", versions(done), err)
+	}
+	if done, err := m.Down(ctx); err != nil || !equalVersions(versions(done), []int64{3}) {
+		t.Fatalf("Down() = %v, %v, want [3]", versions(done), err)
+	}
+	if done, err := m.To(ctx, 0); err != nil || !equalVersions(versions(done), []int64{2, 1}) {
+		t.Fatalf("To(0) = %v, %v, want [2 1]", versions(done), err)
+	}
+	if exists, err := dbClient.Exists("SELECT 1 FROM sqlite_master WHERE name = 'users'"); err != nil || exists {
+		t.Errorf("Exists() = %v, %v, want users dropped", exists, err)
+	}
+	if done, err := m.Down(ctx); err != nil || len(done) != 0 {
+		t.Errorf("Down() = %v, %v, want nothing to do", done, err)
+	}
+
+	if _, err := m.To(ctx, 4); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("To(4) error = %v, want ErrUnknownVersion", err)
+	}
+}
+
+func TestMigrator_Failure(t *testing.T) {
+	fsys := fstest.MapFS{
+		"1_create.up.sql": {Data: []byte("CREATE TABLE t1 (id INTEGER)")},
+		"2_broken.up.sql": {Data: []byte("CREATE TABLE t2 (id INTEGER); INSERT INTO not_exists VALUES (1)")},
+		"3_never.up.sql":  {Data: []byte("CREATE TABLE t3 (id INTEGER)")},
+	}
+	m, dbClient := newTestMigrator(t, fsys, WithTableName("schema_versions"))
+
+	done, err := m.Up(context.Background())
+	if err == nil {
+		t.Fatal("Up() error = nil")
+	}
+	if !equalVersions(versions(done), []int64{1}) {
+		t.Errorf("Up() = %v, want [1]", versions(done))
+	}
+	if got := appliedVersions(t, m); !equalVersions(got, []int64{1}) {
+		t.Errorf("applied versions = %v, want [1]", got)
+	}
+
+	// 失败的迁移整体回滚，之前成功的迁移依然生效。
+	tables, err := dbClient.SliceGet("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT IN ('go_TypeTest', 'sqlite_sequence') ORDER BY name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 || tables[0]["name"] != "schema_versions" || tables[1]["name"] != "t1" {
+		t.Errorf("tables = %v, want schema_versions and t1", tables)
+	}
+}
+
+func TestMigrator_Locked(t *testing.T) {
+	// 连接池中的另一个连接持有排他事务，迁移时等待锁直至连接超时。
+	dbClient := newSqliteClient(t, sqlmer.WithConnTimeout(100*time.Millisecond))
+	m, err := NewMigrator(dbClient, testMigrations, SqliteDialect{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := dbClient.CreateTransactionContext(context.Background(), &sql.TxOptions{Isolation: sql.LevelLinearizable})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); !errors.Is(err, ErrLocked) {
+		t.Errorf("Up() error = %v, want ErrLocked", err)
+	}
+
+	tx.Close()
+	if done, err := m.Up(context.Background()); err != nil || len(done) != 3 {
+		t.Errorf("Up() = %v, %v, want 3 migrations after the lock is released", versions(done), err)
+	}
+}
+
+func TestNewMigrator(t *testing.T) {
+	dbClient := newSqliteClient(t)
+
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		options []Option
+	}{
+		{"duplicate_version", fstest.MapFS{"1_a.up.sql": {}, "01_b.up.sql": {}}, nil},
+		{"duplicate_file", fstest.MapFS{"1_a.up.sql": {}, "01_a.up.sql": {}}, nil},
+		{"missing_up", fstest.MapFS{"1_a.up.sql": {}, "2_b.down.sql": {}}, nil},
+		{"invalid_table_name", fstest.MapFS{}, []Option{WithTableName("migrations; DROP TABLE users")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMigrator(dbClient, tt.fsys, SqliteDialect{}, tt.options...); err == nil {
+				t.Error("NewMigrator() error = nil")
+			}
+		})
+	}
+
+	m, err := NewMigrator(dbClient, fstest.MapFS{"10_b.up.sql": {}, "9_a.up.sql": {}, "9_a.down.sql": {}, "notes.sql": {}}, SqliteDialect{},
+		WithLockName("lock"), WithLockTimeout(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrations := m.Migrations()
+	if len(migrations) != 2 || migrations[0].String() != "9_a" || !migrations[0].HasDown() || migrations[1].HasDown() {
+		t.Errorf("Migrations() = %+v, want 9_a (with down) and 10_b", migrations)
+	}
+}
@@ -2,15 +2,17 @@ package mssql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
-	"sync"
-	"unicode/utf8"
 
 	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/sqlscript"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 
-	_ "github.com/denisenkom/go-mssqldb"
+	mssql "github.com/denisenkom/go-mssqldb"
 )
 
 // DriverName 是 SqlServer 驱动名称。
@@ -28,7 +30,14 @@ func NewMsSqlDbClient(dsn string, options ...sqlmer.DbClientOption) (*MsSqlDbCli
 	fixedOptions := []sqlmer.DbClientOption{
 		sqlmer.WithDsn(DriverName, dsn),
 		sqlmer.WithUnifyDataTypeFunc(unifyDataType),
-		sqlmer.WithBindArgsFunc(bindArgs), // SqlServer 要支持命名参数，需要定制一个参数解析函数。
+		sqlmer.WithBindArgsFunc(bindArgs),                    // SqlServer 要支持命名参数，需要定制一个参数解析函数。
+		sqlmer.WithParseParamNamesFunc(parseParamNames),      // 定制命名参数解析逻辑。
+		sqlmer.WithSavepointDialect(mssqlSavepointDialect{}), // SqlServer 的保存点语法与 SQL 标准不同。
+		sqlmer.WithRetryableErrorFunc(isRetryableError),      // 定制可重试错误的判断逻辑。
+		sqlmer.WithMaxParamCount(2098),                       // SqlServer 单条语句最多 2100 个参数，驱动通过 sp_executesql 执行时自身占用 2 个。
+		sqlmer.WithMaxInsertRows(1000),                       // SqlServer 的 VALUES 子句最多 1000 行。
+		sqlmer.WithInsertIdDialect(mssqlInsertIdDialect{}),   // SqlServer 驱动不支持 LastInsertId 。
+		sqlmer.WithSplitScriptFunc(splitScript),              // 按单独一行的 GO 拆分脚本。
 	}
 	options = append(fixedOptions, options...) // 用户自定义选项放后面，以覆盖默认。
 
@@ -45,6 +54,59 @@ func NewMsSqlDbClient(dsn string, options ...sqlmer.DbClientOption) (*MsSqlDbCli
 	return &MsSqlDbClient{internalDbClient}, nil
 }
 
+// isRetryableError 用于判断错误是否可以通过重试事务解决：
+//   - 1205 ：事务与另一个进程发生死锁，被选作牺牲品。
+func isRetryableError(err error) bool {
+	var mssqlErr mssql.Error
+	if !errors.As(err, &mssqlErr) {
+		return false
+	}
+	return mssqlErr.Number == 1205
+}
+
+// splitScript 用于按 SqlServer 客户端工具的规则拆分脚本：按单独一行的 GO [count] 拆分为批，同一批中的多条语句一起执行。
+func splitScript(script string) []string {
+	return sqlscript.Split(script, sqlscript.SqlServer)
+}
+
+// mssqlSavepointDialect 是 SqlServer 的保存点语句。
+type mssqlSavepointDialect struct{}
+
+// CreateSavepoint 返回设置保存点的语句。
+func (mssqlSavepointDialect) CreateSavepoint(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+// RollbackToSavepoint 返回回滚到保存点的语句。
+func (mssqlSavepointDialect) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+// ReleaseSavepoint SqlServer 没有释放保存点的语句，保存点随事务结束而释放。
+func (mssqlSavepointDialect) ReleaseSavepoint(name string) string {
+	return ""
+}
+
+// outputInsertedRegexp 用于判断插入语句是否已经通过 OUTPUT 子句返回插入的数据。
+var outputInsertedRegexp = regexp.MustCompile(`(?i)\bOUTPUT\s+INSERTED\.`)
+
+// mssqlInsertIdDialect 是 SqlServer 获取自增 id 的方式：
+//   - 语句中已包含 OUTPUT INSERTED.xxx 子句的，直接读取其返回的第一列；
+//   - 否则在同一个批处理中追加 SELECT SCOPE_IDENTITY() ，读取当前作用域最后插入的自增 id 。
+type mssqlInsertIdDialect struct{}
+
+// InsertIdSql 将插入语句改写为返回自增 id 的查询语句。
+func (mssqlInsertIdDialect) InsertIdSql(sqlText string) (string, bool) {
+	// 只在普通文本中查找，字符串、注释中的 OUTPUT INSERTED 不算。
+	tokens := sqltoken.Tokenize(sqlText, sqltoken.SqlServer)
+	for _, token := range tokens {
+		if token.Kind == sqltoken.Text && outputInsertedRegexp.MatchString(token.Text) {
+			return sqlText, true
+		}
+	}
+	return sqltoken.TrimStatementEnd(tokens) + ";\nSELECT CAST(SCOPE_IDENTITY() AS BIGINT)", true
+}
+
 // unifyDataType 用于统一数据类型。
 func unifyDataType(columnType *sql.ColumnType, dest *any) {
 	switch columnType.DatabaseTypeName() {
@@ -71,6 +133,17 @@ func unifyDataType(columnType *sql.ColumnType, dest *any) {
 	}
 }
 
+// parseParamNames 用于解析 SQL 语句中用到的命名参数名称（按顺序），字符串、引用标识符及注释中的 @ 不是参数。
+func parseParamNames(sqlText string) []string {
+	names := make([]string, 0, 10)
+	for _, token := range sqltoken.Tokenize(sqlText, sqltoken.SqlServer) {
+		if token.Kind == sqltoken.NamedParam {
+			names = append(names, token.Name())
+		}
+	}
+	return names
+}
+
 // bindArgs 用于对 sql 语句和参数进行预处理。
 // 第一个参数如果是 map，且仅且只有一个参数的情况下，做命名参数处理；其余情况做位置参数处理。
 func bindArgs(sqlText string, args ...any) (string, []any, error) {
@@ -110,67 +183,26 @@ func bindArgs(sqlText string, args ...any) (string, []any, error) {
 }
 
 // extendInParams 用于处理 SQL IN 子句的参数展开
-// 将切片类型的参数展开为多个参数。
+// 将切片类型的参数展开为多个参数；字符串、引用标识符及注释中的 @ 不是参数，会原样保留。
 func extendInParams(sqlText string, params map[string]any) (string, []any, error) {
 	var newParams []any = make([]any, 0, len(params))
 	var newSqlBuilder strings.Builder
+	hasParam := map[string]struct{}{} // 用于判断某个参数是否已经存在返回结果的参数列表中。
 
-	inString := false                                // 用于判断当前字符是否在字符串中。
-	inName := false                                  // 用于判断当前字符是否属于参数名的一部分。
-	paramNameBuilder := strings.Builder{}            // 用于存储参数名。
-	lastIndex := utf8.RuneCountInString(sqlText) - 1 // sql 语句 bytes 的最后一个索引位置。
-	hasParam := map[string]struct{}{}                // 用于判断某个参数是否已经存在返回结果的参数列表中。
-
-	for i, r := range sqlText {
-		if r == '\'' {
-			inString = !inString
-			newSqlBuilder.WriteRune(r)
-			continue
-		}
-
-		if inString {
-			newSqlBuilder.WriteRune(r)
+	for _, token := range sqltoken.Tokenize(sqlText, sqltoken.SqlServer) {
+		// 非参数部分（包括 @@ROWCOUNT 等系统函数的 @@ ）原样入库即可。
+		if token.Kind != sqltoken.NamedParam {
+			newSqlBuilder.WriteString(token.Text)
 			continue
 		}
 
-		// 处理参数标识符。
-		if r == '@' {
-			// 处理转义的 @@ 情况。
-			if inName && i > 0 && sqlText[i-1] == '@' {
-				paramNameBuilder.WriteRune(r)
-				inName = false
-				continue
-			}
-
-			// 开始新的参数名
-			inName = true
-			paramNameBuilder.Reset()
-			continue
-		}
-
-		// 普通语句字符，直接入库即可。
-		if !inName {
-			newSqlBuilder.WriteRune(r)
-			continue
-		}
-
-		// 处理参数名称字符。
-		isLegalChar := isLegalParamNameCharter(r)
-		if isLegalChar {
-			paramNameBuilder.WriteRune(r)
-			if i < lastIndex {
-				continue
-			}
-		}
-
-		// 参数名称结束，处理参数。
-		inName = isLegalChar // 更新参数名状态。
-		paramName := paramNameBuilder.String()
+		paramName := token.Name()
 		param := params[paramName]
 		paramValue := reflect.ValueOf(param)
 
 		// 处理需要展开的参数。
 		_, hasThisParam := hasParam[paramName]
+		hasParam[paramName] = struct{}{}
 		if (paramValue.Kind() == reflect.Slice || paramValue.Kind() == reflect.Array) &&
 			!paramValue.Type().ConvertibleTo(reflect.TypeOf([]byte{})) {
 			paramLen := paramValue.Len()
@@ -194,45 +226,17 @@ func extendInParams(sqlText string, params map[string]any) (string, []any, error
 					newParams = append(newParams, sql.Named(newParamName, paramValue.Index(j).Interface()))
 				}
 			}
-		} else {
-			// 处理非切片类型参数
-			newSqlBuilder.WriteRune('@')
-			newSqlBuilder.WriteString(paramName)
-
-			// 只在参数首次出现时添加到参数列表。
-			if !hasThisParam {
-				newParams = append(newParams, sql.Named(paramName, param))
-			}
+			continue
 		}
 
-		// 更新参数状态并处理结束字符
-		hasParam[paramName] = struct{}{}
+		// 处理非切片类型参数
+		newSqlBuilder.WriteString(token.Text)
 
-		// 如果是参数名的下一个字符触发的参数处理逻辑，该字符需要入语句。
-		if !inName {
-			newSqlBuilder.WriteRune(r)
+		// 只在参数首次出现时添加到参数列表。
+		if !hasThisParam {
+			newParams = append(newParams, sql.Named(paramName, param))
 		}
 	}
 
 	return newSqlBuilder.String(), newParams, nil
 }
-
-// 用于初始化合法字符集合 map，用于快速筛选合法字符。
-var onceInitParamNameMap = sync.Once{}
-
-// 定义 mssql 参数名允许的字符。
-var legalParamNameCharactersMap map[rune]struct{}
-
-// 用于快速判断某个字符是否是占位符参数合法字符。
-func isLegalParamNameCharter(r rune) bool {
-	onceInitParamNameMap.Do(func() {
-		const legalParamNameCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
-		legalParamNameCharactersMap = make(map[rune]struct{}, len(legalParamNameCharacters))
-		for _, r := range legalParamNameCharacters {
-			legalParamNameCharactersMap[r] = struct{}{}
-		}
-	})
-
-	_, ok := legalParamNameCharactersMap[r]
-	return ok
-}
@@ -106,3 +106,33 @@ func Test_MsSqlDbClient_internalDbClient_execute_smoke(t *testing.T) {
 		t.Fatalf("cleanup Execute() error = %v", err)
 	}
 }
+
+func Test_MsSqlDbClient_internalDbClient_insert_smoke(t *testing.T) {
+	mssqlClient := getMsSqlClientOrSkip(t)
+	defer func() {
+		if _, err := mssqlClient.Execute("DELETE FROM go_TypeTest WHERE TinyIntTest=6"); err != nil {
+			t.Fatalf("cleanup Execute() error = %v", err)
+		}
+	}()
+
+	const columns = `(TinyIntTest, SmallIntTest, IntTest, BitTest, NvarcharTest, VarcharTest, NcharTest, CharTest, DateTimeTest, DateTime2Test, DateTest, TimeTest, MoneyTest, FloatTest, DecimalTest, BinaryTest)`
+	const values = `VALUES (6, 6, 6, 1, N'行6', 'Row6', N'行6', 'Row6', '2021-07-06 15:38:39.583', '2021-07-06 15:38:50.4257813', '2021-07-06', '12:06:01.345', 6.123, 6.12345, 6.45678999, 1)`
+
+	for _, sqlText := range []string{
+		"INSERT INTO go_TypeTest " + columns + " " + values + ";",
+		"INSERT INTO go_TypeTest " + columns + " OUTPUT INSERTED.Id " + values,
+	} {
+		id, err := mssqlClient.Insert(sqlText)
+		if err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+
+		maxId, _, err := mssqlClient.Scalar("SELECT MAX(Id) FROM go_TypeTest")
+		if err != nil {
+			t.Fatalf("Scalar() error = %v", err)
+		}
+		if id != maxId {
+			t.Fatalf("Insert() id = %v, want %v", id, maxId)
+		}
+	}
+}
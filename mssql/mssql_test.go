@@ -2,8 +2,12 @@ package mssql
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
+
+	mssql "github.com/denisenkom/go-mssqldb"
 )
 
 func Test_bindMsSqlArgs(t *testing.T) {
@@ -52,4 +56,68 @@ func Test_bindMsSqlArgs(t *testing.T) {
 			t.Errorf("bindArgs() args = %v, wantParam %v", gotArgs, wantParam)
 		}
 	})
+
+	t.Run("comment_and_quote", func(t *testing.T) {
+		oriSql := "SELECT [@a], '@b''@c', @@ROWCOUNT FROM go_TypeTest -- @d\nWHERE Id IN (@ids) /* @e */"
+		args := []any{map[string]any{"ids": []int{1, 2}}}
+		wantSql := "SELECT [@a], '@b''@c', @@ROWCOUNT FROM go_TypeTest -- @d\nWHERE Id IN (@ids_0,@ids_1) /* @e */"
+		wantParam := []any{sql.Named("ids_0", 1), sql.Named("ids_1", 2)}
+
+		fixedSql, gotArgs, err := bindArgs(oriSql, args...)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if fixedSql != wantSql {
+			t.Errorf("bindArgs() sql = %v, wantSql %v", fixedSql, wantSql)
+		}
+
+		if !reflect.DeepEqual(gotArgs, wantParam) {
+			t.Errorf("bindArgs() args = %v, wantParam %v", gotArgs, wantParam)
+		}
+	})
+}
+
+func Test_isRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"deadlock", mssql.Error{Number: 1205}, true},
+		{"wrapped", fmt.Errorf("wrapped: %w", mssql.Error{Number: 1205}), true},
+		{"duplicate_key", mssql.Error{Number: 2627}, false},
+		{"other", errors.New("other"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Errorf("isRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mssqlInsertIdDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		sqlText string
+		want    string
+	}{
+		{"scope_identity", "INSERT INTO t(a) VALUES (@p1);  ", "INSERT INTO t(a) VALUES (@p1);\nSELECT CAST(SCOPE_IDENTITY() AS BIGINT)"},
+		{"output_inserted", "INSERT INTO t(a) OUTPUT INSERTED.Id VALUES (@p1)", "INSERT INTO t(a) OUTPUT INSERTED.Id VALUES (@p1)"},
+		{"output_inserted_in_string", "INSERT INTO t(note) VALUES ('OUTPUT INSERTED.x') -- OUTPUT INSERTED.x", "INSERT INTO t(note) VALUES ('OUTPUT INSERTED.x');\nSELECT CAST(SCOPE_IDENTITY() AS BIGINT)"},
+		{"output_inserted_lower", "insert into t(a) output\n\tinserted.Id values (@p1)", "insert into t(a) output\n\tinserted.Id values (@p1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mssqlInsertIdDialect{}.InsertIdSql(tt.sqlText)
+			if !ok || got != tt.want {
+				t.Errorf("InsertIdSql() = %q %v, want %q true", got, ok, tt.want)
+			}
+		})
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/testenv"
 )
 
 func Test_MssqlTransaction(t *testing.T) {
@@ -26,6 +27,25 @@ INSERT INTO go_TypeTest (TinyIntTest, SmallIntTest, IntTest, BitTest, NvarcharTe
 	}
 }
 
+func Test_MssqlTransactionSavepoint(t *testing.T) {
+	getMsSqlClientOrSkip(t)
+	mssqlClient, err := testenv.NewSqlServerClient(sqlmer.WithSavepoint(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 准备数据。
+	if _, err = mssqlClient.Execute(`INSERT INTO go_TypeTest (TinyIntTest, SmallIntTest, IntTest, BitTest, NvarcharTest, VarcharTest, NcharTest, CharTest, DateTimeTest, DateTime2Test, DateTest, TimeTest, MoneyTest, FloatTest, DecimalTest, BinaryTest)
+	VALUES (32, 32, 32, 32, N'行32', 'Row32', N'行32', 'Row32', '2021-07-30 15:38:39.583', '2021-07-30 15:38:50.4257813', '2021-07-30', '12:30:01.345', 32.123, 32.12345, 32.45678999, 1);`); err != nil {
+		t.Fatalf("prepare error: %v", err)
+	}
+	TransactionSavepointTest(t, mssqlClient, "TinyIntTest=32")
+	// 清理数据
+	if _, err = mssqlClient.Execute("DELETE FROM go_TypeTest WHERE TinyIntTest=32"); err != nil {
+		t.Errorf("clean error: %v", err)
+	}
+}
+
 func TransactionFuncTest(t *testing.T, dbClient sqlmer.DbClient) {
 	// 测试事务回滚。
 	t.Run("rollback", func(t *testing.T) {
@@ -256,3 +276,126 @@ func TransactionEmbeddedRollback(t *testing.T, maxDepth int, currentDepth int, t
 		return
 	}
 }
+
+func TransactionSavepointTest(t *testing.T, dbClient sqlmer.DbClient, condition string) {
+	existsInTx := func(t *testing.T, tx sqlmer.TransactionKeeper) bool {
+		t.Helper()
+		exist, err := tx.Exists("SELECT 1 FROM go_TypeTest WHERE " + condition)
+		if err != nil {
+			t.Fatalf("transactionKeeper.Exists() error = %v, wantErr nil", err)
+		}
+		return exist
+	}
+
+	tx, err := dbClient.CreateTransaction()
+	if err != nil {
+		t.Fatalf("dbClient.CreateTransaction() error = %v, wantErr nil", err)
+	}
+	defer tx.Close()
+
+	// 测试内层事务回滚到保存点，外层事务不受影响。
+	t.Run("embeddedRollback", func(t *testing.T) {
+		embeddedTx, err := tx.CreateTransaction()
+		if err != nil {
+			t.Fatalf("transactionKeeper.CreateTransaction() error = %v, wantErr nil", err)
+		}
+		defer embeddedTx.Close()
+
+		if _, err = embeddedTx.Execute("DELETE FROM go_TypeTest WHERE " + condition); err != nil {
+			t.Fatalf("transactionKeeper.Execute() error = %v, wantErr nil", err)
+		}
+		if existsInTx(t, tx) {
+			t.Fatal("transactionKeeper.Exists() exist, want not exist")
+		}
+
+		if err = embeddedTx.Rollback(); err != nil {
+			t.Fatalf("transactionKeeper.Rollback() error = %v, wantErr nil", err)
+		}
+		if !existsInTx(t, tx) {
+			t.Fatal("transactionKeeper.Exists() not exist, want exist")
+		}
+
+		if err = embeddedTx.Commit(); !errors.Is(err, sqlmer.ErrTran) {
+			t.Fatalf("transactionKeeper.Commit() error = %v, wantErr ErrTran", err)
+		}
+	})
+
+	// 测试内层事务未提交时， Close 会回滚到保存点。
+	t.Run("embeddedClose", func(t *testing.T) {
+		embeddedTx, err := tx.CreateTransaction()
+		if err != nil {
+			t.Fatalf("transactionKeeper.CreateTransaction() error = %v, wantErr nil", err)
+		}
+
+		if _, err = embeddedTx.Execute("DELETE FROM go_TypeTest WHERE " + condition); err != nil {
+			t.Fatalf("transactionKeeper.Execute() error = %v, wantErr nil", err)
+		}
+		if err = embeddedTx.Close(); err != nil {
+			t.Fatalf("transactionKeeper.Close() error = %v, wantErr nil", err)
+		}
+		if !existsInTx(t, tx) {
+			t.Fatal("transactionKeeper.Exists() not exist, want exist")
+		}
+	})
+
+	// 测试多层嵌套：最内层提交，中间层回滚，最内层的修改也被回滚。
+	t.Run("multiLevel", func(t *testing.T) {
+		middleTx, err := tx.CreateTransaction()
+		if err != nil {
+			t.Fatalf("transactionKeeper.CreateTransaction() error = %v, wantErr nil", err)
+		}
+		defer middleTx.Close()
+
+		innerTx, err := middleTx.CreateTransaction()
+		if err != nil {
+			t.Fatalf("transactionKeeper.CreateTransaction() error = %v, wantErr nil", err)
+		}
+		defer innerTx.Close()
+
+		if _, err = innerTx.Execute("DELETE FROM go_TypeTest WHERE " + condition); err != nil {
+			t.Fatalf("transactionKeeper.Execute() error = %v, wantErr nil", err)
+		}
+		if err = innerTx.Commit(); err != nil {
+			t.Fatalf("transactionKeeper.Commit() error = %v, wantErr nil", err)
+		}
+		if existsInTx(t, tx) {
+			t.Fatal("transactionKeeper.Exists() exist, want not exist")
+		}
+
+		if err = middleTx.Rollback(); err != nil {
+			t.Fatalf("transactionKeeper.Rollback() error = %v, wantErr nil", err)
+		}
+		if !existsInTx(t, tx) {
+			t.Fatal("transactionKeeper.Exists() not exist, want exist")
+		}
+	})
+
+	// 测试内层事务提交后，修改由外层事务决定是否生效。
+	t.Run("embeddedCommit", func(t *testing.T) {
+		embeddedTx, err := tx.CreateTransaction()
+		if err != nil {
+			t.Fatalf("transactionKeeper.CreateTransaction() error = %v, wantErr nil", err)
+		}
+		defer embeddedTx.Close()
+
+		if _, err = embeddedTx.Execute("DELETE FROM go_TypeTest WHERE " + condition); err != nil {
+			t.Fatalf("transactionKeeper.Execute() error = %v, wantErr nil", err)
+		}
+		if err = embeddedTx.Commit(); err != nil {
+			t.Fatalf("transactionKeeper.Commit() error = %v, wantErr nil", err)
+		}
+		if existsInTx(t, tx) {
+			t.Fatal("transactionKeeper.Exists() exist, want not exist")
+		}
+	})
+
+	// 回滚外层事务，内层已提交的修改也一并回滚。
+	if err = tx.Rollback(); err != nil {
+		t.Fatalf("transactionKeeper.Rollback() error = %v, wantErr nil", err)
+	}
+	if exist, err := dbClient.Exists("SELECT 1 FROM go_TypeTest WHERE " + condition); err != nil {
+		t.Fatalf("dbClient.Exists() error = %v, wantErr nil", err)
+	} else if !exist {
+		t.Fatal("dbClient.Exists() not exist, want exist")
+	}
+}
@@ -2,12 +2,15 @@ package mysql
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
 
 	"github.com/bunnier/sqlmer"
 	"github.com/bunnier/sqlmer/internal/named2qm"
+	"github.com/bunnier/sqlmer/internal/sqlscript"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 	"github.com/bunnier/sqlmer/sqlen"
 
 	mysqlDriver "github.com/go-sql-driver/mysql"
@@ -40,9 +43,14 @@ func NewMySqlDbClient(dsn string, options ...sqlmer.DbClientOption) (*MySqlDbCli
 
 	fixedOptions := []sqlmer.DbClientOption{
 		sqlmer.WithDsn(DriverName, dsn),
-		sqlmer.WithGetScanTypeFunc(getScanTypeFn(dsnConfig)),        // 定制 Scan 类型逻辑。
-		sqlmer.WithUnifyDataTypeFunc(getUnifyDataTypeFn(dsnConfig)), // 定制类型转换逻辑。
-		sqlmer.WithBindArgsFunc(bindArgs),                           // 定制参数绑定逻辑。
+		sqlmer.WithGetScanTypeFunc(getScanTypeFn(dsnConfig)),           // 定制 Scan 类型逻辑。
+		sqlmer.WithUnifyDataTypeFunc(getUnifyDataTypeFn(dsnConfig)),    // 定制类型转换逻辑。
+		sqlmer.WithBindArgsFunc(bindArgs),                              // 定制参数绑定逻辑。
+		sqlmer.WithParseParamNamesFunc(defaultBinder.ParseParamNames),  // 定制命名参数解析逻辑。
+		sqlmer.WithSavepointDialect(sqlmer.StandardSavepointDialect{}), // 使用 SQL 标准的保存点语句。
+		sqlmer.WithRetryableErrorFunc(isRetryableError),                // 定制可重试错误的判断逻辑。
+		sqlmer.WithMaxParamCount(65535),                                // MySQL 预处理语句最多 65535 个参数。
+		sqlmer.WithSplitScriptFunc(splitScript),                        // 按 MySQL 客户端的规则拆分脚本。
 	}
 	options = append(fixedOptions, options...) // 用户自定义选项放后面，以覆盖默认。
 
@@ -56,10 +64,11 @@ func NewMySqlDbClient(dsn string, options ...sqlmer.DbClientOption) (*MySqlDbCli
 		return nil, err
 	}
 
-	// 自动设置连接池的超时时间，
-	err = autoSetConnMaxLifetime(absDbClient.Db)
-	if err != nil {
-		return nil, err
+	// 自动设置连接池的超时时间，用户通过 WithConnMaxLifetime 指定了的话，以用户的设置为准。
+	if _, ok := config.ConnMaxLifetime(); !ok {
+		if err = autoSetConnMaxLifetime(absDbClient.Db); err != nil {
+			return nil, err
+		}
 	}
 
 	return &MySqlDbClient{absDbClient, dsnConfig}, nil
@@ -100,12 +109,31 @@ func autoSetConnMaxLifetime(db *sqlen.DbEnhance) error {
 	return nil
 }
 
+// isRetryableError 用于判断错误是否可以通过重试事务解决：
+//   - 1213 ER_LOCK_DEADLOCK ：发生死锁，事务已被回滚；
+//   - 1205 ER_LOCK_WAIT_TIMEOUT ：等待锁超时。
+func isRetryableError(err error) bool {
+	var mysqlErr *mysqlDriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+}
+
+// splitScript 用于按 MySQL 客户端的规则拆分脚本：按 ; 拆分，支持通过单独一行的 DELIMITER 命令修改分隔符。
+func splitScript(script string) []string {
+	return sqlscript.Split(script, sqlscript.MySql)
+}
+
 // bindArgs 用于对 SQL 语句和参数进行预处理。
 // 第一个参数如果是 map，且仅且只有一个参数的情况下，做命名参数处理，其余情况做位置参数处理。
 func bindArgs(sqlText string, args ...any) (string, []any, error) {
-	return named2qm.BindQuestionMarkArgs(sqlText, args...)
+	return defaultBinder.BindQuestionMarkArgs(sqlText, args...)
 }
 
+// defaultBinder 是按 MySQL 方言识别字符串、标识符及注释的默认绑定器。
+var defaultBinder, _ = named2qm.NewQuestionMarkSqlBinder(named2qm.DefaultCacheCapacity, sqltoken.MySql)
+
 // getScanTypeFn 根据驱动配置返回一个可以正确获取 Scan 类型的函数。
 func getScanTypeFn(cfg *mysqlDriver.Config) sqlen.GetScanTypeFunc {
 	var scanTypeRawBytes = reflect.TypeOf(sql.RawBytes{})
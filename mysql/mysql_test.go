@@ -0,0 +1,31 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+)
+
+func Test_isRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"deadlock", &mysqlDriver.MySQLError{Number: 1213}, true},
+		{"lock_wait_timeout", &mysqlDriver.MySQLError{Number: 1205}, true},
+		{"wrapped", fmt.Errorf("wrapped: %w", &mysqlDriver.MySQLError{Number: 1213}), true},
+		{"duplicate_entry", &mysqlDriver.MySQLError{Number: 1062}, false},
+		{"other", errors.New("other"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Errorf("isRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package otelsqlmer
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/sqlen"
+	"github.com/bunnier/sqlmer/wrap"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ sqlmer.DbClient = (*DbClient)(nil)
+
+// DbClient 是带有链路追踪的 DbClient ，由 Extend 创建。
+// 不带 context 的方法，使用带有默认执行超时的 context 调用对应的带 context 的方法，
+// 以便语句的 span 可以传递给 sqlmer.WithObserver 注册的 Observer 。
+type DbClient struct {
+	*wrap.WrappedDbClient
+
+	raw    sqlmer.DbClient // 原始的 DbClient 实例。
+	config *config
+	txSpan trace.Span // 所在事务的 span ，不在事务中时为 nil 。
+}
+
+// Extend 用于包裹 DbClient ，为其上（包括其上开启的事务中）执行的语句和事务创建 span 。
+// params:
+//
+//	@raw 原始的 DbClient 实例，创建时通过 sqlmer.WithObserver 注册 Observer ，可以记录绑定参数后的 SQL 。
+//	@options 可选配置，见 WithTracerProvider 、 WithDBSystem 、 WithAttributes 。
+//
+// 语句的 span 以 SQL 的操作名（如 SELECT ）命名，父 span 为所在事务的 span 或 context 中的 span ；
+// 带有 db.system 、 db.statement 、 db.operation ，以及 sqlmer.method 、 sqlmer.rows_affected 等属性。
+//
+// 注意： Rows / RowsContext 方法的 span 在返回游标对象时即结束。
+func Extend(raw sqlmer.DbClient, options ...Option) *DbClient {
+	return newConfig(options).extend(raw, nil)
+}
+
+// extend 用于包裹 DbClient ，txSpan 为所在事务的 span 。
+func (c *config) extend(raw sqlmer.DbClient, txSpan trace.Span) *DbClient {
+	client := &DbClient{raw: raw, config: c, txSpan: txSpan}
+	client.WrappedDbClient = wrap.ExtendContext(raw, client.wrapFunc)
+	return client
+}
+
+// parentContext 用于获取创建 span 的 context ：在事务中时，以事务的 span 为父 span 。
+func (c *DbClient) parentContext(ctx context.Context) context.Context {
+	if c.txSpan != nil {
+		return trace.ContextWithSpan(ctx, c.txSpan)
+	}
+	return ctx
+}
+
+// wrapFunc 用于为每条语句创建 span 。
+func (c *DbClient) wrapFunc(ctx context.Context, info wrap.CallInfo) (context.Context, func(wrap.CallResult)) {
+	op := operation(info.SQL)
+	spanName := op
+	if spanName == "" {
+		spanName = "sqlmer." + info.Method
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(c.config.attrs)+4)
+	attrs = append(attrs, c.config.attrs...)
+	attrs = append(attrs,
+		semconv.DBStatement(info.SQL),
+		semconv.DBOperation(op),
+		MethodKey.String(info.Method),
+		InTxKey.Bool(info.InTx),
+	)
+
+	ctx, span := c.config.tracer.Start(c.parentContext(ctx), spanName,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	ctx = context.WithValue(ctx, statementSpanKey{}, span)
+
+	return ctx, func(result wrap.CallResult) {
+		if result.RowsAffected >= 0 {
+			span.SetAttributes(RowsAffectedKey.Int64(result.RowsAffected))
+		}
+		if result.RowsReturned >= 0 {
+			span.SetAttributes(RowsReturnedKey.Int64(result.RowsReturned))
+		}
+		recordError(span, result.Err)
+		span.End()
+	}
+}
+
+// execTimeoutContext 用于获取带有默认执行超时的 context 。
+func (c *DbClient) execTimeoutContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.GetExecTimeout())
+}
+
+// CreateTransaction 用于开始一个事务，并创建事务的 span 。
+func (c *DbClient) CreateTransaction() (sqlmer.TransactionKeeper, error) {
+	return c.CreateTransactionContext(context.Background(), nil)
+}
+
+// CreateTransactionContext 用于开始一个事务，并创建事务的 span ，span 在事务提交、回滚或关闭时结束。
+// 在事务中调用时，会加入当前事务，ctx 和 opts 沿用外层事务的设置。
+func (c *DbClient) CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (sqlmer.TransactionKeeper, error) {
+	ctx, span := c.config.tracer.Start(c.parentContext(ctx), TransactionSpanName,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(c.config.attrs...))
+
+	tx, err := c.raw.CreateTransactionContext(ctx, opts)
+	if err != nil {
+		recordError(span, err)
+		span.End()
+		return nil, err
+	}
+
+	return &TransactionKeeper{DbClient: c.config.extend(tx, span), tx: tx, span: span}, nil
+}
+
+// Execute 用于执行非查询 sql 语句，并返回所影响的行数。
+func (c *DbClient) Execute(sqlText string, args ...any) (int64, error) {
+	ctx, cancelFunc := c.execTimeoutContext()
+	defer cancelFunc()
+	return c.ExecuteContext(ctx, sqlText, args...)
+}
+
+// Insert 用于执行插入语句，并返回自增 id 。
+func (c *DbClient) Insert(sqlText string, args ...any) (int64, error) {
+	ctx, cancelFunc := c.execTimeoutContext()
+	defer cancelFunc()
+	return c.InsertContext(ctx, sqlText, args...)
+}
+
+// SizedExecute 用于执行非查询 sql 语句，并断言所影响的行数。
+func (c *DbClient) SizedExecute(expectedSize int64, sqlText string, args ...any) error {
+	ctx, cancelFunc := c.execTimeoutContext()
+	defer cancelFunc()
+	return c.SizedExecuteContext(ctx, expectedSize, sqlText, args...)
+}
+
+// Exists 用于判断给定的查询的结果是否至少包含 1 行。
+func (c *DbClient) Exists(sqlText string, args ...any) (bool, error) {
+	ctx, cancelFunc := c.execTimeoutContext()
+	defer cancelFunc()
+	return c.ExistsContext(ctx, sqlText, args...)
+}
+
+// Scalar 用于获取查询的第一行第一列的值。
+func (c *DbClient) Scalar(sqlText string, args ...any) (any, bool, error) {
+	ctx, cancelFunc := c.execTimeoutContext()
+	defer cancelFunc()
+	return c.ScalarContext(ctx, sqlText, args...)
+}
+
+// Get 用于获取查询结果的第一行记录。
+func (c *DbClient) Get(sqlText string, args ...any) (map[string]any, error) {
+	ctx, cancelFunc := c.execTimeoutContext()
+	defer cancelFunc()
+	return c.GetContext(ctx, sqlText, args...)
+}
+
+// SliceGet 用于获取查询结果的所有行。
+func (c *DbClient) SliceGet(sqlText string, args ...any) ([]map[string]any, error) {
+	ctx, cancelFunc := c.execTimeoutContext()
+	defer cancelFunc()
+	return c.SliceGetContext(ctx, sqlText, args...)
+}
+
+// Row 用于获取单个查询结果行。
+func (c *DbClient) Row(sqlText string, args ...any) (*sqlen.EnhanceRow, error) {
+	ctx, _ := c.execTimeoutContext()
+	return c.RowContext(ctx, sqlText, args...)
+}
+
+// Rows 用于获取查询结果行的游标对象。
+func (c *DbClient) Rows(sqlText string, args ...any) (*sqlen.EnhanceRows, error) {
+	ctx, _ := c.execTimeoutContext()
+	return c.RowsContext(ctx, sqlText, args...)
+}
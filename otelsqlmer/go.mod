@@ -0,0 +1,28 @@
+module github.com/bunnier/sqlmer/otelsqlmer
+
+go 1.24.0
+
+// sqlmer 与本模块一同发布：根模块打 vX.Y.Z tag 时，本模块同时打 otelsqlmer/vX.Y.Z ，并将这里的版本更新为 vX.Y.Z 。
+require (
+	github.com/bunnier/sqlmer v1.5.0
+	go.opentelemetry.io/otel v1.40.0
+	go.opentelemetry.io/otel/sdk v1.40.0
+	go.opentelemetry.io/otel/trace v1.40.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/cmstar/go-conv v0.6.6 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/ncruces/go-sqlite3 v0.30.4 // indirect
+	github.com/ncruces/julianday v1.0.0 // indirect
+	github.com/tetratelabs/wazero v1.11.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	go.opentelemetry.io/otel/metric v1.40.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+)
+
+// 仅用于在仓库内开发、测试，依赖方会忽略 replace ，使用上面 require 的版本。
+replace github.com/bunnier/sqlmer => ../
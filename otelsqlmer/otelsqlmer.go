@@ -0,0 +1,157 @@
+// Package otelsqlmer 为 sqlmer 提供 OpenTelemetry 链路追踪：通过 Extend 包裹 DbClient ，
+// 每条语句创建一个 span ，每个事务从开始到提交/回滚创建一个 span ，作为事务中语句的 span 的父 span 。
+//
+// 本包是独立的 Go module ，以免 sqlmer 引入 OpenTelemetry 的依赖。
+package otelsqlmer
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bunnier/sqlmer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName 是创建 span 使用的 Tracer 的名称。
+const TracerName = "github.com/bunnier/sqlmer/otelsqlmer"
+
+// TransactionSpanName 是事务的 span 的名称。
+const TransactionSpanName = "sqlmer.transaction"
+
+// sqlmer 特有的 span 属性。
+const (
+	MethodKey       = attribute.Key("sqlmer.method")         // 被调用的 DbClient 方法名，如 ExecuteContext 。
+	RowsAffectedKey = attribute.Key("sqlmer.rows_affected")  // Execute / SizedExecute 系列方法影响的行数。
+	RowsReturnedKey = attribute.Key("sqlmer.rows_returned")  // Get / SliceGet / Scalar 系列方法返回的行数。
+	RawStatementKey = attribute.Key("sqlmer.raw_statement")  // 参数绑定前的原始 SQL ，仅在与 db.statement 不同时记录。
+	TxOutcomeKey    = attribute.Key("sqlmer.tx.outcome")     // 事务的结果，值为 TxOutcomeCommitted 或 TxOutcomeRolledBack 。
+	InTxKey         = attribute.Key("sqlmer.in_transaction") // 语句是否在事务中执行。
+)
+
+// 事务的结果，即 TxOutcomeKey 属性的值。
+const (
+	TxOutcomeCommitted  = "committed"
+	TxOutcomeRolledBack = "rolled_back"
+)
+
+// config 是链路追踪的配置。
+type config struct {
+	tracerProvider trace.TracerProvider
+	system         string
+	attrs          []attribute.KeyValue // 所有 span 共有的属性。
+
+	tracer trace.Tracer
+}
+
+// Option 是链路追踪的可选配置。
+type Option func(config *config)
+
+// WithTracerProvider 用于设置创建 span 的 TracerProvider ，默认使用 otel.GetTracerProvider() 。
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(config *config) {
+		config.tracerProvider = provider
+	}
+}
+
+// WithDBSystem 用于设置 db.system 属性的值，如 mysql 、 mssql 、 postgresql 、 sqlite ，默认为 other_sql 。
+func WithDBSystem(system string) Option {
+	return func(config *config) {
+		config.system = system
+	}
+}
+
+// WithAttributes 用于设置所有 span 共有的其它属性，如 db.name 。
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(config *config) {
+		config.attrs = append(config.attrs, attrs...)
+	}
+}
+
+func newConfig(options []Option) *config {
+	c := &config{system: semconv.DBSystemOtherSQL.Value.AsString()}
+	for _, option := range options {
+		option(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+
+	c.tracer = c.tracerProvider.Tracer(TracerName)
+	c.attrs = append([]attribute.KeyValue{semconv.DBSystemKey.String(c.system)}, c.attrs...)
+	return c
+}
+
+// statementSpanKey 是语句的 span 在 context 中的键，供 Observer 使用。
+type statementSpanKey struct{}
+
+// Observer 是一个 sqlmer.ObserverFunc ，创建原始 DbClient 时通过 sqlmer.WithObserver 注册后，
+// 语句的 span 的 db.statement 属性会记录为参数绑定（ IN 展开、命名参数改写等）后实际执行的 SQL ，
+// 原始 SQL 记录在 sqlmer.raw_statement 属性中；未注册时， db.statement 为原始 SQL 。
+func Observer(ctx context.Context, o sqlmer.SqlObservation) {
+	span, ok := ctx.Value(statementSpanKey{}).(trace.Span)
+	if !ok || o.FixedSQL == "" {
+		return
+	}
+	setStatement(span, o.RawSQL, o.FixedSQL)
+}
+
+// setStatement 用于设置 span 的 db.statement 属性为绑定参数后的 SQL 。
+func setStatement(span trace.Span, rawSql string, fixedSql string) {
+	span.SetAttributes(semconv.DBStatement(fixedSql))
+	if fixedSql != rawSql {
+		span.SetAttributes(RawStatementKey.String(rawSql))
+	}
+}
+
+// recordError 用于在 span 上记录错误。错误为 sqlmer.SqlContextError 时，
+// 只记录底层错误，以免参数值被输出到链路追踪中，并记录绑定参数后的 SQL （若有）。
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	var sqlErr *sqlmer.SqlContextError
+	if errors.As(err, &sqlErr) && sqlErr.Err != nil {
+		if sqlErr.FixedSQL != "" {
+			setStatement(span, sqlErr.RawSQL, sqlErr.FixedSQL)
+		}
+		err = sqlErr.Err
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// operation 用于获取 SQL 语句的操作名，即跳过空白和注释后的第一个单词（大写），如 SELECT 、 INSERT 。
+func operation(sqlText string) string {
+	for {
+		sqlText = strings.TrimLeft(sqlText, " \t\r\n(")
+		switch {
+		case strings.HasPrefix(sqlText, "--"):
+			end := strings.IndexByte(sqlText, '\n')
+			if end < 0 {
+				return ""
+			}
+			sqlText = sqlText[end+1:]
+		case strings.HasPrefix(sqlText, "/*"):
+			end := strings.Index(sqlText, "*/")
+			if end < 0 {
+				return ""
+			}
+			sqlText = sqlText[end+2:]
+		default:
+			end := strings.IndexFunc(sqlText, func(r rune) bool {
+				return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+			})
+			if end < 0 {
+				end = len(sqlText)
+			}
+			return strings.ToUpper(sqlText[:end])
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package otelsqlmer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/sqlite"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTracedClient 返回一个带有链路追踪的 SQLite DbClient ，以及记录 span 的内存 exporter 。
+func newTracedClient(t *testing.T, options ...sqlmer.DbClientOption) (*DbClient, *tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	t.Helper()
+
+	raw, err := sqlite.NewSqliteDbClient(filepath.Join(t.TempDir(), "otel.db"), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { raw.Close() })
+	if _, err := raw.Execute("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := raw.Execute("INSERT INTO users (name) VALUES ('a'), ('b'), ('c')"); err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return Extend(raw, WithTracerProvider(provider), WithDBSystem("sqlite")), exporter, provider
+}
+
+// attrs 用于获取 span 的属性。
+func attrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, attr := range span.Attributes {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
+
+func TestExtend_Statement(t *testing.T) {
+	dbClient, exporter, _ := newTracedClient(t, sqlmer.WithObserver(Observer))
+
+	rows, err := dbClient.Execute("UPDATE users SET name = @name WHERE id IN (@ids)", map[string]any{"name": "x", "ids": []int{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows != 2 {
+		t.Fatalf("Execute() = %d, want 2", rows)
+	}
+	if _, err := dbClient.SliceGet("SELECT * FROM users"); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	update := attrs(spans[0])
+	if spans[0].Name != "UPDATE" {
+		t.Errorf("span name = %q, want UPDATE", spans[0].Name)
+	}
+	want := map[attribute.Key]attribute.Value{
+		"db.system":     attribute.StringValue("sqlite"),
+		"db.statement":  attribute.StringValue("UPDATE users SET name = ? WHERE id IN (?,?)"),
+		"db.operation":  attribute.StringValue("UPDATE"),
+		RawStatementKey: attribute.StringValue("UPDATE users SET name = @name WHERE id IN (@ids)"),
+		MethodKey:       attribute.StringValue("ExecuteContext"),
+		RowsAffectedKey: attribute.Int64Value(2),
+		InTxKey:         attribute.BoolValue(false),
+	}
+	for key, value := range want {
+		if update[key] != value {
+			t.Errorf("attribute %s = %v, want %v", key, update[key].Emit(), value.Emit())
+		}
+	}
+
+	if got := attrs(spans[1])[RowsReturnedKey]; got != attribute.Int64Value(3) {
+		t.Errorf("attribute %s = %v, want 3", RowsReturnedKey, got.Emit())
+	}
+}
+
+func TestExtend_WithoutObserver(t *testing.T) {
+	dbClient, exporter, _ := newTracedClient(t)
+
+	if _, err := dbClient.Get("SELECT * FROM users WHERE id = @p1", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if got := attrs(spans[0])["db.statement"].AsString(); got != "SELECT * FROM users WHERE id = @p1" {
+		t.Errorf("db.statement = %q, want the raw SQL", got)
+	}
+}
+
+func TestExtend_Parent(t *testing.T) {
+	dbClient, exporter, provider := newTracedClient(t)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	if _, _, err := dbClient.ScalarContext(ctx, "SELECT COUNT(1) FROM users"); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("statement span parent = %v, want %v", spans[0].Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+}
+
+func TestExtend_Error(t *testing.T) {
+	dbClient, exporter, _ := newTracedClient(t)
+
+	_, err := dbClient.SliceGet("SELECT * FROM users WHERE id IN (@p1)", []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dbClient.SliceGet("SELECT * FROM not_exists WHERE id = @p1", "secret")
+	if !errors.Is(err, sqlmer.ErrExecutingSql) {
+		t.Fatalf("SliceGet() error = %v, want ErrExecutingSql", err)
+	}
+
+	spans := exporter.GetSpans()
+	span := spans[len(spans)-1]
+	if span.Status.Code != codes.Error {
+		t.Fatalf("span status = %v, want Error", span.Status)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+		t.Fatalf("span events = %v, want one exception event", span.Events)
+	}
+
+	var sqlErr *sqlmer.SqlContextError
+	if !errors.As(err, &sqlErr) {
+		t.Fatal("error is not a SqlContextError")
+	}
+	if span.Status.Description != sqlErr.Err.Error() {
+		t.Errorf("span status description = %q, want %q", span.Status.Description, sqlErr.Err.Error())
+	}
+	if got := attrs(span)["db.statement"].AsString(); got != sqlErr.FixedSQL {
+		t.Errorf("db.statement = %q, want the bound SQL %q", got, sqlErr.FixedSQL)
+	}
+}
+
+func TestExtend_Transaction(t *testing.T) {
+	dbClient, exporter, _ := newTracedClient(t)
+
+	t.Run("commit", func(t *testing.T) {
+		exporter.Reset()
+
+		tx, err := dbClient.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+
+		if _, err := tx.Execute("UPDATE users SET name = 'y' WHERE id = 1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		tx.Close()
+
+		spans := exporter.GetSpans()
+		if len(spans) != 2 {
+			t.Fatalf("got %d spans, want 2", len(spans))
+		}
+		statement, txSpan := spans[0], spans[1]
+		if txSpan.Name != TransactionSpanName {
+			t.Fatalf("span name = %q, want %q", txSpan.Name, TransactionSpanName)
+		}
+		if statement.Parent.SpanID() != txSpan.SpanContext.SpanID() {
+			t.Errorf("statement span parent = %v, want the transaction span", statement.Parent.SpanID())
+		}
+		if got := attrs(statement)[InTxKey]; got != attribute.BoolValue(true) {
+			t.Errorf("attribute %s = %v, want true", InTxKey, got.Emit())
+		}
+		if got := attrs(txSpan)[TxOutcomeKey].AsString(); got != TxOutcomeCommitted {
+			t.Errorf("attribute %s = %q, want %q", TxOutcomeKey, got, TxOutcomeCommitted)
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		exporter.Reset()
+
+		tx, err := dbClient.CreateTransactionContext(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nested, err := tx.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := nested.Execute("UPDATE users SET name = 'z' WHERE id = 2"); err != nil {
+			t.Fatal(err)
+		}
+		nested.Close()
+		tx.Close()
+
+		spans := exporter.GetSpans()
+		if len(spans) != 3 {
+			t.Fatalf("got %d spans, want 3", len(spans))
+		}
+		statement, nestedSpan, txSpan := spans[0], spans[1], spans[2]
+		if statement.Parent.SpanID() != nestedSpan.SpanContext.SpanID() || nestedSpan.Parent.SpanID() != txSpan.SpanContext.SpanID() {
+			t.Error("spans are not nested as statement -> nested transaction -> transaction")
+		}
+		for _, span := range []tracetest.SpanStub{nestedSpan, txSpan} {
+			if got := attrs(span)[TxOutcomeKey].AsString(); got != TxOutcomeRolledBack {
+				t.Errorf("attribute %s = %q, want %q", TxOutcomeKey, got, TxOutcomeRolledBack)
+			}
+		}
+
+		if name, _, err := dbClient.Scalar("SELECT name FROM users WHERE id = 2"); err != nil || name != "b" {
+			t.Errorf("Scalar() = %v, %v, want the update rolled back", name, err)
+		}
+	})
+}
+
+func Test_operation(t *testing.T) {
+	tests := []struct {
+		sqlText string
+		want    string
+	}{
+		{"SELECT 1", "SELECT"},
+		{"  \n insert INTO t VALUES (1)", "INSERT"},
+		{"-- comment\n/* block */ (SELECT 1)", "SELECT"},
+		{"/* unterminated", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := operation(tt.sqlText); got != tt.want {
+			t.Errorf("operation(%q) = %q, want %q", tt.sqlText, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package otelsqlmer
+
+import (
+	"sync/atomic"
+
+	"github.com/bunnier/sqlmer"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ sqlmer.TransactionKeeper = (*TransactionKeeper)(nil)
+
+// TransactionKeeper 是带有链路追踪的事务，事务中语句的 span 以事务的 span 为父 span 。
+type TransactionKeeper struct {
+	*DbClient                          // 事务上执行语句的 DbClient 。
+	tx        sqlmer.TransactionKeeper // 原始的事务。
+	span      trace.Span               // 事务的 span 。
+	ended     atomic.Bool              // 事务的 span 是否已结束。
+}
+
+// Commit 用于提交事务，并结束事务的 span 。
+func (t *TransactionKeeper) Commit() error {
+	err := t.tx.Commit()
+	t.end(TxOutcomeCommitted, err)
+	return err
+}
+
+// Rollback 用于回滚事务，并结束事务的 span 。
+func (t *TransactionKeeper) Rollback() error {
+	err := t.tx.Rollback()
+	t.end(TxOutcomeRolledBack, err)
+	return err
+}
+
+// Close 用于优雅关闭事务，未提交或回滚的事务将被回滚，并结束事务的 span 。
+func (t *TransactionKeeper) Close() error {
+	err := t.tx.Close()
+	t.end(TxOutcomeRolledBack, err)
+	return err
+}
+
+// end 用于结束事务的 span ，只有第一次调用生效；执行成功时记录事务的结果，否则记录错误。
+func (t *TransactionKeeper) end(outcome string, err error) {
+	if !t.ended.CompareAndSwap(false, true) {
+		return
+	}
+
+	if err == nil {
+		t.span.SetAttributes(TxOutcomeKey.String(outcome))
+	} else {
+		recordError(t.span, err)
+	}
+	t.span.End()
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
+)
+
+// parsedSql 是 SQL 命名参数解析后的结果。
+type parsedSql struct {
+	segments []string // 被参数分隔开的 SQL 片段，数量总是 len(names)+1 。
+	names    []string // 原语句中用到的命名参数集合（没有去重逻辑，如果有同名参数，会有多项）。
+}
+
+// parseNamedSql 分析 SQL 语句，提取用到的命名参数名称（按顺序），并将语句按参数位置切分为片段。
+// 与 ? 占位符的驱动不同，PostgreSQL 中 @> 、 <@ 等是合法的操作符，因此 @ 后若不是合法的参数名字符，会原样保留；
+// 连续 2 个 @ 依然作为转义，输出为 1 个 @ （如全文检索的 @@ 操作符需要写作 @@@@ ）。
+func parseNamedSql(sqlText string) parsedSql {
+	segments := make([]string, 0, 10)
+	names := make([]string, 0, 10)
+	segmentBuilder := strings.Builder{}
+
+	for _, token := range sqltoken.Tokenize(sqlText, sqltoken.Postgres) {
+		switch token.Kind {
+		// 命名参数将语句切分为前后两个片段。
+		case sqltoken.NamedParam:
+			names = append(names, token.Name())
+			segments = append(segments, segmentBuilder.String())
+			segmentBuilder.Reset()
+
+		// 连续 2 个 @ 用来转义。
+		case sqltoken.EscapedAt:
+			segmentBuilder.WriteByte('@')
+
+		// 字符串、标识符、注释等其余部分原样输出。
+		default:
+			segmentBuilder.WriteString(token.Text)
+		}
+	}
+
+	segments = append(segments, segmentBuilder.String())
+	return parsedSql{segments, names}
+}
+
+// parseParamNames 用于解析 SQL 语句中用到的命名参数名称（按顺序）。
+func parseParamNames(sqlText string) []string {
+	return parseNamedSql(sqlText).names
+}
+
+// bindArgs 用于对 SQL 语句和参数进行预处理，将 @name 、 @p1 形式的参数转为 PostgreSQL 的 $1...$N 占位符。
+// 若唯一参数为 map，则按 SQL 中的 @name 绑定；否则按 @p1、@p2 形式从 args 切片取位置参数。
+// 同名参数重复出现时复用同一个占位符编号；切片类型的参数会被展开为多个占位符，用于 IN 子句。
+func bindArgs(sqlText string, args ...any) (string, []any, error) {
+	parsed := parseNamedSql(sqlText)
+	if len(parsed.names) == 0 {
+		// 没有命名参数的语句，可能是直接使用 $N 占位符编写的，参数原样交给驱动。
+		return strings.Join(parsed.segments, ""), args, nil
+	}
+
+	var getParam func(name string) (any, error)
+	if len(args) == 1 && reflect.ValueOf(args[0]).Kind() == reflect.Map {
+		mapArgs := args[0].(map[string]any)
+		getParam = func(name string) (any, error) {
+			if value, ok := mapArgs[name]; ok {
+				return value, nil
+			}
+			return nil, fmt.Errorf("%w:\nlack of parameter\nsql = %s", sqlmer.ErrParseParamFailed, sqlText)
+		}
+	} else {
+		getParam = func(name string) (any, error) {
+			if name[0] != 'p' { // 要求数字参数的格式为 @p1....@pN。
+				return nil, fmt.Errorf("%w: parsing parameter failed\nsql = %s", sqlmer.ErrParseParamFailed, sqlText)
+			}
+
+			index, err := strconv.Atoi(name[1:]) // 取出索引值。
+			if err != nil {
+				return nil, fmt.Errorf("%w: parsing parameter failed\nsql = %s", sqlmer.ErrParseParamFailed, sqlText)
+			}
+
+			index-- // 占位符从 1 开始，转成索引，需要 -1。
+			if index < 0 {
+				return nil, fmt.Errorf("%w: index must start at 1\nsql = %s", sqlmer.ErrParseParamFailed, sqlText)
+			}
+
+			if index >= len(args) {
+				return nil, fmt.Errorf("%w: parameter index out of args range\nsql = %s", sqlmer.ErrParseParamFailed, sqlText)
+			}
+			return args[index], nil
+		}
+	}
+
+	fixedSqlBuilder := strings.Builder{}
+	resultArgs := make([]any, 0, len(parsed.names))
+	placeholders := make(map[string]string, len(parsed.names)) // 参数名到已生成占位符的映射，用于同名参数复用编号。
+	for i, name := range parsed.names {
+		fixedSqlBuilder.WriteString(parsed.segments[i])
+
+		if placeholder, ok := placeholders[name]; ok {
+			fixedSqlBuilder.WriteString(placeholder)
+			continue
+		}
+
+		param, err := getParam(name)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var placeholder string
+		paramValue := reflect.ValueOf(param)
+
+		// 处理切片类型。
+		// 排除 []byte，因为虽然 []byte 也是切片类型，但它是二进制数据，不应该被展开。
+		if (paramValue.Kind() == reflect.Slice || paramValue.Kind() == reflect.Array) &&
+			!paramValue.Type().ConvertibleTo(reflect.TypeOf([]byte{})) {
+			paramLen := paramValue.Len()
+			if paramLen == 0 {
+				placeholder = "NULL" // 空切片替换为 SQL 不可能条件。
+			} else {
+				placeholderBuilder := strings.Builder{}
+				for j := 0; j < paramLen; j++ {
+					if j != 0 {
+						placeholderBuilder.WriteByte(',')
+					}
+					resultArgs = append(resultArgs, paramValue.Index(j).Interface())
+					placeholderBuilder.WriteByte('$')
+					placeholderBuilder.WriteString(strconv.Itoa(len(resultArgs)))
+				}
+				placeholder = placeholderBuilder.String()
+			}
+		} else {
+			resultArgs = append(resultArgs, param)
+			placeholder = "$" + strconv.Itoa(len(resultArgs))
+		}
+
+		placeholders[name] = placeholder
+		fixedSqlBuilder.WriteString(placeholder)
+	}
+	fixedSqlBuilder.WriteString(parsed.segments[len(parsed.names)])
+
+	return fixedSqlBuilder.String(), resultArgs, nil
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/sqlscript"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
+	"github.com/lib/pq"
+)
+
+// DriverName 是 PostgreSQL 驱动名称。
+const DriverName = "postgres"
+
+var _ sqlmer.DbClient = (*PostgresDbClient)(nil)
+
+// PostgresDbClient 是针对 PostgreSQL 的 DbClient 实现。
+type PostgresDbClient struct {
+	*sqlmer.AbstractDbClient
+}
+
+// NewPostgresDbClient 用于创建一个 PostgresDbClient。
+func NewPostgresDbClient(dsn string, options ...sqlmer.DbClientOption) (*PostgresDbClient, error) {
+	fixedOptions := []sqlmer.DbClientOption{
+		sqlmer.WithDsn(DriverName, dsn),
+		sqlmer.WithGetScanTypeFunc(getScanType),                        // 定制 Scan 类型逻辑。
+		sqlmer.WithUnifyDataTypeFunc(unifyDataType),                    // 定制类型转换逻辑。
+		sqlmer.WithBindArgsFunc(bindArgs),                              // PostgreSQL 使用 $N 占位符，需要定制一个参数解析函数。
+		sqlmer.WithParseParamNamesFunc(parseParamNames),                // 定制命名参数解析逻辑。
+		sqlmer.WithSavepointDialect(sqlmer.StandardSavepointDialect{}), // 使用 SQL 标准的保存点语句。
+		sqlmer.WithRetryableErrorFunc(isRetryableError),                // 定制可重试错误的判断逻辑。
+		sqlmer.WithMaxParamCount(65535),                                // PostgreSQL 协议最多支持 65535 个参数。
+		sqlmer.WithInsertIdDialect(ReturningInsertIdDialect{"id"}),     // PostgreSQL 驱动不支持 LastInsertId 。
+		sqlmer.WithSplitScriptFunc(splitScript),                        // 按 ; 拆分脚本，跳过 $$ 字符串。
+	}
+	options = append(fixedOptions, options...) // 用户自定义选项放后面，以覆盖默认。
+
+	config, err := sqlmer.NewDbClientConfig(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	absDbClient, err := sqlmer.NewAbstractDbClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresDbClient{absDbClient}, nil
+}
+
+// returningRegexp 用于判断插入语句是否已经通过 RETURNING 子句返回插入的数据。
+var returningRegexp = regexp.MustCompile(`(?i)\bRETURNING\b`)
+
+// ReturningInsertIdDialect 是 PostgreSQL 获取自增 id 的方式，驱动不支持 LastInsertId ，通过 RETURNING 子句返回自增 id ：
+//   - 语句中已包含 RETURNING 子句的，直接读取其返回的第一列；
+//   - 否则追加 RETURNING Column ，默认的 Column 为 id ，自增列不是 id 的，可以通过 sqlmer.WithInsertIdDialect 替换。
+//
+// 改写后仍是单条语句，表中没有 Column 列时，语句执行失败，不会插入数据。
+type ReturningInsertIdDialect struct {
+	Column string // 自增列的列名，会原样拼接到语句中。
+}
+
+// InsertIdSql 将插入语句改写为返回自增 id 的查询语句。
+func (d ReturningInsertIdDialect) InsertIdSql(sqlText string) (string, bool) {
+	tokens := sqltoken.Tokenize(sqlText, sqltoken.Postgres)
+	for _, token := range tokens {
+		if token.Kind == sqltoken.Text && returningRegexp.MatchString(token.Text) {
+			return sqlText, true
+		}
+	}
+	return sqltoken.TrimStatementEnd(tokens) + "\nRETURNING " + d.Column, true
+}
+
+// isRetryableError 用于判断错误是否可以通过重试事务解决：
+//   - 40001 serialization_failure ：可串行化隔离级别下的序列化失败；
+//   - 40P01 deadlock_detected ：发生死锁。
+func isRetryableError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
+
+// splitScript 用于按 ; 拆分脚本，函数体等 $tag$...$tag$ 字符串中的 ; 不拆分。
+func splitScript(script string) []string {
+	return sqlscript.Split(script, sqlscript.Postgres)
+}
+
+var scanTypeAny = reflect.TypeOf(new(any)).Elem()
+
+// getScanType 用于获取 Scan 类型。
+// lib/pq 无法提供列的可空信息，直接用具体类型接收 NULL 会失败，因此统一使用 any 接收，由驱动决定返回的类型。
+func getScanType(columnType *sql.ColumnType) reflect.Type {
+	return scanTypeAny
+}
+
+// unifyDataType 用于统一数据类型。
+// lib/pq 对没有专门解析逻辑的类型（NUMERIC、UUID、JSONB、数组等）会直接返回文本协议的 []byte，这里将其转为更易用的类型。
+func unifyDataType(columnType *sql.ColumnType, dest *any) {
+	typeName := columnType.DatabaseTypeName()
+
+	// 数组类型的类型名以 _ 开头，如 _INT4 、 _TEXT 。
+	if strings.HasPrefix(typeName, "_") {
+		if v, ok := (*dest).([]byte); ok {
+			*dest = unifyArrayType(typeName[1:], v)
+		}
+		return
+	}
+
+	switch typeName {
+	case "NUMERIC", "MONEY", "UUID", "JSON", "JSONB", "BPCHAR", "CHAR", "VARCHAR", "TEXT", "NAME",
+		"INTERVAL", "INET", "CIDR", "MACADDR", "XML":
+		switch v := (*dest).(type) {
+		case []byte:
+			*dest = string(v)
+		}
+
+	case "TIMESTAMPTZ":
+		// lib/pq 返回的时间带有会话时区的偏移量，统一为 UTC，避免结果受数据库会话时区影响。
+		switch v := (*dest).(type) {
+		case time.Time:
+			*dest = v.UTC()
+		}
+	}
+}
+
+// unifyArrayType 用于将数组类型的文本表示解析为 Go 的切片。
+// 无法解析的情况（如数组中含有 NULL 元素、多维数组等）保留其文本表示。
+func unifyArrayType(elemTypeName string, src []byte) any {
+	switch elemTypeName {
+	case "INT2", "INT4", "INT8":
+		var arr pq.Int64Array
+		if err := arr.Scan(src); err == nil {
+			return []int64(arr)
+		}
+
+	case "FLOAT4", "FLOAT8":
+		var arr pq.Float64Array
+		if err := arr.Scan(src); err == nil {
+			return []float64(arr)
+		}
+
+	case "BOOL":
+		var arr pq.BoolArray
+		if err := arr.Scan(src); err == nil {
+			return []bool(arr)
+		}
+
+	case "BYTEA":
+		var arr pq.ByteaArray
+		if err := arr.Scan(src); err == nil {
+			return [][]byte(arr)
+		}
+
+	default:
+		var arr pq.StringArray
+		if err := arr.Scan(src); err == nil {
+			return []string(arr)
+		}
+	}
+
+	return string(src)
+}
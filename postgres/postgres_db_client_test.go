@@ -0,0 +1,120 @@
+package postgres_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/testenv"
+	"github.com/bunnier/sqlmer/postgres"
+)
+
+func init() {
+	testenv.TryInitConfig("..")
+}
+
+func getPostgresClientOrSkip(t *testing.T) sqlmer.DbClient {
+	t.Helper()
+
+	dbClient, err := testenv.NewPostgresClient()
+	if err != nil {
+		t.Skipf("skip postgres integration smoke test: %v", err)
+	}
+	if _, _, err = dbClient.Scalar("SELECT 1"); err != nil {
+		t.Skipf("skip postgres integration smoke test: %v", err)
+	}
+	return dbClient
+}
+
+func Test_NewPostgresDbClient(t *testing.T) {
+	dbClient := getPostgresClientOrSkip(t)
+
+	if dbClient.Dsn() != testenv.TestConf.Postgres {
+		t.Errorf("postgresDbClient.Dsn() connString = %v, wantConnString %v", dbClient.Dsn(), testenv.TestConf.Postgres)
+	}
+
+	if _, err := postgres.NewPostgresDbClient("postgres://127.0.0.1:1/test?sslmode=disable&connect_timeout=1",
+		sqlmer.WithConnTimeout(time.Second*15),
+		sqlmer.WithExecTimeout(time.Second*15),
+		sqlmer.WithPingCheck(true)); err == nil {
+		t.Errorf("postgresDbClient.NewPostgresDbClient() err = nil, want has a err")
+	}
+}
+
+func Test_PostgresDbClient_scalar_smoke(t *testing.T) {
+	dbClient := getPostgresClientOrSkip(t)
+
+	got, hit, err := dbClient.Scalar("SELECT id FROM go_TypeTest WHERE id=@p1", 1)
+	if err != nil {
+		t.Fatalf("Scalar() error = %v", err)
+	}
+	if !hit {
+		t.Fatal("Scalar() hit = false, want true")
+	}
+	if !reflect.DeepEqual(got, int64(1)) {
+		t.Fatalf("Scalar() = %v, want %v", got, int64(1))
+	}
+}
+
+func Test_PostgresDbClient_get_smoke(t *testing.T) {
+	dbClient := getPostgresClientOrSkip(t)
+
+	got, err := dbClient.Get(`SELECT numericTest, uuidTest, jsonbTest, intArrayTest, textArrayTest, timestamptzTest, nullIntTest
+		FROM go_TypeTest WHERE id=@id`, map[string]any{"id": 1})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	want := map[string]any{
+		"numerictest":     "1.4567899900",
+		"uuidtest":        "00000000-0000-0000-0000-000000000001",
+		"jsonbtest":       `{"a": 1}`,
+		"intarraytest":    []int64{1, 2, 3},
+		"textarraytest":   []string{"a", "b"},
+		"timestamptztest": time.Date(2021, 7, 1, 15, 38, 50, 425000000, time.UTC),
+		"nullinttest":     nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Get() = %#v, want %#v", got, want)
+	}
+}
+
+func Test_PostgresDbClient_slice_get_in_smoke(t *testing.T) {
+	dbClient := getPostgresClientOrSkip(t)
+
+	got, err := dbClient.SliceGet("SELECT id FROM go_TypeTest WHERE id IN (@ids) OR intTest IN (@ids) ORDER BY id",
+		map[string]any{"ids": []int{1, 2}})
+	if err != nil {
+		t.Fatalf("SliceGet() error = %v", err)
+	}
+	if len(got) != 2 || got[0]["id"] != int64(1) || got[1]["id"] != int64(2) {
+		t.Fatalf("SliceGet() = %v", got)
+	}
+}
+
+func Test_PostgresDbClient_execute_smoke(t *testing.T) {
+	dbClient := getPostgresClientOrSkip(t)
+
+	sqlText := `INSERT INTO go_TypeTest(intTest, smallIntTest, bigIntTest, varcharTest, charTest, charTextTest, dateTest, timestampTest, timestamptzTest, realTest, doubleTest, numericTest, boolTest, uuidTest, jsonbTest, intArrayTest, textArrayTest, byteaTest)
+	VALUES (5, 5, 5, '行5', '行5char', '行5text', '2021-07-05', '2021-07-05 15:38:50.425', '2021-07-05 15:38:50.425+00', 5.456, 5.15678, 5.45678999, TRUE, '00000000-0000-0000-0000-000000000005', '{"a": 5}', '{5}', '{"e"}', '\x05')`
+
+	effectedRows, err := dbClient.Execute(sqlText)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if effectedRows != 1 {
+		t.Fatalf("Execute() effectedRows = %v, want %v", effectedRows, int64(1))
+	}
+
+	err = dbClient.SizedExecute(2, sqlText)
+	if !errors.Is(err, sqlmer.ErrExpectedSizeWrong) {
+		t.Fatalf("SizedExecute() error = %v, want ErrExpectedSizeWrong", err)
+	}
+
+	_, err = dbClient.Execute("DELETE FROM go_TypeTest WHERE intTest=@p1", 5)
+	if err != nil {
+		t.Fatalf("cleanup Execute() error = %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/lib/pq"
+)
+
+func Test_bindArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		oriSql    string
+		args      []any
+		wantSql   string
+		wantParam []any
+	}{
+		{
+			"map",
+			"SELECT * FROM go_TypeTest WHERE id=@id",
+			[]any{map[string]any{"id": 1}},
+			"SELECT * FROM go_TypeTest WHERE id=$1",
+			[]any{1},
+		},
+		{
+			"map_repeated",
+			"SELECT * FROM go_TypeTest WHERE id=@id OR intTest=@id OR bigIntTest=@big",
+			[]any{map[string]any{"id": 1, "big": 2}},
+			"SELECT * FROM go_TypeTest WHERE id=$1 OR intTest=$1 OR bigIntTest=$2",
+			[]any{1, 2},
+		},
+		{
+			"index",
+			"SELECT * FROM go_TypeTest WHERE id=@p2 OR id=@p1 OR intTest=@p2",
+			[]any{1, 2},
+			"SELECT * FROM go_TypeTest WHERE id=$1 OR id=$2 OR intTest=$1",
+			[]any{2, 1},
+		},
+		{
+			"in_slice",
+			"SELECT * FROM go_TypeTest WHERE id IN (@ids) AND intTest=@v OR bigIntTest IN (@ids)",
+			[]any{map[string]any{"ids": []int{1, 2, 3}, "v": 4}},
+			"SELECT * FROM go_TypeTest WHERE id IN ($1,$2,$3) AND intTest=$4 OR bigIntTest IN ($1,$2,$3)",
+			[]any{1, 2, 3, 4},
+		},
+		{
+			"in_empty_slice",
+			"SELECT * FROM go_TypeTest WHERE id IN (@p1)",
+			[]any{[]int{}},
+			"SELECT * FROM go_TypeTest WHERE id IN (NULL)",
+			[]any{},
+		},
+		{
+			"bytes_not_expanded",
+			"SELECT * FROM go_TypeTest WHERE byteaTest=@p1",
+			[]any{[]byte{1, 2}},
+			"SELECT * FROM go_TypeTest WHERE byteaTest=$1",
+			[]any{[]byte{1, 2}},
+		},
+		{
+			"string_and_operator",
+			"SELECT '@id', jsonbTest @> @p1::jsonb, @p1 <@ jsonbTest FROM go_TypeTest",
+			[]any{`{"a":1}`},
+			`SELECT '@id', jsonbTest @> $1::jsonb, $1 <@ jsonbTest FROM go_TypeTest`,
+			[]any{`{"a":1}`},
+		},
+		{
+			"escape",
+			"SELECT to_tsvector(charTextTest) @@@@ to_tsquery(@q) FROM go_TypeTest",
+			[]any{map[string]any{"q": "text"}},
+			"SELECT to_tsvector(charTextTest) @@ to_tsquery($1) FROM go_TypeTest",
+			[]any{"text"},
+		},
+		{
+			"comment_and_quote",
+			"SELECT \"@col\", $$@body$$ FROM go_TypeTest -- @x\nWHERE id=@p1 /* @y */",
+			[]any{1},
+			"SELECT \"@col\", $$@body$$ FROM go_TypeTest -- @x\nWHERE id=$1 /* @y */",
+			[]any{1},
+		},
+		{
+			"native_placeholder",
+			"SELECT * FROM go_TypeTest WHERE id=$1",
+			[]any{1},
+			"SELECT * FROM go_TypeTest WHERE id=$1",
+			[]any{1},
+		},
+		{
+			"name_at_end",
+			"SELECT @p1",
+			[]any{1},
+			"SELECT $1",
+			[]any{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixedSql, gotArgs, err := bindArgs(tt.oriSql, tt.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fixedSql != tt.wantSql {
+				t.Errorf("bindArgs() sql = %v, wantSql %v", fixedSql, tt.wantSql)
+			}
+
+			if !reflect.DeepEqual(gotArgs, tt.wantParam) {
+				t.Errorf("bindArgs() args = %v, wantParam %v", gotArgs, tt.wantParam)
+			}
+		})
+	}
+}
+
+func Test_bindArgs_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		oriSql string
+		args   []any
+	}{
+		{"missing_map_param", "SELECT * FROM go_TypeTest WHERE id=@id1 OR id=@id2", []any{map[string]any{"id1": 1}}},
+		{"index_out_of_range", "SELECT * FROM go_TypeTest WHERE id=@p3", []any{1}},
+		{"index_start_at_zero", "SELECT * FROM go_TypeTest WHERE id=@p0", []any{1}},
+		{"index_not_number", "SELECT * FROM go_TypeTest WHERE id=@px", []any{1}},
+		{"index_not_p", "SELECT * FROM go_TypeTest WHERE id=@id", []any{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := bindArgs(tt.oriSql, tt.args...)
+			if !errors.Is(err, sqlmer.ErrParseParamFailed) {
+				t.Errorf("bindArgs() error = %v, want %v", err, sqlmer.ErrParseParamFailed)
+			}
+		})
+	}
+}
+
+func Test_unifyArrayType(t *testing.T) {
+	tests := []struct {
+		name         string
+		elemTypeName string
+		src          string
+		want         any
+	}{
+		{"int", "INT4", "{1,2,3}", []int64{1, 2, 3}},
+		{"float", "FLOAT8", "{1.5,2}", []float64{1.5, 2}},
+		{"bool", "BOOL", "{t,f}", []bool{true, false}},
+		{"text", "TEXT", `{"a b",c}`, []string{"a b", "c"}},
+		{"uuid", "UUID", "{00000000-0000-0000-0000-000000000001}", []string{"00000000-0000-0000-0000-000000000001"}},
+		{"null_element", "INT4", "{1,NULL}", "{1,NULL}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifyArrayType(tt.elemTypeName, []byte(tt.src))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unifyArrayType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization_failure", &pq.Error{Code: "40001"}, true},
+		{"deadlock_detected", &pq.Error{Code: "40P01"}, true},
+		{"wrapped", fmt.Errorf("wrapped: %w", &pq.Error{Code: "40001"}), true},
+		{"unique_violation", &pq.Error{Code: "23505"}, false},
+		{"other", errors.New("other"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Errorf("isRetryableError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ReturningInsertIdDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		sqlText string
+		want    string
+	}{
+		{"append", "INSERT INTO t(a) VALUES ($1);  ", "INSERT INTO t(a) VALUES ($1)\nRETURNING id"},
+		{"returning", "INSERT INTO t(a) VALUES ($1) RETURNING t_id", "INSERT INTO t(a) VALUES ($1) RETURNING t_id"},
+		{"returning_lower", "insert into t(a) values ($1)\nreturning t_id", "insert into t(a) values ($1)\nreturning t_id"},
+		{"returning_in_string", "INSERT INTO t(a) VALUES ('returning') -- returning", "INSERT INTO t(a) VALUES ('returning')\nRETURNING id"},
+		{"trailing_comment", "INSERT INTO t(a) VALUES ($1); -- note\n", "INSERT INTO t(a) VALUES ($1)\nRETURNING id"},
+		{"trailing_block_comment", "INSERT /* a; */ INTO t(a) VALUES ($1) /* b */ ;", "INSERT /* a; */ INTO t(a) VALUES ($1)\nRETURNING id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ReturningInsertIdDialect{"id"}.InsertIdSql(tt.sqlText)
+			if !ok || got != tt.want {
+				t.Errorf("InsertIdSql() = %q %v, want %q true", got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package querycache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+var _ Cache = (*LRUCache)(nil)
+
+// Result 是被缓存的查询结果。
+type Result struct {
+	Rows []map[string]any // Get （至多 1 行）和 SliceGet 的结果。
+	Cell any              // Scalar 的结果。
+	Hit  bool             // Scalar 是否查询到了数据。
+}
+
+// Cache 是查询结果的缓存，实现需要是并发安全的。
+// 可以基于 Redis 等外部存储实现，此时需要自行处理 Result 的序列化。
+type Cache interface {
+	// Get 用于获取 key 对应的结果，不存在或已过期时 ok 为 false 。
+	Get(key string) (result Result, ok bool)
+
+	// Set 用于缓存 key 对应的结果， ttl 后过期（小于等于 0 时不过期）， tags 用于 InvalidateTags 。
+	Set(key string, result Result, ttl time.Duration, tags []string)
+
+	// InvalidateTags 用于移除带有任一给定标签的结果。
+	InvalidateTags(tags ...string)
+
+	// Clear 用于移除所有结果。
+	Clear()
+}
+
+// lruEntry 是 LRUCache 中的一项。
+type lruEntry struct {
+	key       string
+	result    Result
+	expiresAt time.Time // 零值表示不过期。
+	tags      []string
+}
+
+// LRUCache 是基于内存的 LRU 缓存，超过容量时淘汰最久未使用的结果，过期的结果在访问或淘汰时移除。
+type LRUCache struct {
+	maxSize int
+
+	mu      sync.Mutex
+	lru     *list.List                     // 元素为 *lruEntry ，最近使用的在前。
+	entries map[string]*list.Element       // key 到 lru 中元素的映射。
+	tags    map[string]map[string]struct{} // 标签到 key 集合的映射。
+}
+
+// NewLRUCache 用于创建一个最多缓存 maxSize 个结果的 LRUCache ， maxSize 小于等于 0 时使用 DefaultMaxSize 。
+func NewLRUCache(maxSize int) *LRUCache {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &LRUCache{
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+		tags:    make(map[string]map[string]struct{}),
+	}
+}
+
+// Get 用于获取 key 对应的结果，不存在或已过期时 ok 为 false 。
+func (c *LRUCache) Get(key string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return Result{}, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		c.remove(elem)
+		return Result{}, false
+	}
+
+	c.lru.MoveToFront(elem)
+	return entry.result, true
+}
+
+// Set 用于缓存 key 对应的结果，已存在时覆盖。
+func (c *LRUCache) Set(key string, result Result, ttl time.Duration, tags []string) {
+	entry := &lruEntry{key: key, result: result, tags: tags}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for _, tag := range tags {
+		keys, ok := c.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.lru.Len() > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+}
+
+// InvalidateTags 用于移除带有任一给定标签的结果。
+func (c *LRUCache) InvalidateTags(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			c.remove(c.entries[key])
+		}
+	}
+}
+
+// Clear 用于移除所有结果。
+func (c *LRUCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.Init()
+	clear(c.entries)
+	clear(c.tags)
+}
+
+// Len 返回当前缓存的结果数量（可能包含已过期但还未移除的结果）。
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// remove 用于移除一项，调用方需持有锁。
+func (c *LRUCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*lruEntry)
+	delete(c.entries, entry.key)
+	for _, tag := range entry.tags {
+		if keys, ok := c.tags[tag]; ok {
+			delete(keys, entry.key)
+			if len(keys) == 0 {
+				delete(c.tags, tag)
+			}
+		}
+	}
+}
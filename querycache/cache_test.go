@@ -0,0 +1,65 @@
+package querycache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	t.Run("evict", func(t *testing.T) {
+		c := NewLRUCache(2)
+		c.Set("a", Result{Cell: 1}, 0, nil)
+		c.Set("b", Result{Cell: 2}, 0, nil)
+		c.Get("a") // a 变为最近使用。
+		c.Set("c", Result{Cell: 3}, 0, nil)
+
+		if _, ok := c.Get("b"); ok {
+			t.Error("b is not evicted")
+		}
+		if r, ok := c.Get("a"); !ok || r.Cell != 1 {
+			t.Errorf("Get(a) = %v, %v, want 1", r, ok)
+		}
+		if c.Len() != 2 {
+			t.Errorf("Len() = %d, want 2", c.Len())
+		}
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		c := NewLRUCache(10)
+		c.Set("a", Result{Cell: 1}, time.Millisecond, nil)
+		time.Sleep(5 * time.Millisecond)
+		if _, ok := c.Get("a"); ok {
+			t.Error("expired a is returned")
+		}
+		if c.Len() != 0 {
+			t.Errorf("Len() = %d, want the expired entry removed", c.Len())
+		}
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		c := NewLRUCache(10)
+		c.Set("a", Result{}, 0, []string{"t1", "t2"})
+		c.Set("b", Result{}, 0, []string{"t2"})
+		c.Set("c", Result{}, 0, nil)
+
+		// 覆盖后，旧的标签不再生效。
+		c.Set("a", Result{}, 0, []string{"t3"})
+		c.InvalidateTags("t1")
+		if _, ok := c.Get("a"); !ok {
+			t.Error("a is removed by its old tag")
+		}
+
+		c.InvalidateTags("t2", "t3")
+		if c.Len() != 1 {
+			t.Errorf("Len() = %d, want only c left", c.Len())
+		}
+		if len(c.tags) != 0 {
+			t.Errorf("tags = %v, want empty", c.tags)
+		}
+
+		c.Clear()
+		if c.Len() != 0 {
+			t.Errorf("Len() = %d after Clear, want 0", c.Len())
+		}
+	})
+}
@@ -0,0 +1,371 @@
+// Package querycache 提供缓存查询结果的 DbClient ：Get / SliceGet / Scalar 的结果按原始 SQL 和规范化后的参数缓存，
+// 适用于频繁执行、可以容忍短时间过期数据的查询（如报表、看板中的聚合查询）。
+package querycache
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"sync/atomic"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+var _ sqlmer.DbClient = (*CachedDbClient)(nil)
+var _ sqlmer.RetryableErrorClassifier = (*CachedDbClient)(nil)
+var _ sqlmer.ParamLimitProvider = (*CachedDbClient)(nil)
+var _ sqlmer.StmtPreparer = (*CachedDbClient)(nil)
+var _ sqlmer.ScriptExecutor = (*CachedDbClient)(nil)
+
+// DefaultTTL 是查询结果默认的缓存时间。
+const DefaultTTL = 10 * time.Second
+
+// DefaultMaxSize 是默认的 LRUCache 最多缓存的结果数量。
+const DefaultMaxSize = 1000
+
+// CacheStats 是 CachedDbClient 的缓存统计信息。
+type CacheStats struct {
+	Hits   uint64 // 命中缓存的次数。
+	Misses uint64 // 未命中缓存、需要查询数据库的次数（不含跳过缓存的查询）。
+}
+
+// CachedDbClient 是缓存查询结果的 DbClient ：
+//   - Get / SliceGet / Scalar （及其 *Context 版本）的结果按原始 SQL 和规范化后的参数缓存，执行出错时不缓存；
+//   - 其余方法（ Execute 、 Exists 、 Row 、 Rows 等）及事务直接在原始的 DbClient 上执行，不使用缓存；
+//   - 包裹的是事务、 *Context 版本的方法使用 Bypass 标记过的 context ，或 context 中带有 sqlmer.WithTx 设置的事务时，跳过缓存。
+//
+// 数据变更后，可以通过 Invalidate 按标签（见 Tag ）移除相关的缓存。
+type CachedDbClient struct {
+	dbClient sqlmer.DbClient // 原始的 DbClient 实例。
+	inTx     bool            // 原始的 DbClient 是否是事务。
+
+	cache   Cache
+	ttl     time.Duration
+	maxSize int
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Option 是 NewCachedDbClient 的可选配置。
+type Option func(c *CachedDbClient)
+
+// WithCache 用于设置存储查询结果的缓存，默认为容量为 WithMaxSize 的 LRUCache 。
+func WithCache(cache Cache) Option {
+	return func(c *CachedDbClient) {
+		c.cache = cache
+	}
+}
+
+// WithTTL 用于设置查询结果的缓存时间（默认为 DefaultTTL ），小于等于 0 时不过期，只能通过 Invalidate 移除。
+func WithTTL(ttl time.Duration) Option {
+	return func(c *CachedDbClient) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxSize 用于设置默认的 LRUCache 最多缓存的结果数量（默认为 DefaultMaxSize ），通过 WithCache 设置了缓存时无效。
+func WithMaxSize(maxSize int) Option {
+	return func(c *CachedDbClient) {
+		c.maxSize = maxSize
+	}
+}
+
+// NewCachedDbClient 用于创建一个缓存查询结果的 DbClient 。
+// params:
+//
+//	@raw 原始的 DbClient 实例。
+//	@options 可选配置，见 WithCache 、 WithTTL 、 WithMaxSize 。
+func NewCachedDbClient(raw sqlmer.DbClient, options ...Option) *CachedDbClient {
+	_, inTx := raw.(sqlmer.TransactionKeeper)
+	c := &CachedDbClient{
+		dbClient: raw,
+		inTx:     inTx,
+		ttl:      DefaultTTL,
+		maxSize:  DefaultMaxSize,
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.cache == nil {
+		c.cache = NewLRUCache(c.maxSize)
+	}
+
+	return c
+}
+
+// Invalidate 用于移除带有任一给定标签（见 Tag ）的缓存结果。
+func (c *CachedDbClient) Invalidate(tags ...string) {
+	c.cache.InvalidateTags(tags...)
+}
+
+// Purge 用于移除所有缓存结果。
+func (c *CachedDbClient) Purge() {
+	c.cache.Clear()
+}
+
+// CacheStats 返回缓存的统计信息。
+func (c *CachedDbClient) CacheStats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// cached 用于获取缓存的查询结果，未命中时执行 query 并缓存其结果。
+func (c *CachedDbClient) cached(ctx context.Context, method string, sqlText string, args []any, query func() (Result, error)) (Result, error) {
+	if c.inTx || IsBypass(ctx) {
+		return query()
+	}
+	if _, ok := sqlmer.TxFromContext(ctx); ok {
+		return query()
+	}
+
+	key, ok := cacheKey(method, sqlText, args)
+	if !ok {
+		return query()
+	}
+
+	if result, ok := c.cache.Get(key); ok {
+		c.hits.Add(1)
+		return cloneResult(result), nil
+	}
+
+	c.misses.Add(1)
+	result, err := query()
+	if err != nil {
+		return result, err
+	}
+
+	// 缓存的是副本，调用方修改返回的结果不会影响缓存。
+	c.cache.Set(key, cloneResult(result), c.ttl, TagsFromContext(ctx))
+	return result, nil
+}
+
+// cloneResult 用于复制查询结果，包括其中的 map 和 []byte 。
+func cloneResult(result Result) Result {
+	if result.Rows != nil {
+		rows := make([]map[string]any, len(result.Rows))
+		for i, row := range result.Rows {
+			rows[i] = maps.Clone(row)
+			for k, v := range rows[i] {
+				if b, ok := v.([]byte); ok {
+					rows[i][k] = bytes.Clone(b)
+				}
+			}
+		}
+		result.Rows = rows
+	}
+	if b, ok := result.Cell.([]byte); ok {
+		result.Cell = bytes.Clone(b)
+	}
+	return result
+}
+
+// getResult 用于将 Get 的结果转换为 Result 。
+func getResult(mapRow map[string]any, err error) (Result, error) {
+	if mapRow == nil {
+		return Result{}, err
+	}
+	return Result{Rows: []map[string]any{mapRow}}, err
+}
+
+// firstRow 用于从 Result 中获取 Get 的结果。
+func firstRow(result Result) map[string]any {
+	if len(result.Rows) == 0 {
+		return nil
+	}
+	return result.Rows[0]
+}
+
+// Dsn 用于获取当前实例所使用的数据库连接字符串。
+func (c *CachedDbClient) Dsn() string {
+	return c.dbClient.Dsn()
+}
+
+// GetConnTimeout 用于获取当前 DbClient 实例的获取连接的超时时间。
+func (c *CachedDbClient) GetConnTimeout() time.Duration {
+	return c.dbClient.GetConnTimeout()
+}
+
+// GetExecTimeout 用于获取当前 DbClient 实例的执行超时时间。
+func (c *CachedDbClient) GetExecTimeout() time.Duration {
+	return c.dbClient.GetExecTimeout()
+}
+
+// PingContext 用于检查数据库连接是否可用，必要时会建立连接。
+func (c *CachedDbClient) PingContext(ctx context.Context) error {
+	return c.dbClient.PingContext(ctx)
+}
+
+// Stats 用于获取连接池的统计信息。
+func (c *CachedDbClient) Stats() sql.DBStats {
+	return c.dbClient.Stats()
+}
+
+// Close 用于关闭原始的 DbClient ，缓存的结果不会被移除。
+func (c *CachedDbClient) Close() error {
+	return c.dbClient.Close()
+}
+
+// IsRetryableError 用于判断给定的错误是否可以通过重试事务解决，判断逻辑由原始的 DbClient 提供。
+func (c *CachedDbClient) IsRetryableError(err error) bool {
+	classifier, ok := c.dbClient.(sqlmer.RetryableErrorClassifier)
+	return ok && classifier.IsRetryableError(err)
+}
+
+// MaxParamCount 返回单条语句允许使用的最大参数个数，由原始的 DbClient 提供，返回 0 表示未知。
+func (c *CachedDbClient) MaxParamCount() int {
+	if provider, ok := c.dbClient.(sqlmer.ParamLimitProvider); ok {
+		return provider.MaxParamCount()
+	}
+	return 0
+}
+
+// MaxInsertRows 返回单条 INSERT ... VALUES 语句允许插入的最大行数，由原始的 DbClient 提供，返回 0 表示不限制。
+func (c *CachedDbClient) MaxInsertRows() int {
+	if provider, ok := c.dbClient.(sqlmer.ParamLimitProvider); ok {
+		return provider.MaxInsertRows()
+	}
+	return 0
+}
+
+// Prepare 用于创建一个支持命名参数的预编译语句，预编译语句上的查询不使用缓存。
+func (c *CachedDbClient) Prepare(ctx context.Context, sqlText string) (*sqlmer.Stmt, error) {
+	if preparer, ok := c.dbClient.(sqlmer.StmtPreparer); ok {
+		return preparer.Prepare(ctx, sqlText)
+	}
+	return nil, fmt.Errorf("querycache: %T does not support prepared statements", c.dbClient)
+}
+
+// ExecuteScript 用于执行包含多条语句的脚本，脚本中的查询不使用缓存。
+func (c *CachedDbClient) ExecuteScript(ctx context.Context, script string, options ...sqlmer.ScriptOption) ([]sqlmer.ScriptResult, error) {
+	if executor, ok := c.dbClient.(sqlmer.ScriptExecutor); ok {
+		return executor.ExecuteScript(ctx, script, options...)
+	}
+	return nil, fmt.Errorf("querycache: %T does not support executing scripts", c.dbClient)
+}
+
+// CreateTransaction 用于开始一个事务，事务内的所有语句都不使用缓存。
+func (c *CachedDbClient) CreateTransaction() (sqlmer.TransactionKeeper, error) {
+	return c.dbClient.CreateTransaction()
+}
+
+// CreateTransactionContext 用于开始一个事务，事务内的所有语句都不使用缓存。
+func (c *CachedDbClient) CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (sqlmer.TransactionKeeper, error) {
+	return c.dbClient.CreateTransactionContext(ctx, opts)
+}
+
+// Execute 用于执行非查询SQL语句，并返回所影响的行数。
+func (c *CachedDbClient) Execute(sqlText string, args ...any) (int64, error) {
+	return c.dbClient.Execute(sqlText, args...)
+}
+
+// ExecuteContext 用于执行非查询SQL语句，并返回所影响的行数。
+func (c *CachedDbClient) ExecuteContext(ctx context.Context, sqlText string, args ...any) (int64, error) {
+	return c.dbClient.ExecuteContext(ctx, sqlText, args...)
+}
+
+// Insert 用于执行插入语句，并返回自增 id 。
+func (c *CachedDbClient) Insert(sqlText string, args ...any) (int64, error) {
+	return c.dbClient.Insert(sqlText, args...)
+}
+
+// InsertContext 用于执行插入语句，并返回自增 id 。
+func (c *CachedDbClient) InsertContext(ctx context.Context, sqlText string, args ...any) (int64, error) {
+	return c.dbClient.InsertContext(ctx, sqlText, args...)
+}
+
+// SizedExecute 用于执行非查询SQL语句，并断言所影响的行数。
+func (c *CachedDbClient) SizedExecute(expectedSize int64, sqlText string, args ...any) error {
+	return c.dbClient.SizedExecute(expectedSize, sqlText, args...)
+}
+
+// SizedExecuteContext 用于执行非查询SQL语句，并断言所影响的行数。
+func (c *CachedDbClient) SizedExecuteContext(ctx context.Context, expectedSize int64, sqlText string, args ...any) error {
+	return c.dbClient.SizedExecuteContext(ctx, expectedSize, sqlText, args...)
+}
+
+// Exists 用于判断给定的查询的结果是否至少包含 1 行，不使用缓存。
+func (c *CachedDbClient) Exists(sqlText string, args ...any) (bool, error) {
+	return c.dbClient.Exists(sqlText, args...)
+}
+
+// ExistsContext 用于判断给定的查询的结果是否至少包含 1 行，不使用缓存。
+func (c *CachedDbClient) ExistsContext(ctx context.Context, sqlText string, args ...any) (bool, error) {
+	return c.dbClient.ExistsContext(ctx, sqlText, args...)
+}
+
+// Scalar 用于获取查询的第一行第一列的值，结果会被缓存。
+func (c *CachedDbClient) Scalar(sqlText string, args ...any) (any, bool, error) {
+	result, err := c.cached(context.Background(), "Scalar", sqlText, args, func() (Result, error) {
+		cell, hit, err := c.dbClient.Scalar(sqlText, args...)
+		return Result{Cell: cell, Hit: hit}, err
+	})
+	return result.Cell, result.Hit, err
+}
+
+// ScalarContext 用于获取查询的第一行第一列的值，结果会被缓存，带有 Tag 设置的标签。
+func (c *CachedDbClient) ScalarContext(ctx context.Context, sqlText string, args ...any) (any, bool, error) {
+	result, err := c.cached(ctx, "Scalar", sqlText, args, func() (Result, error) {
+		cell, hit, err := c.dbClient.ScalarContext(ctx, sqlText, args...)
+		return Result{Cell: cell, Hit: hit}, err
+	})
+	return result.Cell, result.Hit, err
+}
+
+// Get 用于获取查询结果的第一行记录，结果（包括没有数据的结果）会被缓存。
+func (c *CachedDbClient) Get(sqlText string, args ...any) (map[string]any, error) {
+	result, err := c.cached(context.Background(), "Get", sqlText, args, func() (Result, error) {
+		return getResult(c.dbClient.Get(sqlText, args...))
+	})
+	return firstRow(result), err
+}
+
+// GetContext 用于获取查询结果的第一行记录，结果（包括没有数据的结果）会被缓存，带有 Tag 设置的标签。
+func (c *CachedDbClient) GetContext(ctx context.Context, sqlText string, args ...any) (map[string]any, error) {
+	result, err := c.cached(ctx, "Get", sqlText, args, func() (Result, error) {
+		return getResult(c.dbClient.GetContext(ctx, sqlText, args...))
+	})
+	return firstRow(result), err
+}
+
+// SliceGet 用于获取查询结果的所有行，结果会被缓存。
+func (c *CachedDbClient) SliceGet(sqlText string, args ...any) ([]map[string]any, error) {
+	result, err := c.cached(context.Background(), "SliceGet", sqlText, args, func() (Result, error) {
+		rows, err := c.dbClient.SliceGet(sqlText, args...)
+		return Result{Rows: rows}, err
+	})
+	return result.Rows, err
+}
+
+// SliceGetContext 用于获取查询结果的所有行，结果会被缓存，带有 Tag 设置的标签。
+func (c *CachedDbClient) SliceGetContext(ctx context.Context, sqlText string, args ...any) ([]map[string]any, error) {
+	result, err := c.cached(ctx, "SliceGet", sqlText, args, func() (Result, error) {
+		rows, err := c.dbClient.SliceGetContext(ctx, sqlText, args...)
+		return Result{Rows: rows}, err
+	})
+	return result.Rows, err
+}
+
+// Row 用于获取单个查询结果行，不使用缓存。
+func (c *CachedDbClient) Row(sqlText string, args ...any) (*sqlen.EnhanceRow, error) {
+	return c.dbClient.Row(sqlText, args...)
+}
+
+// RowContext 用于获取单个查询结果行，不使用缓存。
+func (c *CachedDbClient) RowContext(ctx context.Context, sqlText string, args ...any) (*sqlen.EnhanceRow, error) {
+	return c.dbClient.RowContext(ctx, sqlText, args...)
+}
+
+// Rows 用于获取查询结果行的游标对象，不使用缓存。
+func (c *CachedDbClient) Rows(sqlText string, args ...any) (*sqlen.EnhanceRows, error) {
+	return c.dbClient.Rows(sqlText, args...)
+}
+
+// RowsContext 用于获取查询结果行的游标对象，不使用缓存。
+func (c *CachedDbClient) RowsContext(ctx context.Context, sqlText string, args ...any) (*sqlen.EnhanceRows, error) {
+	return c.dbClient.RowsContext(ctx, sqlText, args...)
+}
@@ -0,0 +1,242 @@
+package querycache
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/sqlite"
+)
+
+func newCachedClient(t *testing.T, options ...Option) *CachedDbClient {
+	t.Helper()
+
+	raw, err := sqlite.NewSqliteDbClient(filepath.Join(t.TempDir(), "cache.db"), sqlmer.WithContextTx(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := raw.Execute("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := raw.Execute("INSERT INTO users (name) VALUES ('a'), ('b'), ('c')"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCachedDbClient(raw, options...)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// rename 用于在缓存之外修改数据，以判断查询结果是否来自缓存。
+func rename(t *testing.T, c *CachedDbClient, id int, name string) {
+	t.Helper()
+	if _, err := c.Execute("UPDATE users SET name = @p1 WHERE id = @p2", name, id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCachedDbClient_Cache(t *testing.T) {
+	c := newCachedClient(t)
+
+	t.Run("get", func(t *testing.T) {
+		row, err := c.Get("SELECT name FROM users WHERE id = @id", map[string]any{"id": 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		row["name"] = "modified" // 修改返回的结果不影响缓存。
+		rename(t, c, 1, "x")
+
+		row, err = c.GetContext(context.Background(), "SELECT name FROM users WHERE id = @id", map[string]any{"id": 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row["name"] != "a" {
+			t.Errorf("GetContext() = %v, want the cached row", row)
+		}
+
+		// 参数不同，不命中缓存。
+		row, err = c.Get("SELECT name FROM users WHERE id = @id", map[string]any{"id": 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row["name"] != "b" {
+			t.Errorf("Get() = %v, want b", row)
+		}
+	})
+
+	t.Run("no_rows", func(t *testing.T) {
+		row, err := c.Get("SELECT name FROM users WHERE id = 100")
+		if err != nil || row != nil {
+			t.Fatalf("Get() = %v, %v, want nil", row, err)
+		}
+		if _, err := c.Execute("INSERT INTO users (id, name) VALUES (100, 'new')"); err != nil {
+			t.Fatal(err)
+		}
+		if row, err = c.Get("SELECT name FROM users WHERE id = 100"); err != nil || row != nil {
+			t.Errorf("Get() = %v, %v, want the cached empty result", row, err)
+		}
+	})
+
+	t.Run("slice_get_and_scalar", func(t *testing.T) {
+		rows, err := c.SliceGet("SELECT id, name FROM users WHERE id IN (@p1) ORDER BY id", []int{2, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		count, _, err := c.Scalar("SELECT COUNT(1) FROM users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rename(t, c, 2, "y")
+		if _, err := c.Execute("DELETE FROM users WHERE id = 3"); err != nil {
+			t.Fatal(err)
+		}
+
+		cachedRows, err := c.SliceGetContext(context.Background(), "SELECT id, name FROM users WHERE id IN (@p1) ORDER BY id", []int{2, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cachedRows) != 2 || cachedRows[0]["name"] != rows[0]["name"] {
+			t.Errorf("SliceGetContext() = %v, want the cached rows %v", cachedRows, rows)
+		}
+		cachedCount, hit, err := c.ScalarContext(context.Background(), "SELECT COUNT(1) FROM users")
+		if err != nil || !hit || cachedCount != count {
+			t.Errorf("ScalarContext() = %v, %v, %v, want the cached count %v", cachedCount, hit, err, count)
+		}
+	})
+
+	t.Run("error_not_cached", func(t *testing.T) {
+		stats := c.CacheStats()
+		for i := 0; i < 2; i++ {
+			if _, err := c.SliceGet("SELECT * FROM not_exists"); err == nil {
+				t.Fatal("SliceGet() error = nil")
+			}
+		}
+		if got := c.CacheStats(); got.Hits != stats.Hits || got.Misses != stats.Misses+2 {
+			t.Errorf("CacheStats() = %+v, want 2 more misses than %+v", got, stats)
+		}
+	})
+}
+
+func TestCachedDbClient_Bypass(t *testing.T) {
+	c := newCachedClient(t)
+	query := "SELECT name FROM users WHERE id = 1"
+	scalar := func(ctx context.Context, dbClient sqlmer.DbClient) any {
+		t.Helper()
+		name, _, err := dbClient.ScalarContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+
+	scalar(context.Background(), c)
+	rename(t, c, 1, "x")
+
+	if got := scalar(Bypass(context.Background()), c); got != "x" {
+		t.Errorf("with Bypass got %v, want x", got)
+	}
+	if got := scalar(context.Background(), c); got != "a" {
+		t.Errorf("got %v, want the cached a", got)
+	}
+
+	tx, err := c.CreateTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Close()
+	if got := scalar(context.Background(), tx); got != "x" {
+		t.Errorf("in transaction got %v, want x", got)
+	}
+	if got := scalar(sqlmer.WithTx(context.Background(), tx), c); got != "x" {
+		t.Errorf("with context transaction got %v, want x", got)
+	}
+	if got := scalar(context.Background(), NewCachedDbClient(tx)); got != "x" {
+		t.Errorf("wrapping a transaction got %v, want x", got)
+	}
+}
+
+func TestCachedDbClient_TTLAndInvalidate(t *testing.T) {
+	c := newCachedClient(t, WithTTL(50*time.Millisecond))
+
+	t.Run("ttl", func(t *testing.T) {
+		c.Get("SELECT name FROM users WHERE id = 1")
+		rename(t, c, 1, "x")
+		time.Sleep(60 * time.Millisecond)
+		row, err := c.Get("SELECT name FROM users WHERE id = 1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row["name"] != "x" {
+			t.Errorf("Get() = %v, want the expired result refreshed", row)
+		}
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		c := newCachedClient(t, WithTTL(0))
+		ctx := Tag(Tag(context.Background(), "users"), "user:2")
+		query := "SELECT name FROM users WHERE id = 2"
+		c.GetContext(ctx, query)
+		c.Get("SELECT name FROM users WHERE id = 3")
+		rename(t, c, 2, "y")
+		rename(t, c, 3, "z")
+
+		c.Invalidate("orders")
+		if row, _ := c.GetContext(ctx, query); row["name"] != "b" {
+			t.Errorf("GetContext() = %v, want the cached b", row)
+		}
+
+		c.Invalidate("user:2")
+		if row, _ := c.GetContext(ctx, query); row["name"] != "y" {
+			t.Errorf("GetContext() = %v, want y after invalidation", row)
+		}
+		if row, _ := c.Get("SELECT name FROM users WHERE id = 3"); row["name"] != "c" {
+			t.Errorf("Get() = %v, want the untagged result still cached", row)
+		}
+
+		c.Purge()
+		if row, _ := c.Get("SELECT name FROM users WHERE id = 3"); row["name"] != "z" {
+			t.Errorf("Get() = %v, want z after purge", row)
+		}
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	type filter struct {
+		Name  *string
+		Since time.Time
+	}
+	name1, name2 := "a", "a"
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	key := func(args ...any) string {
+		t.Helper()
+		k, ok := cacheKey("Get", "SELECT 1", args)
+		if !ok {
+			t.Fatalf("cacheKey(%v) is not cacheable", args)
+		}
+		return k
+	}
+
+	// 指针指向的值相同时， key 相同。
+	if key(filter{Name: &name1, Since: since}) != key(&filter{Name: &name2, Since: since}) {
+		t.Error("struct args with equal values got different keys")
+	}
+	if key(map[string]any{"a": 1, "b": "2"}) != key(map[string]any{"b": "2", "a": 1}) {
+		t.Error("map args got different keys")
+	}
+	if key(sql.NullString{String: "a", Valid: true}) != key("a") {
+		t.Error("driver.Valuer arg got a different key from its value")
+	}
+	if key(1) == key(2) || key([]int{1, 2}) == key([]int{2, 1}) {
+		t.Error("different args got the same key")
+	}
+	if k, _ := cacheKey("Scalar", "SELECT 1", []any{1}); k == key(1) {
+		t.Error("different methods got the same key")
+	}
+	if _, ok := cacheKey("Get", "SELECT 1", []any{func() {}}); ok {
+		t.Error("func arg is cacheable")
+	}
+}
@@ -0,0 +1,34 @@
+package querycache
+
+import "context"
+
+// tagsKey 是 context 中缓存标签的 key 。
+type tagsKey struct{}
+
+// bypassKey 是 context 中跳过缓存标记的 key 。
+type bypassKey struct{}
+
+// Tag 返回一个带有缓存标签的 context ，CachedDbClient 的查询方法使用该 context 时，缓存的结果带有这些标签，
+// 之后可以通过 CachedDbClient.Invalidate 按标签移除。多次调用时，标签会累加。
+func Tag(ctx context.Context, tags ...string) context.Context {
+	merged := append(append([]string(nil), TagsFromContext(ctx)...), tags...)
+	return context.WithValue(ctx, tagsKey{}, merged)
+}
+
+// TagsFromContext 用于获取 ctx 中由 Tag 设置的缓存标签。
+func TagsFromContext(ctx context.Context) []string {
+	tags, _ := ctx.Value(tagsKey{}).([]string)
+	return tags
+}
+
+// Bypass 返回一个带有跳过缓存标记的 context ，CachedDbClient 的查询方法使用该 context 时，
+// 直接查询数据库，且不缓存查询结果，用于不能容忍过期数据的场景。
+func Bypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// IsBypass 用于判断 ctx 是否带有 Bypass 设置的跳过缓存标记。
+func IsBypass(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassKey{}).(bool)
+	return bypass
+}
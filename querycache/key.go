@@ -0,0 +1,103 @@
+package querycache
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// maxArgDepth 是规范化参数时允许的最大嵌套深度，超过时（如存在循环引用）不缓存。
+const maxArgDepth = 32
+
+// cacheKey 用于根据查询方法、原始 SQL 和规范化后的参数生成缓存的 key ，
+// 参数无法规范化（如包含函数、 channel 或 driver.Valuer 返回错误）时 ok 为 false ，此时不缓存。
+func cacheKey(method string, sqlText string, args []any) (key string, ok bool) {
+	normalized, ok := normalizeArg(args, 0)
+	if !ok {
+		return "", false
+	}
+
+	// fmt 输出 map 时按 key 排序，相同的参数总是得到相同的结果。
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%#v", method, sqlText, normalized))
+	return hex.EncodeToString(sum[:]), true
+}
+
+// normalizeArg 用于将参数转换为只包含值的结构：解引用指针、取 driver.Valuer 的值，
+// 结构体转换为导出字段的 map ，以免 key 中出现指针地址。
+func normalizeArg(arg any, depth int) (any, bool) {
+	if depth > maxArgDepth {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(arg)
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, true
+	}
+
+	switch v := arg.(type) {
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return nil, false
+		}
+		return normalizeArg(value, depth+1)
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	case []byte:
+		return v, true
+	case sql.NamedArg:
+		value, ok := normalizeArg(v.Value, depth+1)
+		return sql.NamedArg{Name: v.Name, Value: value}, ok
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return normalizeArg(rv.Elem().Interface(), depth+1)
+
+	case reflect.Map:
+		m := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			value, ok := normalizeArg(iter.Value().Interface(), depth+1)
+			if !ok {
+				return nil, false
+			}
+			m[fmt.Sprint(iter.Key().Interface())] = value
+		}
+		return m, true
+
+	case reflect.Slice, reflect.Array:
+		s := make([]any, rv.Len())
+		for i := range s {
+			value, ok := normalizeArg(rv.Index(i).Interface(), depth+1)
+			if !ok {
+				return nil, false
+			}
+			s[i] = value
+		}
+		return s, true
+
+	case reflect.Struct:
+		m := make(map[string]any, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			value, ok := normalizeArg(rv.Field(i).Interface(), depth+1)
+			if !ok {
+				return nil, false
+			}
+			m[field.Name] = value
+		}
+		return m, true
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil, false
+	}
+
+	return arg, true
+}
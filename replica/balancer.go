@@ -0,0 +1,45 @@
+package replica
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+
+	"github.com/bunnier/sqlmer"
+)
+
+// Balancer 用于从可用的从库中选出执行查询的实例，需要能够被并发调用。
+type Balancer interface {
+	// Pick 从 replicas 中选出一个从库， replicas 中只包含健康的从库，且至少有一个元素。
+	Pick(replicas []sqlmer.DbClient) sqlmer.DbClient
+}
+
+// BalancerFunc 是函数形式的 Balancer 。
+type BalancerFunc func(replicas []sqlmer.DbClient) sqlmer.DbClient
+
+// Pick 从 replicas 中选出一个从库。
+func (f BalancerFunc) Pick(replicas []sqlmer.DbClient) sqlmer.DbClient {
+	return f(replicas)
+}
+
+var _ Balancer = (*RoundRobinBalancer)(nil)
+
+// RoundRobinBalancer 依次轮流选择从库，是 ReplicatedDbClient 默认的 Balancer 。
+type RoundRobinBalancer struct {
+	next atomic.Uint64
+}
+
+// NewRoundRobinBalancer 用于创建一个 RoundRobinBalancer 。
+func NewRoundRobinBalancer() *RoundRobinBalancer {
+	return &RoundRobinBalancer{}
+}
+
+// Pick 从 replicas 中依次轮流选出一个从库。
+func (b *RoundRobinBalancer) Pick(replicas []sqlmer.DbClient) sqlmer.DbClient {
+	n := b.next.Add(1) - 1
+	return replicas[n%uint64(len(replicas))]
+}
+
+// RandomBalancer 随机选择从库。
+var RandomBalancer Balancer = BalancerFunc(func(replicas []sqlmer.DbClient) sqlmer.DbClient {
+	return replicas[rand.IntN(len(replicas))]
+})
@@ -0,0 +1,18 @@
+package replica
+
+import "context"
+
+// forcePrimaryKey 是 context 中强制使用主库标记的 key 。
+type forcePrimaryKey struct{}
+
+// ForcePrimary 返回一个带有强制使用主库标记的 context ，ReplicatedDbClient 的查询方法使用该 context 时会在主库上执行，
+// 用于刚写入数据后需要立即读到（ read-your-writes ）、不能容忍主从延迟的场景。
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryKey{}, true)
+}
+
+// IsForcePrimary 用于判断 ctx 是否带有 ForcePrimary 设置的强制使用主库标记。
+func IsForcePrimary(ctx context.Context) bool {
+	force, _ := ctx.Value(forcePrimaryKey{}).(bool)
+	return force
+}
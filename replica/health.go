@@ -0,0 +1,78 @@
+package replica
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+)
+
+// HealthCheckFunc 用于检查一个从库是否可用，返回 nil 表示可用。
+type HealthCheckFunc func(ctx context.Context, replica sqlmer.DbClient) error
+
+// PingHealthCheck 通过 DbClient.PingContext 检查从库是否可用，是默认的 HealthCheckFunc 。
+func PingHealthCheck(ctx context.Context, replica sqlmer.DbClient) error {
+	return replica.PingContext(ctx)
+}
+
+// replicaNode 是一个从库及其健康状态。
+type replicaNode struct {
+	client    sqlmer.DbClient
+	unhealthy atomic.Bool // 最近一次健康检查失败时为 true 。
+}
+
+// healthChecker 用于定时检查从库的健康状态。
+type healthChecker struct {
+	nodes    []*replicaNode
+	check    HealthCheckFunc
+	interval time.Duration
+	timeout  time.Duration // 单次检查的超时时间。
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     sync.WaitGroup
+}
+
+// start 用于在后台开始定时检查。
+func (h *healthChecker) start() {
+	h.stop = make(chan struct{})
+	h.done.Add(1)
+	go func() {
+		defer h.done.Done()
+
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-h.stop:
+				return
+			case <-ticker.C:
+				h.checkAll()
+			}
+		}
+	}()
+}
+
+// close 用于停止定时检查，并等待进行中的检查结束。重复调用是安全的。
+func (h *healthChecker) close() {
+	h.stopOnce.Do(func() { close(h.stop) })
+	h.done.Wait()
+}
+
+// checkAll 用于并发检查所有从库，并更新其健康状态。
+func (h *healthChecker) checkAll() {
+	var wg sync.WaitGroup
+	for _, node := range h.nodes {
+		wg.Add(1)
+		go func(node *replicaNode) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+			defer cancel()
+			node.unhealthy.Store(h.check(ctx, node.client) != nil)
+		}(node)
+	}
+	wg.Wait()
+}
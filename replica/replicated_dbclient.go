@@ -0,0 +1,375 @@
+// Package replica 提供读写分离的 DbClient 实现：写操作和事务在主库上执行，查询按负载均衡策略路由到从库。
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+var _ sqlmer.DbClient = (*ReplicatedDbClient)(nil)
+var _ sqlmer.RetryableErrorClassifier = (*ReplicatedDbClient)(nil)
+var _ sqlmer.ParamLimitProvider = (*ReplicatedDbClient)(nil)
+var _ sqlmer.StmtPreparer = (*ReplicatedDbClient)(nil)
+var _ sqlmer.ScriptExecutor = (*ReplicatedDbClient)(nil)
+
+// ErrNoReplicaAvailable 当没有可用的从库，且关闭了 WithFallbackToPrimary 时，查询方法返回该类型错误。
+var ErrNoReplicaAvailable = errors.New("replica: no healthy replica available")
+
+// DefaultHealthCheckInterval 是默认的从库健康检查间隔。
+const DefaultHealthCheckInterval = 5 * time.Second
+
+// ReplicatedDbClient 是读写分离的 DbClient ：
+//   - Execute / SizedExecute / Insert 及事务（事务内的所有语句）在主库上执行；
+//   - Exists / Scalar / Get / SliceGet / Row / Rows 在 Balancer 选出的从库上执行，
+//     *Context 版本的方法使用 ForcePrimary 标记过的 context 时，在主库上执行；
+//   - 从库由后台定时做健康检查，不可用的从库不会被选中，没有可用的从库时，默认回退到主库上执行。
+type ReplicatedDbClient struct {
+	primary  sqlmer.DbClient
+	replicas []*replicaNode
+
+	balancer          Balancer
+	fallbackToPrimary bool
+	healthChecker     *healthChecker // 未开启健康检查时为 nil 。
+}
+
+// Option 是 NewReplicatedDbClient 的可选配置。
+type Option func(c *ReplicatedDbClient)
+
+// WithBalancer 用于设置选择从库的策略，默认为 RoundRobinBalancer 。
+func WithBalancer(balancer Balancer) Option {
+	return func(c *ReplicatedDbClient) {
+		c.balancer = balancer
+	}
+}
+
+// WithHealthCheck 用于设置从库健康检查的间隔和方式（默认每 DefaultHealthCheckInterval 通过 PingHealthCheck 检查一次），
+// 单次检查的超时时间与间隔相同。 interval 小于等于 0 时关闭健康检查，所有从库始终视为可用； check 为 nil 时使用 PingHealthCheck 。
+func WithHealthCheck(interval time.Duration, check HealthCheckFunc) Option {
+	return func(c *ReplicatedDbClient) {
+		if interval <= 0 {
+			c.healthChecker = nil
+			return
+		}
+		if check == nil {
+			check = PingHealthCheck
+		}
+		c.healthChecker = &healthChecker{check: check, interval: interval, timeout: interval}
+	}
+}
+
+// WithFallbackToPrimary 用于设置没有可用的从库时，查询是否回退到主库上执行（默认为 true ）。
+// 设置为 false 时，查询方法返回 ErrNoReplicaAvailable 。
+func WithFallbackToPrimary(enabled bool) Option {
+	return func(c *ReplicatedDbClient) {
+		c.fallbackToPrimary = enabled
+	}
+}
+
+// NewReplicatedDbClient 用于创建一个读写分离的 DbClient 。
+// params:
+//
+//	@primary 主库，不能为 nil 。
+//	@replicas 从库，可以为空，此时查询按 WithFallbackToPrimary 的设置处理。
+//	@options 可选配置，见 WithBalancer 、 WithHealthCheck 、 WithFallbackToPrimary 。
+//
+// 使用完毕后需要调用 Close ，以停止健康检查并关闭主库和所有从库。
+func NewReplicatedDbClient(primary sqlmer.DbClient, replicas []sqlmer.DbClient, options ...Option) *ReplicatedDbClient {
+	c := &ReplicatedDbClient{
+		primary:           primary,
+		replicas:          make([]*replicaNode, 0, len(replicas)),
+		balancer:          NewRoundRobinBalancer(),
+		fallbackToPrimary: true,
+		healthChecker:     &healthChecker{check: PingHealthCheck, interval: DefaultHealthCheckInterval, timeout: DefaultHealthCheckInterval},
+	}
+	for _, replica := range replicas {
+		c.replicas = append(c.replicas, &replicaNode{client: replica})
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.healthChecker != nil && len(c.replicas) > 0 {
+		c.healthChecker.nodes = c.replicas
+		c.healthChecker.start()
+	} else {
+		c.healthChecker = nil
+	}
+
+	return c
+}
+
+// Primary 用于获取主库。
+func (c *ReplicatedDbClient) Primary() sqlmer.DbClient {
+	return c.primary
+}
+
+// reader 用于获取执行查询的实例。
+func (c *ReplicatedDbClient) reader(ctx context.Context) (sqlmer.DbClient, error) {
+	if IsForcePrimary(ctx) {
+		return c.primary, nil
+	}
+
+	healthy := make([]sqlmer.DbClient, 0, len(c.replicas))
+	for _, node := range c.replicas {
+		if !node.unhealthy.Load() {
+			healthy = append(healthy, node.client)
+		}
+	}
+
+	if len(healthy) == 0 {
+		if c.fallbackToPrimary {
+			return c.primary, nil
+		}
+		return nil, ErrNoReplicaAvailable
+	}
+
+	return c.balancer.Pick(healthy), nil
+}
+
+// Dsn 用于获取主库的数据库连接字符串。
+func (c *ReplicatedDbClient) Dsn() string {
+	return c.primary.Dsn()
+}
+
+// GetConnTimeout 用于获取主库的获取连接的超时时间。
+func (c *ReplicatedDbClient) GetConnTimeout() time.Duration {
+	return c.primary.GetConnTimeout()
+}
+
+// GetExecTimeout 用于获取主库的执行超时时间。
+func (c *ReplicatedDbClient) GetExecTimeout() time.Duration {
+	return c.primary.GetExecTimeout()
+}
+
+// PingContext 用于检查主库连接是否可用，从库的可用性由健康检查负责。
+func (c *ReplicatedDbClient) PingContext(ctx context.Context) error {
+	return c.primary.PingContext(ctx)
+}
+
+// Stats 用于获取主库连接池的统计信息。
+func (c *ReplicatedDbClient) Stats() sql.DBStats {
+	return c.primary.Stats()
+}
+
+// Close 用于停止健康检查，并关闭所有从库和主库。重复调用是安全的。
+func (c *ReplicatedDbClient) Close() error {
+	if c.healthChecker != nil {
+		c.healthChecker.close()
+	}
+
+	errs := make([]error, 0, len(c.replicas)+1)
+	for i, node := range c.replicas {
+		if err := node.client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("replica: close replica %d: %w", i, err))
+		}
+	}
+	if err := c.primary.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("replica: close primary: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// IsRetryableError 用于判断给定的错误是否可以通过重试事务解决，判断逻辑由主库提供。
+func (c *ReplicatedDbClient) IsRetryableError(err error) bool {
+	classifier, ok := c.primary.(sqlmer.RetryableErrorClassifier)
+	return ok && classifier.IsRetryableError(err)
+}
+
+// MaxParamCount 返回单条语句允许使用的最大参数个数，由主库提供，返回 0 表示未知。
+func (c *ReplicatedDbClient) MaxParamCount() int {
+	if provider, ok := c.primary.(sqlmer.ParamLimitProvider); ok {
+		return provider.MaxParamCount()
+	}
+	return 0
+}
+
+// MaxInsertRows 返回单条 INSERT ... VALUES 语句允许插入的最大行数，由主库提供，返回 0 表示不限制。
+func (c *ReplicatedDbClient) MaxInsertRows() int {
+	if provider, ok := c.primary.(sqlmer.ParamLimitProvider); ok {
+		return provider.MaxInsertRows()
+	}
+	return 0
+}
+
+// Prepare 用于在主库上创建一个支持命名参数的预编译语句。
+func (c *ReplicatedDbClient) Prepare(ctx context.Context, sqlText string) (*sqlmer.Stmt, error) {
+	if preparer, ok := c.primary.(sqlmer.StmtPreparer); ok {
+		return preparer.Prepare(ctx, sqlText)
+	}
+	return nil, fmt.Errorf("replica: %T does not support prepared statements", c.primary)
+}
+
+// ExecuteScript 用于在主库上执行包含多条语句的脚本。
+func (c *ReplicatedDbClient) ExecuteScript(ctx context.Context, script string, options ...sqlmer.ScriptOption) ([]sqlmer.ScriptResult, error) {
+	if executor, ok := c.primary.(sqlmer.ScriptExecutor); ok {
+		return executor.ExecuteScript(ctx, script, options...)
+	}
+	return nil, fmt.Errorf("replica: %T does not support executing scripts", c.primary)
+}
+
+// CreateTransaction 用于在主库上开始一个事务，事务内的所有语句（包括查询）都在主库上执行。
+func (c *ReplicatedDbClient) CreateTransaction() (sqlmer.TransactionKeeper, error) {
+	return c.primary.CreateTransaction()
+}
+
+// CreateTransactionContext 用于在主库上开始一个事务，事务内的所有语句（包括查询）都在主库上执行。
+func (c *ReplicatedDbClient) CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (sqlmer.TransactionKeeper, error) {
+	return c.primary.CreateTransactionContext(ctx, opts)
+}
+
+// Execute 用于在主库上执行非查询SQL语句，并返回所影响的行数。
+func (c *ReplicatedDbClient) Execute(sqlText string, args ...any) (int64, error) {
+	return c.primary.Execute(sqlText, args...)
+}
+
+// ExecuteContext 用于在主库上执行非查询SQL语句，并返回所影响的行数。
+func (c *ReplicatedDbClient) ExecuteContext(ctx context.Context, sqlText string, args ...any) (int64, error) {
+	return c.primary.ExecuteContext(ctx, sqlText, args...)
+}
+
+// Insert 用于在主库上执行插入语句，并返回新插入行的自增 id 。
+func (c *ReplicatedDbClient) Insert(sqlText string, args ...any) (int64, error) {
+	return c.primary.Insert(sqlText, args...)
+}
+
+// InsertContext 用于在主库上执行插入语句，并返回新插入行的自增 id 。
+func (c *ReplicatedDbClient) InsertContext(ctx context.Context, sqlText string, args ...any) (int64, error) {
+	return c.primary.InsertContext(ctx, sqlText, args...)
+}
+
+// SizedExecute 用于在主库上执行非查询SQL语句，并断言所影响的行数。
+func (c *ReplicatedDbClient) SizedExecute(expectedSize int64, sqlText string, args ...any) error {
+	return c.primary.SizedExecute(expectedSize, sqlText, args...)
+}
+
+// SizedExecuteContext 用于在主库上执行非查询SQL语句，并断言所影响的行数。
+func (c *ReplicatedDbClient) SizedExecuteContext(ctx context.Context, expectedSize int64, sqlText string, args ...any) error {
+	return c.primary.SizedExecuteContext(ctx, expectedSize, sqlText, args...)
+}
+
+// Exists 用于在从库上判断给定的查询的结果是否至少包含 1 行。
+// 除 sqlmer.DbClient.Exists 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) Exists(sqlText string, args ...any) (bool, error) {
+	reader, err := c.reader(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return reader.Exists(sqlText, args...)
+}
+
+// ExistsContext 用于在从库上判断给定的查询的结果是否至少包含 1 行， ctx 带有 ForcePrimary 标记时在主库上执行。
+// 除 sqlmer.DbClient.ExistsContext 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) ExistsContext(ctx context.Context, sqlText string, args ...any) (bool, error) {
+	reader, err := c.reader(ctx)
+	if err != nil {
+		return false, err
+	}
+	return reader.ExistsContext(ctx, sqlText, args...)
+}
+
+// Scalar 用于在从库上获取查询的第一行第一列的值。
+// 除 sqlmer.DbClient.Scalar 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) Scalar(sqlText string, args ...any) (any, bool, error) {
+	reader, err := c.reader(context.Background())
+	if err != nil {
+		return nil, false, err
+	}
+	return reader.Scalar(sqlText, args...)
+}
+
+// ScalarContext 用于在从库上获取查询的第一行第一列的值， ctx 带有 ForcePrimary 标记时在主库上执行。
+// 除 sqlmer.DbClient.ScalarContext 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) ScalarContext(ctx context.Context, sqlText string, args ...any) (any, bool, error) {
+	reader, err := c.reader(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	return reader.ScalarContext(ctx, sqlText, args...)
+}
+
+// Get 用于在从库上获取查询结果的第一行记录。
+// 除 sqlmer.DbClient.Get 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) Get(sqlText string, args ...any) (map[string]any, error) {
+	reader, err := c.reader(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return reader.Get(sqlText, args...)
+}
+
+// GetContext 用于在从库上获取查询结果的第一行记录， ctx 带有 ForcePrimary 标记时在主库上执行。
+// 除 sqlmer.DbClient.GetContext 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) GetContext(ctx context.Context, sqlText string, args ...any) (map[string]any, error) {
+	reader, err := c.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetContext(ctx, sqlText, args...)
+}
+
+// SliceGet 用于在从库上获取查询结果的所有行。
+// 除 sqlmer.DbClient.SliceGet 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) SliceGet(sqlText string, args ...any) ([]map[string]any, error) {
+	reader, err := c.reader(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return reader.SliceGet(sqlText, args...)
+}
+
+// SliceGetContext 用于在从库上获取查询结果的所有行， ctx 带有 ForcePrimary 标记时在主库上执行。
+// 除 sqlmer.DbClient.SliceGetContext 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) SliceGetContext(ctx context.Context, sqlText string, args ...any) ([]map[string]any, error) {
+	reader, err := c.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.SliceGetContext(ctx, sqlText, args...)
+}
+
+// Row 用于在从库上获取单个查询结果行。
+// 除 sqlmer.DbClient.Row 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) Row(sqlText string, args ...any) (*sqlen.EnhanceRow, error) {
+	reader, err := c.reader(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return reader.Row(sqlText, args...)
+}
+
+// RowContext 用于在从库上获取单个查询结果行， ctx 带有 ForcePrimary 标记时在主库上执行。
+// 除 sqlmer.DbClient.RowContext 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) RowContext(ctx context.Context, sqlText string, args ...any) (*sqlen.EnhanceRow, error) {
+	reader, err := c.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.RowContext(ctx, sqlText, args...)
+}
+
+// Rows 用于在从库上获取查询结果行的游标对象。
+// 除 sqlmer.DbClient.Rows 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) Rows(sqlText string, args ...any) (*sqlen.EnhanceRows, error) {
+	reader, err := c.reader(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return reader.Rows(sqlText, args...)
+}
+
+// RowsContext 用于在从库上获取查询结果行的游标对象， ctx 带有 ForcePrimary 标记时在主库上执行。
+// 除 sqlmer.DbClient.RowsContext 的错误外，没有可用的从库且关闭了 WithFallbackToPrimary 时返回 ErrNoReplicaAvailable 。
+func (c *ReplicatedDbClient) RowsContext(ctx context.Context, sqlText string, args ...any) (*sqlen.EnhanceRows, error) {
+	reader, err := c.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reader.RowsContext(ctx, sqlText, args...)
+}
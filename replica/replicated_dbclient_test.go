@@ -0,0 +1,211 @@
+package replica
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/testenv"
+)
+
+// newNodeClient 用于创建一个 SQLite 的 DbClient ，表 go_TypeTest 中 id=1 的 varcharTest 记录了节点名称，用于判断语句在哪个节点上执行。
+func newNodeClient(t *testing.T, name string) sqlmer.DbClient {
+	t.Helper()
+
+	dbClient, err := testenv.NewSqliteTempClient(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest=@p1 WHERE id=1", name); err != nil {
+		t.Fatal(err)
+	}
+	return dbClient
+}
+
+func newReplicatedClient(t *testing.T, replicaCount int, options ...Option) *ReplicatedDbClient {
+	t.Helper()
+
+	replicas := make([]sqlmer.DbClient, 0, replicaCount)
+	for i := 1; i <= replicaCount; i++ {
+		replicas = append(replicas, newNodeClient(t, fmt.Sprintf("replica%d", i)))
+	}
+	c := NewReplicatedDbClient(newNodeClient(t, "primary"), replicas, options...)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func scalarNode(t *testing.T, ctx context.Context, c sqlmer.DbClient) string {
+	t.Helper()
+
+	v, _, err := c.ScalarContext(ctx, "SELECT varcharTest FROM go_TypeTest WHERE id=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.(string)
+}
+
+func TestReplicatedDbClient_Routing(t *testing.T) {
+	c := newReplicatedClient(t, 2)
+	ctx := context.Background()
+
+	t.Run("read_round_robin", func(t *testing.T) {
+		got := make([]string, 0, 4)
+		for i := 0; i < 4; i++ {
+			got = append(got, scalarNode(t, ctx, c))
+		}
+		want := []string{"replica1", "replica2", "replica1", "replica2"}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("reads went to %v, want %v", got, want)
+		}
+
+		row, err := c.Get("SELECT varcharTest FROM go_TypeTest WHERE id=1")
+		if err != nil || row["varcharTest"] != "replica1" {
+			t.Fatalf("Get() = %v, %v, want replica1", row, err)
+		}
+	})
+
+	t.Run("write_to_primary", func(t *testing.T) {
+		if _, err := c.Execute("UPDATE go_TypeTest SET varcharTest=varcharTest||'_w' WHERE id=1"); err != nil {
+			t.Fatal(err)
+		}
+		if got := scalarNode(t, ForcePrimary(ctx), c); got != "primary_w" {
+			t.Fatalf("ForcePrimary read = %v, want primary_w", got)
+		}
+		if err := c.SizedExecute(1, "UPDATE go_TypeTest SET varcharTest='primary' WHERE id=1"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("transaction_on_primary", func(t *testing.T) {
+		tx, err := c.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+
+		if got := scalarNode(t, ctx, tx); got != "primary" {
+			t.Fatalf("read in transaction = %v, want primary", got)
+		}
+	})
+}
+
+func TestReplicatedDbClient_HealthCheck(t *testing.T) {
+	var down = errors.New("down")
+	downNodes := make(chan map[string]bool, 1)
+	downNodes <- map[string]bool{}
+
+	check := func(ctx context.Context, replica sqlmer.DbClient) error {
+		nodes := <-downNodes
+		defer func() { downNodes <- nodes }()
+
+		v, _, err := replica.ScalarContext(ctx, "SELECT varcharTest FROM go_TypeTest WHERE id=1")
+		if err != nil {
+			return err
+		}
+		if nodes[v.(string)] {
+			return down
+		}
+		return nil
+	}
+	setDown := func(names ...string) {
+		nodes := map[string]bool{}
+		for _, name := range names {
+			nodes[name] = true
+		}
+		<-downNodes
+		downNodes <- nodes
+	}
+	// waitFor 用于等待健康检查生效，即连续多次查询都在 want 节点上执行。
+	waitFor := func(t *testing.T, c *ReplicatedDbClient, want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for hits := 0; ; {
+			got, _, err := c.Scalar("SELECT varcharTest FROM go_TypeTest WHERE id=1")
+			if err == nil && got == want {
+				if hits++; hits == 4 {
+					return
+				}
+				continue
+			}
+			hits = 0
+			if time.Now().After(deadline) {
+				t.Fatalf("reads went to %v (err: %v), want %v", got, err, want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	t.Run("fallback_to_primary", func(t *testing.T) {
+		setDown()
+		c := newReplicatedClient(t, 2, WithHealthCheck(10*time.Millisecond, check))
+
+		setDown("replica1")
+		waitFor(t, c, "replica2")
+
+		setDown("replica1", "replica2")
+		waitFor(t, c, "primary")
+
+		// 恢复的从库重新参与查询。
+		setDown("replica2")
+		waitFor(t, c, "replica1")
+	})
+
+	t.Run("no_fallback", func(t *testing.T) {
+		setDown("replica1")
+		c := newReplicatedClient(t, 1, WithHealthCheck(10*time.Millisecond, check), WithFallbackToPrimary(false))
+
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			_, err := c.Get("SELECT varcharTest FROM go_TypeTest WHERE id=1")
+			if errors.Is(err, ErrNoReplicaAvailable) {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Get() error = %v, want ErrNoReplicaAvailable", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// 强制使用主库时不受影响。
+		if got := scalarNode(t, ForcePrimary(context.Background()), c); got != "primary" {
+			t.Fatalf("ForcePrimary read = %v, want primary", got)
+		}
+	})
+}
+
+func TestReplicatedDbClient_Balancer(t *testing.T) {
+	last := BalancerFunc(func(replicas []sqlmer.DbClient) sqlmer.DbClient {
+		return replicas[len(replicas)-1]
+	})
+	c := newReplicatedClient(t, 3, WithBalancer(last), WithHealthCheck(0, nil))
+	for i := 0; i < 3; i++ {
+		if got := scalarNode(t, context.Background(), c); got != "replica3" {
+			t.Fatalf("read went to %v, want replica3", got)
+		}
+	}
+
+	c = newReplicatedClient(t, 2, WithBalancer(RandomBalancer))
+	seen := map[string]bool{}
+	for i := 0; i < 100 && len(seen) < 2; i++ {
+		seen[scalarNode(t, context.Background(), c)] = true
+	}
+	if !seen["replica1"] || !seen["replica2"] || seen["primary"] {
+		t.Fatalf("RandomBalancer reads went to %v, want replica1 and replica2", seen)
+	}
+}
+
+func TestReplicatedDbClient_Close(t *testing.T) {
+	c := newReplicatedClient(t, 2, WithHealthCheck(time.Millisecond, nil))
+	if err := c.PingContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := c.Primary().PingContext(context.Background()); err == nil {
+		t.Fatal("primary should be closed")
+	}
+}
@@ -0,0 +1,115 @@
+package sqlmer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+var _ ScriptExecutor = (*AbstractDbClient)(nil)
+var _ ScriptExecutor = (*DbClientEx)(nil)
+
+// ScriptOption 是 ExecuteScript 的可选配置。
+type ScriptOption func(config *scriptConfig)
+
+// scriptConfig 是 ExecuteScript 的配置。
+type scriptConfig struct {
+	inTransaction bool // 是否在一个事务中执行所有语句。
+}
+
+// WithScriptTransaction 用于设置是否在一个事务中执行脚本中的所有语句（默认为 false ），开启后任一语句失败时整体回滚。
+// 在事务中调用 ExecuteScript 时，语句总是在该事务中执行，此选项无效。
+// 注意：MySQL 等数据库的 DDL 语句会隐式提交事务，包含 DDL 的脚本无法完整回滚。
+func WithScriptTransaction(enabled bool) ScriptOption {
+	return func(config *scriptConfig) {
+		config.inTransaction = enabled
+	}
+}
+
+// ScriptResult 是脚本中一条语句的执行结果。
+type ScriptResult struct {
+	Index        int    // 语句在拆分后的语句中的序号（从 0 开始）。
+	Statement    string // 执行的语句。
+	RowsAffected int64  // 语句影响的行数，驱动无法获取时为 -1 。
+}
+
+// ExecuteScript 用于执行包含多条语句的脚本。
+// 脚本按驱动的规则拆分为语句（如 SQL Server 按单独一行的 GO 拆分、 MySQL 支持 DELIMITER 命令，均会跳过字符串和注释中的分隔符），
+// 之后逐条执行，遇到错误时停止。语句不做参数绑定，其中的 @ 、 ? 等原样发送给数据库，也不使用语句缓存。
+// params:
+//
+//	@ctx context。
+//	@script 脚本。
+//	@options 可选配置，见 WithScriptTransaction 。
+//
+// returns:
+//
+//	@results 执行成功的语句的结果，出错时为出错前已执行的语句（开启 WithScriptTransaction 时，这些语句已被回滚）。
+//	@err 执行过程中遇到的错误。
+//
+// 语句执行失败时，返回 *ScriptError ，其中包含失败语句的序号和内容，可以通过 errors.As 获取。
+func (client *AbstractDbClient) ExecuteScript(ctx context.Context, script string, options ...ScriptOption) ([]ScriptResult, error) {
+	if tx, ok := client.contextTx(ctx); ok {
+		if executor, ok := tx.(ScriptExecutor); ok {
+			return executor.ExecuteScript(ctx, script, options...)
+		}
+		return nil, fmt.Errorf("dbClient: %T does not support executing scripts", tx)
+	}
+
+	config := scriptConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+
+	statements := client.config.splitScriptFunc(script)
+	if _, inTx := client.Exer.(*sqlen.TxEnhance); inTx || !config.inTransaction {
+		return client.executeStatements(ctx, statements)
+	}
+
+	tx, err := client.CreateTransactionContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	results, err := tx.(*abstractTransactionKeeper).executeStatements(ctx, statements)
+	if err != nil {
+		return results, err
+	}
+	if err := tx.Commit(); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// executeStatements 用于逐条执行语句，语句原样发送给数据库。
+func (client *AbstractDbClient) executeStatements(ctx context.Context, statements []string) ([]ScriptResult, error) {
+	results := make([]ScriptResult, 0, len(statements))
+	for i, statement := range statements {
+		observation := client.startObservation(statement, nil, false)
+		result, err := client.Exer.ExecContext(ctx, statement)
+		if err != nil {
+			err = getExecutingSqlError(err, statement, statement, nil)
+			observation.finish(ctx, statement, nil, nil, err)
+			return results, &ScriptError{Index: i, Statement: statement, Err: err}
+		}
+		observation.finish(ctx, statement, nil, result, nil)
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			rowsAffected = -1
+		}
+		results = append(results, ScriptResult{Index: i, Statement: statement, RowsAffected: rowsAffected})
+	}
+	return results, nil
+}
+
+// ExecuteScript 用于执行包含多条语句的脚本，见 AbstractDbClient.ExecuteScript 。
+// 原始的 DbClient 需要实现 ScriptExecutor 接口，否则返回错误。
+func (c *DbClientEx) ExecuteScript(ctx context.Context, script string, options ...ScriptOption) ([]ScriptResult, error) {
+	if executor, ok := c.DbClient.(ScriptExecutor); ok {
+		return executor.ExecuteScript(ctx, script, options...)
+	}
+	return nil, fmt.Errorf("dbClient: %T does not support executing scripts", c.DbClient)
+}
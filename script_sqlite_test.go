@@ -0,0 +1,100 @@
+package sqlmer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bunnier/sqlmer"
+)
+
+func TestExecuteScript(t *testing.T) {
+	ctx := context.Background()
+	script := `
+CREATE TABLE script_test (id INTEGER PRIMARY KEY, name TEXT, n INTEGER);
+-- 字符串、注释中的 ; 及 @ 不影响拆分和执行。
+INSERT INTO script_test (name) VALUES ('a;@b'), ('c');
+CREATE TRIGGER script_test_insert AFTER INSERT ON script_test
+BEGIN
+  UPDATE script_test SET n = CASE WHEN NEW.id > 1 THEN 1 ELSE 0 END WHERE id = NEW.id;
+END;
+INSERT INTO script_test (name) VALUES ('d');`
+
+	t.Run("ok", func(t *testing.T) {
+		c := getSqliteClientExForTest(t)
+		results, err := c.ExecuteScript(ctx, script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 4 {
+			t.Fatalf("got %d results, want 4: %+v", len(results), results)
+		}
+		if results[1].Index != 1 || results[1].RowsAffected != 2 {
+			t.Errorf("results[1] = %+v, want 2 rows affected", results[1])
+		}
+		if n, _, err := c.Scalar("SELECT n FROM script_test WHERE name = 'd'"); err != nil || n != int64(1) {
+			t.Errorf("Scalar() = %v, %v, want the trigger executed", n, err)
+		}
+		if name, _, err := c.Scalar("SELECT name FROM script_test WHERE id = 1"); err != nil || name != "a;@b" {
+			t.Errorf("Scalar() = %v, %v, want a;@b", name, err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := getSqliteClientExForTest(t)
+		results, err := c.ExecuteScript(ctx, script+";\nINSERT INTO not_exists VALUES (1);\nINSERT INTO script_test (name) VALUES ('e')")
+
+		var scriptErr *sqlmer.ScriptError
+		if !errors.As(err, &scriptErr) {
+			t.Fatalf("ExecuteScript() error = %v, want a ScriptError", err)
+		}
+		if scriptErr.Index != 4 || scriptErr.Statement != "INSERT INTO not_exists VALUES (1)" {
+			t.Errorf("ScriptError = %d, %q, want the 5th statement", scriptErr.Index, scriptErr.Statement)
+		}
+		if !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Errorf("ExecuteScript() error = %v, want ErrExecutingSql", err)
+		}
+		if len(results) != 4 {
+			t.Errorf("got %d results, want the 4 statements before the error", len(results))
+		}
+		if count, _, _ := c.Scalar("SELECT COUNT(1) FROM script_test"); count != int64(3) {
+			t.Errorf("count = %v, want 3", count)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		c := getSqliteClientExForTest(t)
+		_, err := c.ExecuteScript(ctx, "CREATE TABLE script_tx (id INTEGER);\nINSERT INTO script_tx VALUES (1);\nINSERT INTO not_exists VALUES (1);",
+			sqlmer.WithScriptTransaction(true))
+		if err == nil {
+			t.Fatal("ExecuteScript() error = nil")
+		}
+		if exists, err := c.Exists("SELECT 1 FROM sqlite_master WHERE name = 'script_tx'"); err != nil || exists {
+			t.Errorf("Exists() = %v, %v, want the script rolled back", exists, err)
+		}
+
+		if _, err := c.ExecuteScript(ctx, "CREATE TABLE script_tx (id INTEGER); INSERT INTO script_tx VALUES (1);", sqlmer.WithScriptTransaction(true)); err != nil {
+			t.Fatal(err)
+		}
+		if count, _, _ := c.Scalar("SELECT COUNT(1) FROM script_tx"); count != int64(1) {
+			t.Errorf("count = %v, want 1", count)
+		}
+	})
+
+	t.Run("in_transaction", func(t *testing.T) {
+		c := getSqliteClientExForTest(t)
+		tx, err := c.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+
+		if _, err := sqlmer.Extend(tx).ExecuteScript(ctx, "CREATE TABLE script_in_tx (id INTEGER); INSERT INTO script_in_tx VALUES (1);"); err != nil {
+			t.Fatal(err)
+		}
+		tx.Rollback()
+		if exists, err := c.Exists("SELECT 1 FROM sqlite_master WHERE name = 'script_in_tx'"); err != nil || exists {
+			t.Errorf("Exists() = %v, %v, want the script rolled back with the transaction", exists, err)
+		}
+	})
+}
@@ -11,6 +11,11 @@ type EnhanceRow struct {
 	err  error
 }
 
+// NewEnhanceRow 用于通过查询得到的 EnhanceRows 创建 EnhanceRow ，查询的错误会延迟到读取数据时返回。
+func NewEnhanceRow(rows *EnhanceRows, err error) *EnhanceRow {
+	return &EnhanceRow{rows, err}
+}
+
 // SetErrWrapper 用于为延迟暴露的错误注入统一包装逻辑。
 func (r *EnhanceRow) SetErrWrapper(wrapper ErrWrapper) {
 	if r.rows != nil {
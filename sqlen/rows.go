@@ -2,6 +2,8 @@ package sqlen
 
 import (
 	"database/sql"
+	"io"
+	"iter"
 	"reflect"
 )
 
@@ -19,6 +21,7 @@ type EnhanceRows struct {
 	wrapErr       ErrWrapper
 
 	columnMetaSlice []*columnMeta // 用于对列的元数据做缓存。
+	lastStructPlan  *structPlan   // 最近一次 StructScan 使用的映射计划，同一结果集的各行共用。
 
 	err error
 }
@@ -101,15 +104,9 @@ func (rs *EnhanceRows) SliceScan() ([]any, error) {
 	dest := make([]any, len(rs.columnMetaSlice))
 	destRefVal := make([]reflect.Value, len(rs.columnMetaSlice))
 
-	for i, colMeta := range rs.columnMetaSlice {
-		if colMeta.scanType == nil {
-			// 第一次查询，需要获取 scan 的类型，获取后缓存到列元数据里。
-			colMeta.scanType = unifyScanType(rs.getScanTypeFn(colMeta.colType), colMeta.colType)
-		}
-
-		refVal := reflect.New(colMeta.scanType) // 使用数据库驱动标记的类型来接收数据。
-		dest[i] = refVal.Interface()            // 注意，这里传入的是指定值的指针。
-		destRefVal[i] = refVal                  // 保存这个 Reflect.value 在后面用于解引用。
+	for i := range rs.columnMetaSlice {
+		destRefVal[i] = rs.newScanHolder(i) // 保存这个 Reflect.value 在后面用于解引用。
+		dest[i] = destRefVal[i].Interface() // 注意，这里传入的是指定值的指针。
 	}
 
 	if rs.err = rs.Scan(dest...); rs.err != nil {
@@ -117,29 +114,46 @@ func (rs *EnhanceRows) SliceScan() ([]any, error) {
 	}
 
 	for i := 0; i < len(rs.columnMetaSlice); i++ {
-		// 之前为了能让 Scan 修改数据，保存的是指针，而返回上层时候只需要实际数据，因此进行解引用。
-		dest[i] = destRefVal[i].Elem().Interface()
-
-		// 注意：根据 sql.RawBytes 类型的定义，database/sql 的有效性仅在下一次 Scan 或 Close 之前。
-		// 如果将该 sql.RawBytes 直接向上层暴露，会因为之后被覆写，导致数据损坏。
-		// 因此这里必须对 sql.RawBytes 做一次 deep copy，将数据拷贝到独立内存中。
-		if raw, ok := dest[i].(sql.RawBytes); ok {
-			if raw == nil {
-				dest[i] = nil
-			} else {
-				frozen := make(sql.RawBytes, len(raw))
-				copy(frozen, raw)
-				dest[i] = frozen
-			}
-		}
+		dest[i] = rs.extractScannedValue(i, destRefVal[i])
+	}
+
+	return dest, rs.err
+}
+
+// newScanHolder 用于为第 i 列创建一个用于接收 Scan 数据的指针（以 reflect.Value 表示）。
+func (rs *EnhanceRows) newScanHolder(i int) reflect.Value {
+	colMeta := rs.columnMetaSlice[i]
+	if colMeta.scanType == nil {
+		// 第一次查询，需要获取 scan 的类型，获取后缓存到列元数据里。
+		colMeta.scanType = unifyScanType(rs.getScanTypeFn(colMeta.colType), colMeta.colType)
+	}
+	return reflect.New(colMeta.scanType) // 使用数据库驱动标记的类型来接收数据。
+}
 
-		extractNullableColumnValue(rs.columnMetaSlice[i].colType, &dest[i]) // 进行统一的空值处理逻辑。
-		if dest[i] != nil {
-			rs.unifyDataType(rs.columnMetaSlice[i].colType, &dest[i]) // 进行数据库定制的类型处理。
+// extractScannedValue 用于从 Scan 后的 holder 中取出第 i 列的值，并进行统一的空值及类型处理。
+func (rs *EnhanceRows) extractScannedValue(i int, holder reflect.Value) any {
+	// 之前为了能让 Scan 修改数据，保存的是指针，而返回上层时候只需要实际数据，因此进行解引用。
+	value := holder.Elem().Interface()
+
+	// 注意：根据 sql.RawBytes 类型的定义，database/sql 的有效性仅在下一次 Scan 或 Close 之前。
+	// 如果将该 sql.RawBytes 直接向上层暴露，会因为之后被覆写，导致数据损坏。
+	// 因此这里必须对 sql.RawBytes 做一次 deep copy，将数据拷贝到独立内存中。
+	if raw, ok := value.(sql.RawBytes); ok {
+		if raw == nil {
+			value = nil
+		} else {
+			frozen := make(sql.RawBytes, len(raw))
+			copy(frozen, raw)
+			value = frozen
 		}
 	}
 
-	return dest, rs.err
+	colType := rs.columnMetaSlice[i].colType
+	extractNullableColumnValue(colType, &value) // 进行统一的空值处理逻辑。
+	if value != nil {
+		rs.unifyDataType(colType, &value) // 进行数据库定制的类型处理。
+	}
+	return value
 }
 
 func (r *EnhanceRows) Err() error {
@@ -150,6 +164,36 @@ func (r *EnhanceRows) Err() error {
 	return r.err
 }
 
+// Maps 返回逐行读取游标的迭代器，每一行以 map 的形式给出。
+// 迭代结束（包括在循环中提前退出）后游标会被自动关闭；出现错误时，会给出该错误并结束迭代。
+//
+//	for m, err := range rows.Maps() {
+//		if err != nil {
+//			return err
+//		}
+//		// 使用 m 。
+//	}
+func (rs *EnhanceRows) Maps() iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		defer rs.Close() // This error is ignored.
+
+		for rs.Next() {
+			m, err := rs.MapScan()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(m, nil) {
+				return
+			}
+		}
+
+		if err := rs.Err(); err != nil && err != io.EOF {
+			yield(nil, err)
+		}
+	}
+}
+
 // Close 关闭游标，并对关闭过程中暴露的错误做统一包装。
 func (r *EnhanceRows) Close() error {
 	if r.Rows == nil {
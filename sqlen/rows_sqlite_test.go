@@ -133,3 +133,71 @@ func TestEnhanceRows_Err_returns_cached_wrapped_error(t *testing.T) {
 		t.Fatalf("expected Err() to reuse cached error, got wrapper count %d", wrapCount)
 	}
 }
+
+func TestEnhanceRows_Maps(t *testing.T) {
+	dbEnhance, db := newSqliteEnhanceForTest(t)
+
+	t.Run("all", func(t *testing.T) {
+		rows, err := dbEnhance.EnhancedQuery(`SELECT Id, VarcharTest FROM go_TypeTest ORDER BY Id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []map[string]any
+		for m, err := range rows.Maps() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, m)
+		}
+
+		want := []map[string]any{
+			{"Id": int64(1), "VarcharTest": "行1"},
+			{"Id": int64(2), "VarcharTest": "行2"},
+			{"Id": int64(3), "VarcharTest": "行3"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Maps() = %v, want %v", got, want)
+		}
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Fatalf("expected rows closed, got %d connections in use", inUse)
+		}
+	})
+
+	t.Run("break", func(t *testing.T) {
+		rows, err := dbEnhance.EnhancedQuery(`SELECT Id FROM go_TypeTest ORDER BY Id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		count := 0
+		for _, err := range rows.Maps() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			count++
+			break
+		}
+
+		if count != 1 {
+			t.Fatalf("expected 1 row, got %d", count)
+		}
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Fatalf("expected rows closed after break, got %d connections in use", inUse)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		rows, err := dbEnhance.EnhancedQuery(`SELECT Id FROM go_TypeTest`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = rows.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		for m, err := range rows.Maps() {
+			t.Fatalf("expected no rows from closed cursor, got %v %v", m, err)
+		}
+	})
+}
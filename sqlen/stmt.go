@@ -0,0 +1,37 @@
+package sqlen
+
+import (
+	"context"
+	"database/sql"
+)
+
+// StmtEnhance 是对原生 sql.Stmt 的包装，除了原本的方法外，另外提供了返回增强对象的查询方法。
+type StmtEnhance struct {
+	*sql.Stmt
+	dbEnhance *DbEnhance // 用于统一不同驱动在 Go 中的映射类型。
+}
+
+func NewStmtEnhance(stmt *sql.Stmt, dbEnhance *DbEnhance) *StmtEnhance {
+	return &StmtEnhance{stmt, dbEnhance}
+}
+
+// EnhancedQueryRowContext executes a prepared query statement that is expected to return at most one row.
+// 返回增强后的 EnhanceRow 对象，相比原生 sql.Row 提供了更强的数据读取能力。
+func (stmt *StmtEnhance) EnhancedQueryRowContext(ctx context.Context, args ...any) *EnhanceRow {
+	rows, err := stmt.EnhancedQueryContext(ctx, args...)
+	return &EnhanceRow{rows: rows, err: err}
+}
+
+// EnhancedQueryContext executes a prepared query statement with the given arguments.
+// 返回增强后的 EnhanceRows 对象，相比原生 sql.Rows 提供了更强的数据读取能力。
+func (stmt *StmtEnhance) EnhancedQueryContext(ctx context.Context, args ...any) (*EnhanceRows, error) {
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &EnhanceRows{
+		Rows:          rows,
+		getScanTypeFn: stmt.dbEnhance.getScanTypeFn,
+		unifyDataType: stmt.dbEnhance.unifyDataType,
+	}, nil
+}
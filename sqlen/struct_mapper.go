@@ -0,0 +1,188 @@
+package sqlen
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// FieldMatchFunc 用于根据列名匹配结构体的字段，返回的字段的 Index 需是从 structType 开始的完整路径。
+// 没有匹配的字段时返回 false ，该列的数据会被丢弃。
+type FieldMatchFunc func(structType reflect.Type, columnName string) (reflect.StructField, bool)
+
+// ValueConvertFunc 用于将列的值转换为字段的类型，返回 nil 时表示字段保持零值。
+type ValueConvertFunc func(value any, typ reflect.Type) (any, error)
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// StructMapper 用于将查询的行直接填充到结构体，不经过中间的 map 。
+// 对于每组（结构体类型，列集合），列到字段的映射计划只计算一次并缓存，可以安全的被多个 goroutine 共享。
+type StructMapper struct {
+	matchField FieldMatchFunc
+	plans      sync.Map // planKey -> *structPlan
+}
+
+// planKey 是映射计划的缓存 key 。
+type planKey struct {
+	typ     reflect.Type
+	columns string // 用 \x00 连接的列名。
+}
+
+// structPlan 是一组列到一个结构体类型的映射计划。
+type structPlan struct {
+	mapper  *StructMapper // 计算该计划的 StructMapper 。
+	typ     reflect.Type  // 结构体类型。
+	columns []string      // 查询的列名。
+	fields  []*fieldPlan  // 和查询的列一一对应，为 nil 的列没有匹配的字段。
+}
+
+// fieldPlan 是单个列到字段的映射。
+type fieldPlan struct {
+	name    string       // 字段名称，用于错误信息。
+	index   []int        // 从结构体开始的字段路径。
+	typ     reflect.Type // 字段的类型。
+	scanner bool         // 字段的指针是否实现了 sql.Scanner ，是的话直接 Scan 到字段上。
+}
+
+// NewStructMapper 用于创建一个 StructMapper 。
+func NewStructMapper(matchField FieldMatchFunc) *StructMapper {
+	return &StructMapper{matchField: matchField}
+}
+
+// getPlan 用于获取给定的结构体类型和列的映射计划，没有缓存时计算并缓存。
+func (m *StructMapper) getPlan(typ reflect.Type, columns []string) *structPlan {
+	key := planKey{typ, strings.Join(columns, "\x00")}
+	if plan, ok := m.plans.Load(key); ok {
+		return plan.(*structPlan)
+	}
+
+	plan := &structPlan{mapper: m, typ: typ, columns: columns, fields: make([]*fieldPlan, len(columns))}
+	for i, column := range columns {
+		field, ok := m.matchField(typ, column)
+		if !ok {
+			continue
+		}
+		plan.fields[i] = &fieldPlan{
+			name:    field.Name,
+			index:   field.Index,
+			typ:     field.Type,
+			scanner: reflect.PointerTo(field.Type).Implements(scannerType),
+		}
+	}
+
+	actual, _ := m.plans.LoadOrStore(key, plan)
+	return actual.(*structPlan)
+}
+
+// StructScan 用于把一行数据直接填充到 dest 指向的结构体中， dest 必须是结构体的指针。
+//   - 字段的指针实现了 sql.Scanner 的，直接 Scan 到字段上；
+//   - 其余字段使用和 SliceScan 相同的逻辑读取列的值，值的类型和字段一致时直接赋值，否则使用 convert 转换；
+//   - 没有匹配字段的列会被丢弃，没有匹配列的字段保持原值。
+func (rs *EnhanceRows) StructScan(mapper *StructMapper, convert ValueConvertFunc, dest any) error {
+	if rs.err != nil {
+		return rs.err
+	}
+
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Pointer || destVal.IsNil() || destVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sqlen: StructScan dest must be a non-nil pointer to struct, got %T", dest)
+	}
+	structVal := destVal.Elem()
+
+	if rs.err = rs.initColumns(); rs.err != nil {
+		return rs.err
+	}
+
+	// 同一结果集的列不变，映射计划只在首行（或目标类型变化时）获取一次。
+	plan := rs.lastStructPlan
+	if plan == nil || plan.mapper != mapper || plan.typ != structVal.Type() {
+		columns := make([]string, len(rs.columnMetaSlice))
+		for i, colMeta := range rs.columnMetaSlice {
+			columns[i] = colMeta.colType.Name()
+		}
+		plan = mapper.getPlan(structVal.Type(), columns)
+		rs.lastStructPlan = plan
+	}
+
+	scanDest := make([]any, len(rs.columnMetaSlice))
+	holders := make([]reflect.Value, len(rs.columnMetaSlice))
+	for i, field := range plan.fields {
+		if field != nil && field.scanner {
+			fieldVal, ok := fieldByIndexAlloc(structVal, field.index)
+			if ok {
+				scanDest[i] = fieldVal.Addr().Interface()
+				continue
+			}
+		}
+
+		// 没有匹配字段的列也需要接收数据，数据库驱动要求接收的数量和列一致。
+		holders[i] = rs.newScanHolder(i)
+		scanDest[i] = holders[i].Interface()
+	}
+
+	if rs.err = rs.Scan(scanDest...); rs.err != nil {
+		return rs.err
+	}
+
+	for i, field := range plan.fields {
+		if field == nil || !holders[i].IsValid() {
+			continue
+		}
+
+		fieldVal, ok := fieldByIndexAlloc(structVal, field.index)
+		if !ok {
+			continue
+		}
+
+		value := rs.extractScannedValue(i, holders[i])
+		if err := assignField(fieldVal, field.typ, value, convert); err != nil {
+			return fmt.Errorf("sqlen: error on converting column '%s' to field '%s': %w", plan.columns[i], field.name, err)
+		}
+	}
+
+	return nil
+}
+
+// assignField 用于将列的值赋给字段：类型一致时直接赋值，否则使用 convert 转换。
+func assignField(fieldVal reflect.Value, fieldTyp reflect.Type, value any, convert ValueConvertFunc) error {
+	if value != nil && reflect.TypeOf(value) == fieldTyp {
+		fieldVal.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	if convert == nil {
+		return errors.New("sqlen: no ValueConvertFunc provided")
+	}
+
+	converted, err := convert(value, fieldTyp)
+	if err != nil {
+		return err
+	}
+	if converted == nil {
+		fieldVal.SetZero()
+		return nil
+	}
+	fieldVal.Set(reflect.ValueOf(converted))
+	return nil
+}
+
+// fieldByIndexAlloc 类似 reflect.Value.FieldByIndex ，但路径上遇到空的嵌入结构体指针时会为其分配内存。
+// 路径上的字段不可设置（如未导出的嵌入结构体指针）时返回 false 。
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, v.CanSet()
+}
@@ -0,0 +1,155 @@
+package sqlen_test
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+// matchFieldIgnoreCaseForTest 按字段名称忽略大小写匹配列。
+func matchFieldIgnoreCaseForTest(structType reflect.Type, columnName string) (reflect.StructField, bool) {
+	return structType.FieldByNameFunc(func(name string) bool {
+		return strings.EqualFold(name, columnName)
+	})
+}
+
+// convertForTest 使用 reflect 的类型转换，值为 nil 时返回 nil 。
+func convertForTest(value any, typ reflect.Type) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	v := reflect.ValueOf(value)
+	if !v.CanConvert(typ) {
+		return nil, fmt.Errorf("cannot convert %T to %v", value, typ)
+	}
+	return v.Convert(typ).Interface(), nil
+}
+
+type StructMapperBaseForTest struct {
+	Id int64
+}
+
+type structMapperRowForTest struct {
+	*StructMapperBaseForTest
+	VarcharTest sql.NullString
+	DecimalTest float32
+	Missing     string
+}
+
+func TestEnhanceRows_StructScan(t *testing.T) {
+	dbEnhance, _ := newSqliteEnhanceForTest(t)
+
+	matchCount := 0
+	mapper := sqlen.NewStructMapper(func(structType reflect.Type, columnName string) (reflect.StructField, bool) {
+		matchCount++
+		return matchFieldIgnoreCaseForTest(structType, columnName)
+	})
+
+	rows, err := dbEnhance.EnhancedQuery(`SELECT Id, VarcharTest, DecimalTest, 1 AS Unknown FROM go_TypeTest ORDER BY Id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var got []structMapperRowForTest
+	for rows.Next() {
+		row := structMapperRowForTest{Missing: "keep"}
+		if err := rows.StructScan(mapper, convertForTest, &row); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, row)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("StructScan() got %d rows, want 3", len(got))
+	}
+	for i, row := range got {
+		if row.StructMapperBaseForTest == nil || row.Id != int64(i+1) {
+			t.Errorf("StructScan() row %d Id = %v, want %v", i, row.StructMapperBaseForTest, i+1)
+		}
+		if want := (sql.NullString{String: fmt.Sprintf("行%d", i+1), Valid: true}); row.VarcharTest != want {
+			t.Errorf("StructScan() row %d VarcharTest = %v, want %v", i, row.VarcharTest, want)
+		}
+		if want := float32(i+1) * 1.11; row.DecimalTest != want {
+			t.Errorf("StructScan() row %d DecimalTest = %v, want %v", i, row.DecimalTest, want)
+		}
+		if row.Missing != "keep" {
+			t.Errorf("StructScan() row %d Missing = %v, want keep", i, row.Missing)
+		}
+	}
+
+	// 映射计划只计算一次。
+	if matchCount != 4 {
+		t.Errorf("StructScan() matchField called %d times, want 4", matchCount)
+	}
+}
+
+func TestEnhanceRows_StructScan_switchType(t *testing.T) {
+	dbEnhance, _ := newSqliteEnhanceForTest(t)
+	mapper := sqlen.NewStructMapper(matchFieldIgnoreCaseForTest)
+
+	rows, err := dbEnhance.EnhancedQuery(`SELECT Id, VarcharTest FROM go_TypeTest ORDER BY Id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	// 同一结果集中交替使用不同的结构体类型，各自使用对应的映射计划。
+	for i := 1; rows.Next(); i++ {
+		var idRow struct{ Id int64 }
+		if err := rows.StructScan(mapper, convertForTest, &idRow); err != nil {
+			t.Fatal(err)
+		}
+		var nameRow struct{ VarcharTest string }
+		if err := rows.StructScan(mapper, convertForTest, &nameRow); err != nil {
+			t.Fatal(err)
+		}
+		if idRow.Id != int64(i) || nameRow.VarcharTest != fmt.Sprintf("行%d", i) {
+			t.Errorf("StructScan() row %d = %v %v", i, idRow, nameRow)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnhanceRows_StructScan_errors(t *testing.T) {
+	dbEnhance, _ := newSqliteEnhanceForTest(t)
+	mapper := sqlen.NewStructMapper(matchFieldIgnoreCaseForTest)
+
+	t.Run("dest_not_struct_pointer", func(t *testing.T) {
+		rows, err := dbEnhance.EnhancedQuery(`SELECT Id FROM go_TypeTest`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		rows.Next()
+		var id int64
+		if err := rows.StructScan(mapper, convertForTest, &id); err == nil {
+			t.Fatal("StructScan() err = nil, want has a err")
+		}
+	})
+
+	t.Run("convert_failed", func(t *testing.T) {
+		rows, err := dbEnhance.EnhancedQuery(`SELECT VarcharTest FROM go_TypeTest`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		rows.Next()
+		var row struct{ VarcharTest []int }
+		err = rows.StructScan(mapper, convertForTest, &row)
+		if err == nil || !strings.Contains(err.Error(), "VarcharTest") {
+			t.Fatalf("StructScan() err = %v, want a err about VarcharTest", err)
+		}
+	})
+}
@@ -2,14 +2,18 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
 	"reflect"
 	"strings"
 	"time"
 
 	"github.com/bunnier/sqlmer"
 	"github.com/bunnier/sqlmer/internal/named2qm"
+	"github.com/bunnier/sqlmer/internal/sqlscript"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 	"github.com/bunnier/sqlmer/sqlen"
 
+	"github.com/ncruces/go-sqlite3"
 	_ "github.com/ncruces/go-sqlite3/driver"
 	_ "github.com/ncruces/go-sqlite3/embed"
 )
@@ -28,9 +32,14 @@ type SqliteDbClient struct {
 func NewSqliteDbClient(dsn string, options ...sqlmer.DbClientOption) (*SqliteDbClient, error) {
 	fixedOptions := []sqlmer.DbClientOption{
 		sqlmer.WithDsn(DriverName, dsn),
-		sqlmer.WithGetScanTypeFunc(getScanTypeFn()),        // 定制 Scan 类型逻辑。
-		sqlmer.WithUnifyDataTypeFunc(getUnifyDataTypeFn()), // 定制类型转换逻辑。
-		sqlmer.WithBindArgsFunc(bindArgs),                  // 定制参数绑定逻辑。
+		sqlmer.WithGetScanTypeFunc(getScanTypeFn()),                    // 定制 Scan 类型逻辑。
+		sqlmer.WithUnifyDataTypeFunc(getUnifyDataTypeFn()),             // 定制类型转换逻辑。
+		sqlmer.WithBindArgsFunc(bindArgs),                              // 定制参数绑定逻辑。
+		sqlmer.WithParseParamNamesFunc(defaultBinder.ParseParamNames),  // 定制命名参数解析逻辑。
+		sqlmer.WithSavepointDialect(sqlmer.StandardSavepointDialect{}), // 使用 SQL 标准的保存点语句。
+		sqlmer.WithRetryableErrorFunc(isRetryableError),                // 定制可重试错误的判断逻辑。
+		sqlmer.WithMaxParamCount(32766),                                // SQLite 3.32.0 起默认最多 32766 个参数。
+		sqlmer.WithSplitScriptFunc(splitScript),                        // 按 ; 拆分脚本，跳过触发器的语句体。
 	}
 	options = append(fixedOptions, options...) // 用户自定义选项放后面，以覆盖默认。
 
@@ -47,12 +56,27 @@ func NewSqliteDbClient(dsn string, options ...sqlmer.DbClientOption) (*SqliteDbC
 	return &SqliteDbClient{absDbClient}, nil
 }
 
+// isRetryableError 用于判断错误是否可以通过重试事务解决：
+//   - SQLITE_BUSY ：数据库被其它连接锁定（包含 SQLITE_BUSY_SNAPSHOT 等扩展错误码）；
+//   - SQLITE_LOCKED ：同一连接内的表锁冲突。
+func isRetryableError(err error) bool {
+	return errors.Is(err, sqlite3.BUSY) || errors.Is(err, sqlite3.LOCKED)
+}
+
+// splitScript 用于按 ; 拆分脚本， CREATE TRIGGER 语句的 BEGIN ... END 中的 ; 不拆分。
+func splitScript(script string) []string {
+	return sqlscript.Split(script, sqlscript.Sqlite)
+}
+
 // bindArgs 用于对 SQL 语句和参数进行预处理。
 // 第一个参数如果是 map，且仅且只有一个参数的情况下，做命名参数处理，其余情况做位置参数处理。
 func bindArgs(sqlText string, args ...any) (string, []any, error) {
-	return named2qm.BindQuestionMarkArgs(sqlText, args...)
+	return defaultBinder.BindQuestionMarkArgs(sqlText, args...)
 }
 
+// defaultBinder 是按 SQLite 方言识别字符串、标识符及注释的默认绑定器。
+var defaultBinder, _ = named2qm.NewQuestionMarkSqlBinder(named2qm.DefaultCacheCapacity, sqltoken.Sqlite)
+
 // getScanTypeFn 根据驱动配置返回一个可以正确获取 Scan 类型的函数。
 func getScanTypeFn() sqlen.GetScanTypeFunc {
 	return func(columnType *sql.ColumnType) reflect.Type {
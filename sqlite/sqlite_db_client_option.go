@@ -3,16 +3,20 @@ package sqlite
 import (
 	"github.com/bunnier/sqlmer"
 	"github.com/bunnier/sqlmer/internal/named2qm"
+	"github.com/bunnier/sqlmer/internal/sqltoken"
 )
 
 // WithSqlParserCacheCapacity 用于为 SQLite 配置命名参数 SQL 解析缓存容量。
 func WithSqlParserCacheCapacity(cacheCapacity int) sqlmer.DbClientOption {
 	return func(config *sqlmer.DbClientConfig) error {
-		binder, err := named2qm.NewQuestionMarkSqlBinder(cacheCapacity)
+		binder, err := named2qm.NewQuestionMarkSqlBinder(cacheCapacity, sqltoken.Sqlite)
 		if err != nil {
 			return err
 		}
 
-		return sqlmer.WithBindArgsFunc(binder.BindQuestionMarkArgs)(config)
+		if err := sqlmer.WithBindArgsFunc(binder.BindQuestionMarkArgs)(config); err != nil {
+			return err
+		}
+		return sqlmer.WithParseParamNamesFunc(binder.ParseParamNames)(config)
 	}
 }
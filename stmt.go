@@ -0,0 +1,339 @@
+package sqlmer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bunnier/sqlmer/sqlen"
+)
+
+var _ StmtPreparer = (*AbstractDbClient)(nil)
+var _ StmtPreparer = (*DbClientEx)(nil)
+
+// preparer 是 sql.DB 和 sql.Tx 上预编译语句的公共方法。
+type preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// Stmt 是支持命名参数的预编译语句，由 StmtPreparer.Prepare 创建，可以并发使用。
+// 参数的用法与 DbClient 的方法一致，支持 map / struct / 索引参数及其合并。
+//
+// 语句在创建时解析一次参数并预编译，之后的执行按缓存的参数名顺序直接取值绑定，无需再解析语句；
+// IN 子句中切片参数的长度会影响最终的 SQL ，有切片参数时会完整地绑定参数，
+// 若绑定得到的 SQL 与当前预编译语句不同（如切片参数的长度变化），会关闭原语句并自动重新预编译。
+// 在事务中创建的 Stmt ，其生命周期不超过事务本身。
+type Stmt struct {
+	client   *AbstractDbClient
+	rawSql   string
+	names    []string       // 语句中用到的命名参数名称（已去重），与 template 中的参数序号对应。
+	template *boundTemplate // 以参数序号代替参数值绑定得到的结果。
+
+	mu       sync.RWMutex
+	fixedSql string             // 当前预编译语句对应的 SQL 。
+	stmt     *sqlen.StmtEnhance // 当前预编译语句。
+	closed   bool
+}
+
+// Prepare 用于创建一个支持命名参数的预编译语句，使用完毕后需要调用 Stmt.Close 释放。
+// 创建时即解析参数并预编译语句，语句有误时直接返回错误。
+// params:
+//
+//	@ctx context，用于预编译语句。
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//
+// returns:
+//
+//	@stmt 预编译语句。
+//	@err 创建时遇到的错误。
+func (client *AbstractDbClient) Prepare(ctx context.Context, sqlText string) (*Stmt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := client.Exer.(preparer); !ok {
+		return nil, fmt.Errorf("dbClient: %T does not support prepared statements", client.Exer)
+	}
+
+	// 以参数序号代替参数值绑定一次，得到的 SQL 和参数顺序供之后的执行直接使用。
+	names := make([]string, 0, 10)
+	standIns := make(map[string]any, 10)
+	for _, name := range client.config.parseParamNamesFunc(sqlText) {
+		if _, ok := standIns[name]; !ok {
+			standIns[name] = len(names)
+			names = append(names, name)
+		}
+	}
+
+	var template *boundTemplate
+	var err error
+	if len(names) == 0 {
+		template, err = newBoundTemplate(client.config.driverBindArgsFunc, sqlText, 0)
+	} else {
+		template, err = newBoundTemplate(client.config.driverBindArgsFunc, sqlText, len(names), standIns)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &Stmt{client: client, rawSql: sqlText, names: names, template: template}
+	if err := stmt.prepare(ctx, template.fixedSql, nil); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// Prepare 用于创建一个支持命名参数的预编译语句，使用完毕后需要调用 Stmt.Close 释放。
+// 原始的 DbClient 需要实现 StmtPreparer 接口，否则返回错误。
+func (c *DbClientEx) Prepare(ctx context.Context, sqlText string) (*Stmt, error) {
+	if preparer, ok := c.DbClient.(StmtPreparer); ok {
+		return preparer.Prepare(ctx, sqlText)
+	}
+	return nil, fmt.Errorf("dbClient: %T does not support prepared statements", c.DbClient)
+}
+
+// Close 用于关闭预编译语句，已经返回的游标不受影响。重复调用是安全的。
+func (s *Stmt) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	if s.stmt == nil {
+		return nil
+	}
+	return s.stmt.Close()
+}
+
+// run 用于绑定参数，取得（必要时重新预编译）对应的预编译语句，并在读锁的保护下调用 fn 。
+func (s *Stmt) run(ctx context.Context, args []any, fn func(stmt *sqlen.StmtEnhance, fixedSql string, fixedArgs []any) error) error {
+	fixedSql, fixedArgs, err := s.bind(args)
+	if err != nil {
+		return err
+	}
+
+	for {
+		s.mu.RLock()
+		if s.closed {
+			s.mu.RUnlock()
+			return getSqlError(ErrStmtClosed, s.rawSql, args)
+		}
+
+		if s.stmt != nil && s.fixedSql == fixedSql {
+			defer s.mu.RUnlock()
+			return fn(s.stmt, fixedSql, fixedArgs)
+		}
+		s.mu.RUnlock()
+
+		if err := s.prepare(ctx, fixedSql, fixedArgs); err != nil {
+			return err
+		}
+	}
+}
+
+// bind 用于绑定参数：各参数均不是切片时，按缓存的参数名取值，直接由 template 得到绑定结果；
+// 否则（如 IN 子句的切片参数、语句中直接使用驱动的占位符）完整地绑定参数。
+func (s *Stmt) bind(args []any) (string, []any, error) {
+	if len(args) == 0 && len(s.names) == 0 {
+		return s.template.fixedSql, nil, nil
+	}
+
+	if values, ok := s.values(args); ok {
+		return s.template.fixedSql, s.template.bind(values), nil
+	}
+	return s.client.config.bindArgsFunc(s.rawSql, args...)
+}
+
+// values 用于按缓存的参数名顺序取得各参数的值，有需要展开的切片参数或取值失败时 ok 为 false 。
+func (s *Stmt) values(args []any) (values []any, ok bool) {
+	if len(s.names) == 0 {
+		return nil, false
+	}
+
+	args, err := preHandleArgs(args...) // 进行 结构体/map/索引 等各种参数的合并处理。
+	if err != nil {
+		return nil, false
+	}
+
+	var mapArgs map[string]any
+	if len(args) == 1 {
+		mapArgs, _ = args[0].(map[string]any)
+	}
+
+	values = make([]any, len(s.names))
+	for i, name := range s.names {
+		var value any
+		if mapArgs != nil {
+			if value, ok = mapArgs[name]; !ok {
+				return nil, false
+			}
+		} else {
+			// 索引参数只能通过 @p1...@pn 引用。
+			index, err := strconv.Atoi(strings.TrimPrefix(name, "p"))
+			if !strings.HasPrefix(name, "p") || err != nil || index < 1 || index > len(args) {
+				return nil, false
+			}
+			value = args[index-1]
+		}
+
+		if isSliceArg(value) {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+// isSliceArg 用于判断参数是否为需要展开的切片类型，[]byte 是二进制数据，不需要展开。
+func isSliceArg(arg any) bool {
+	argValue := reflect.ValueOf(arg)
+	return (argValue.Kind() == reflect.Slice || argValue.Kind() == reflect.Array) &&
+		!argValue.Type().ConvertibleTo(reflect.TypeOf([]byte{}))
+}
+
+// prepare 用于将当前语句替换为 fixedSql 的预编译语句。
+func (s *Stmt) prepare(ctx context.Context, fixedSql string, fixedArgs []any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 其它协程可能已经完成了预编译。
+	if s.closed || s.stmt != nil && s.fixedSql == fixedSql {
+		return nil
+	}
+
+	sqlStmt, err := s.client.Exer.(preparer).PrepareContext(ctx, fixedSql)
+	if err != nil {
+		return getExecutingSqlError(err, s.rawSql, fixedSql, fixedArgs)
+	}
+
+	// 持有写锁时，没有正在使用旧语句的调用，可以安全关闭。
+	if s.stmt != nil {
+		s.stmt.Close()
+	}
+
+	s.fixedSql = fixedSql
+	s.stmt = sqlen.NewStmtEnhance(sqlStmt, s.client.Db)
+	return nil
+}
+
+// Execute 用于执行非查询语句，并返回所影响的行数。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.Execute 。
+func (s *Stmt) Execute(args ...any) (int64, error) {
+	ctx, cancelFunc := s.client.getExecTimeoutContext()
+	defer cancelFunc()
+	return s.ExecuteContext(ctx, args...)
+}
+
+// ExecuteContext 用于执行非查询语句，并返回所影响的行数。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.ExecuteContext 。
+func (s *Stmt) ExecuteContext(ctx context.Context, args ...any) (int64, error) {
+	var result sql.Result
+	err := s.run(ctx, args, func(stmt *sqlen.StmtEnhance, fixedSql string, fixedArgs []any) error {
+		var err error
+		if result, err = stmt.ExecContext(ctx, fixedArgs...); err != nil {
+			return getExecutingSqlError(err, s.rawSql, fixedSql, fixedArgs)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if effectRows, err := result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrGetEffectedRows, err.Error())
+	} else {
+		return effectRows, nil
+	}
+}
+
+// Get 用于获取查询结果的第一行记录，没有命中行时返回 nil 。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.Get 。
+func (s *Stmt) Get(args ...any) (map[string]any, error) {
+	ctx, cancelFunc := s.client.getExecTimeoutContext()
+	defer cancelFunc()
+	return s.GetContext(ctx, args...)
+}
+
+// GetContext 用于获取查询结果的第一行记录，没有命中行时返回 nil 。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.GetContext 。
+func (s *Stmt) GetContext(ctx context.Context, args ...any) (map[string]any, error) {
+	rows, err := s.RowsContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return nil, rows.Err()
+		}
+		return nil, rows.Close()
+	}
+
+	return rows.MapScan()
+}
+
+// SliceGet 用于获取查询结果的所有行。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.SliceGet 。
+func (s *Stmt) SliceGet(args ...any) ([]map[string]any, error) {
+	ctx, cancelFunc := s.client.getExecTimeoutContext()
+	defer cancelFunc()
+	return s.SliceGetContext(ctx, args...)
+}
+
+// SliceGetContext 用于获取查询结果的所有行。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.SliceGetContext 。
+func (s *Stmt) SliceGetContext(ctx context.Context, args ...any) ([]map[string]any, error) {
+	rows, err := s.RowsContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]map[string]any, 0, 5)
+	for rows.Next() {
+		if result, err := rows.MapScan(); err != nil {
+			return nil, err
+		} else {
+			results = append(results, result)
+		}
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return results, rows.Close()
+}
+
+// Rows 用于获取查询结果行的游标对象。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.Rows 。
+func (s *Stmt) Rows(args ...any) (*sqlen.EnhanceRows, error) {
+	ctx, _ := s.client.getExecTimeoutContext()
+	return s.RowsContext(ctx, args...)
+}
+
+// RowsContext 用于获取查询结果行的游标对象。
+// 可以通过 errors.Is 判断的特殊 err 同 DbClient.RowsContext 。
+func (s *Stmt) RowsContext(ctx context.Context, args ...any) (*sqlen.EnhanceRows, error) {
+	var rows *sqlen.EnhanceRows
+	err := s.run(ctx, args, func(stmt *sqlen.StmtEnhance, fixedSql string, fixedArgs []any) error {
+		var err error
+		if rows, err = stmt.EnhancedQueryContext(ctx, fixedArgs...); err != nil {
+			return getExecutingSqlError(err, s.rawSql, fixedSql, fixedArgs)
+		}
+		rows.SetErrWrapper(getSqlRowsErrWrapper(s.rawSql, fixedSql, fixedArgs))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
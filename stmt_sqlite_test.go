@@ -0,0 +1,210 @@
+package sqlmer_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/named2qm"
+)
+
+func TestStmt(t *testing.T) {
+	c := getSqliteClientExForTest(t)
+	ctx := context.Background()
+
+	t.Run("get", func(t *testing.T) {
+		stmt, err := c.Prepare(ctx, `SELECT id, varcharTest FROM go_TypeTest WHERE id=@id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		for _, id := range []int64{1, 2} {
+			row, err := stmt.Get(map[string]any{"id": id})
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if row["id"] != id {
+				t.Fatalf("Get() id = %v, want %v", row["id"], id)
+			}
+		}
+
+		row, err := stmt.Get(map[string]any{"id": -1})
+		if err != nil || row != nil {
+			t.Fatalf("Get() = %v, %v, want nil, nil", row, err)
+		}
+	})
+
+	t.Run("struct_and_index", func(t *testing.T) {
+		stmt, err := c.Prepare(ctx, `SELECT id FROM go_TypeTest WHERE id=@Id OR id=@p1 ORDER BY id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		rows, err := stmt.SliceGet(struct{ Id int }{1}, 3)
+		if err != nil {
+			t.Fatalf("SliceGet() error = %v", err)
+		}
+		if len(rows) != 2 || rows[0]["id"] != int64(1) || rows[1]["id"] != int64(3) {
+			t.Fatalf("SliceGet() = %v, want ids [1 3]", rows)
+		}
+	})
+
+	t.Run("in_slice_reprepare", func(t *testing.T) {
+		stmt, err := c.Prepare(ctx, `SELECT id FROM go_TypeTest WHERE id IN (@ids) ORDER BY id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		for _, ids := range [][]int{{1, 2}, {1, 2, 3}, {2}, {1, 2, 3}} {
+			rows, err := stmt.SliceGet(map[string]any{"ids": ids})
+			if err != nil {
+				t.Fatalf("SliceGet(%v) error = %v", ids, err)
+			}
+			if len(rows) != len(ids) {
+				t.Fatalf("SliceGet(%v) got %d rows, want %d", ids, len(rows), len(ids))
+			}
+		}
+	})
+
+	t.Run("bind_once", func(t *testing.T) {
+		var bindCount int
+		c := sqlmer.Extend(newSqliteDbClientForAbstractDbTest(t, sqlmer.WithBindArgsFunc(func(sqlText string, args ...any) (string, []any, error) {
+			bindCount++
+			return named2qm.BindQuestionMarkArgs(sqlText, args...)
+		})))
+
+		stmt, err := c.Prepare(ctx, `SELECT id FROM go_TypeTest WHERE id IN (@id, @other, @id) ORDER BY id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		for _, args := range []map[string]any{{"id": 1, "other": 2}, {"id": 3, "other": 1}} {
+			rows, err := stmt.SliceGet(args)
+			if err != nil {
+				t.Fatalf("SliceGet(%v) error = %v", args, err)
+			}
+			if len(rows) != 2 {
+				t.Fatalf("SliceGet(%v) got %d rows, want 2", args, len(rows))
+			}
+		}
+		if bindCount != 1 {
+			t.Fatalf("bindCount = %d, want 1 (only in Prepare)", bindCount)
+		}
+
+		rows, err := stmt.SliceGet(map[string]any{"id": 1, "other": []int{2, 3}})
+		if err != nil {
+			t.Fatalf("SliceGet() error = %v", err)
+		}
+		if len(rows) != 3 || bindCount != 2 {
+			t.Fatalf("SliceGet() got %d rows, bindCount = %d, want 3 rows, bindCount = 2", len(rows), bindCount)
+		}
+	})
+
+	t.Run("rows_and_execute", func(t *testing.T) {
+		tx := c.MustCreateTransaction()
+		defer tx.MustClose()
+
+		update, err := tx.Prepare(ctx, `UPDATE go_TypeTest SET varcharTest=@p1 WHERE id=@p2`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer update.Close()
+
+		for _, id := range []int{1, 2} {
+			effected, err := update.Execute("stmt", id)
+			if err != nil || effected != 1 {
+				t.Fatalf("Execute() = %v, %v, want 1, nil", effected, err)
+			}
+		}
+
+		query, err := tx.Prepare(ctx, `SELECT varcharTest FROM go_TypeTest WHERE varcharTest=@p1`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer query.Close()
+
+		rows, err := query.Rows("stmt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		count := 0
+		for rows.Next() {
+			count++
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Fatalf("Rows() got %d rows, want 2", count)
+		}
+
+		tx.MustRollback()
+		if v, _ := c.MustScalarString(`SELECT varcharTest FROM go_TypeTest WHERE id=1`); *v != "行1" {
+			t.Fatalf("expect rollback, got varcharTest=%v", *v)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		stmt, err := c.Prepare(ctx, `SELECT COUNT(1) FROM go_TypeTest WHERE id IN (@p1)`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+				ids := []int{1, 2, 3}[:n%3+1]
+				row, err := stmt.Get(ids)
+				if err == nil && row["COUNT(1)"] != int64(len(ids)) {
+					err = errors.New("unexpected count")
+				}
+				errs <- err
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		stmt, err := c.Prepare(ctx, `SELECT * FROM go_TypeTest WHERE id=@id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := stmt.Get(map[string]any{}); !errors.Is(err, sqlmer.ErrParseParamFailed) {
+			t.Fatalf("Get() error = %v, want ErrParseParamFailed", err)
+		}
+
+		if _, err := c.Prepare(ctx, `SELECT * FROM not_exists`); !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("Prepare() error = %v, want ErrExecutingSql", err)
+		}
+
+		if err := stmt.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.Close(); err != nil {
+			t.Fatalf("second Close() error = %v", err)
+		}
+		if _, err := stmt.Get(map[string]any{"id": 1}); !errors.Is(err, sqlmer.ErrStmtClosed) {
+			t.Fatalf("Get() error = %v, want ErrStmtClosed", err)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"expvar"
+	"strings"
+)
+
+var _ Registry = (*ExpvarRegistry)(nil)
+
+// ExpvarRegistry 是基于标准库 expvar 的 Registry ，所有指标发布在同一个 expvar.Map 下：
+//   - 无标签的计数器、仪表盘为 expvar.Float ，以指标名为键；
+//   - 有标签的指标为 expvar.Map ，以逗号连接的标签值为键；
+//   - 直方图只记录次数和总和，分别发布为 <name>_count 和 <name>_sum 两个计数器。
+type ExpvarRegistry struct {
+	root *expvar.Map
+}
+
+// NewExpvarRegistry 用于创建一个 ExpvarRegistry ，并以 name 发布到 expvar 中。
+// 与 expvar.Publish 一致，name 已被发布过时会 panic 。
+func NewExpvarRegistry(name string) *ExpvarRegistry {
+	return &ExpvarRegistry{root: expvar.NewMap(name)}
+}
+
+// Map 用于获取发布到 expvar 中的 expvar.Map 。
+func (r *ExpvarRegistry) Map() *expvar.Map {
+	return r.root
+}
+
+// Histogram 用于创建一个直方图指标，只记录观测的次数和总和。
+func (r *ExpvarRegistry) Histogram(name, help string, labelNames ...string) Histogram {
+	count := r.Counter(name+"_count", help, labelNames...)
+	sum := r.Counter(name+"_sum", help, labelNames...)
+	return HistogramFunc(func(value float64, labelValues ...string) {
+		count.Add(1, labelValues...)
+		sum.Add(value, labelValues...)
+	})
+}
+
+// Counter 用于创建一个计数器指标。
+func (r *ExpvarRegistry) Counter(name, help string, labelNames ...string) Counter {
+	if len(labelNames) == 0 {
+		value := new(expvar.Float)
+		r.root.Set(name, value)
+		return CounterFunc(func(delta float64, _ ...string) {
+			value.Add(delta)
+		})
+	}
+
+	values := new(expvar.Map).Init()
+	r.root.Set(name, values)
+	return CounterFunc(func(delta float64, labelValues ...string) {
+		values.AddFloat(expvarKey(labelValues), delta)
+	})
+}
+
+// Gauge 用于创建一个仪表盘指标。
+func (r *ExpvarRegistry) Gauge(name, help string, labelNames ...string) Gauge {
+	if len(labelNames) == 0 {
+		value := new(expvar.Float)
+		r.root.Set(name, value)
+		return GaugeFunc(func(v float64, _ ...string) {
+			value.Set(v)
+		})
+	}
+
+	values := new(expvar.Map).Init()
+	r.root.Set(name, values)
+	return GaugeFunc(func(v float64, labelValues ...string) {
+		value := new(expvar.Float)
+		value.Set(v)
+		values.Set(expvarKey(labelValues), value)
+	})
+}
+
+// expvarKey 用于获取标签值在 expvar.Map 中的键。
+func expvarKey(labelValues []string) string {
+	return strings.Join(labelValues, ",")
+}
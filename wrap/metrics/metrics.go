@@ -0,0 +1,220 @@
+// Package metrics 提供统计指标装饰器：通过 Collector.Extend 包裹 DbClient ，记录各方法的耗时、错误、影响和返回的行数，
+// 以及事务的结果；通过 Collector.CollectDBStats 记录连接池的统计信息。
+// 指标通过 Registry 接口创建，可以桥接到 Prometheus 、 expvar 等监控系统，而不需要在 sqlmer 中引入相关依赖。
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/wrap"
+)
+
+// 错误分类，即 operation_errors_total 指标的 class 标签的值，见 ErrorClass 。
+const (
+	ErrorClassTimeout           = "timeout"
+	ErrorClassCanceled          = "canceled"
+	ErrorClassExpectedSizeWrong = "expected_size_wrong"
+	ErrorClassParseParamFailed  = "parse_param_failed"
+	ErrorClassGetEffectedRows   = "get_effected_rows"
+	ErrorClassGetInsertId       = "get_insert_id"
+	ErrorClassExecutingSql      = "executing_sql"
+	ErrorClassClientClosed      = "client_closed"
+	ErrorClassStmtClosed        = "stmt_closed"
+	ErrorClassTransaction       = "transaction"
+	ErrorClassConnect           = "connect"
+	ErrorClassOther             = "other"
+)
+
+// 事务结果，即 transactions_total 指标的 outcome 标签的值。
+const (
+	TxOutcomeBegun          = "begun"
+	TxOutcomeBeginFailed    = "begin_failed"
+	TxOutcomeCommitted      = "committed"
+	TxOutcomeCommitFailed   = "commit_failed"
+	TxOutcomeRolledBack     = "rolled_back"
+	TxOutcomeRollbackFailed = "rollback_failed"
+)
+
+// DefaultNamespace 是指标名的默认前缀。
+const DefaultNamespace = "sqlmer"
+
+// classifiedErrors 是 ErrorClass 按顺序匹配的错误及其分类。
+var classifiedErrors = []struct {
+	err   error
+	class string
+}{
+	{context.DeadlineExceeded, ErrorClassTimeout},
+	{context.Canceled, ErrorClassCanceled},
+	{sqlmer.ErrExpectedSizeWrong, ErrorClassExpectedSizeWrong},
+	{sqlmer.ErrParseParamFailed, ErrorClassParseParamFailed},
+	{sqlmer.ErrGetEffectedRows, ErrorClassGetEffectedRows},
+	{sqlmer.ErrGetInsertId, ErrorClassGetInsertId},
+	{sqlmer.ErrExecutingSql, ErrorClassExecutingSql},
+	{sqlmer.ErrClientClosed, ErrorClassClientClosed},
+	{sqlmer.ErrStmtClosed, ErrorClassStmtClosed},
+	{sqlmer.ErrTran, ErrorClassTransaction},
+	{sqlmer.ErrConnect, ErrorClassConnect},
+}
+
+// ErrorClass 用于获取错误的分类，err 为 nil 时返回空字符串。
+// 超时和取消优先于其它分类，即执行语句时超时的错误分类为 ErrorClassTimeout ，而不是 ErrorClassExecutingSql 。
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, c := range classifiedErrors {
+		if errors.Is(err, c.err) {
+			return c.class
+		}
+	}
+	return ErrorClassOther
+}
+
+// Option 是 Collector 的可选配置。
+type Option func(c *Collector)
+
+// WithNamespace 用于设置指标名的前缀（默认为 DefaultNamespace ），为空时不加前缀。
+func WithNamespace(namespace string) Option {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// Collector 用于统计 DbClient 的指标，同一个 Collector 可以包裹多个 DbClient ，指标会合并统计。
+//
+// 创建的指标如下（指标名均带有 <namespace>_ 前缀）：
+//   - operation_duration_seconds{method} ：直方图，各方法（含事务的开始、提交、回滚和关闭）的耗时；
+//   - operation_errors_total{method, class} ：计数器，各方法返回的错误数， class 见 ErrorClass ；
+//   - rows_affected_total{method} ：计数器， Execute / SizedExecute 系列方法影响的行数；
+//   - rows_returned_total{method} ：计数器， Get / SliceGet / Scalar 系列方法返回的行数；
+//   - transactions_total{outcome} ：计数器，事务的开始、提交和回滚的结果， outcome 见 TxOutcomeBegun 等常量；
+//   - db_* ：仪表盘，连接池的统计信息，见 CollectDBStats 。
+//
+// 注意：事务未提交时由 Close 隐式进行的回滚不计入 transactions_total ，其数量为 begun 与 committed 、 rolled_back 之差；
+// 嵌套事务的开始、提交和回滚也不计入，只统计最外层的事务。
+type Collector struct {
+	namespace string
+
+	duration     Histogram
+	errors       Counter
+	rowsAffected Counter
+	rowsReturned Counter
+	transactions Counter
+
+	dbMaxOpenConnections Gauge
+	dbOpenConnections    Gauge
+	dbInUse              Gauge
+	dbIdle               Gauge
+	dbWaitCount          Gauge
+	dbWaitDuration       Gauge
+	dbMaxIdleClosed      Gauge
+	dbMaxIdleTimeClosed  Gauge
+	dbMaxLifetimeClosed  Gauge
+}
+
+// NewCollector 用于创建一个 Collector ，并在 registry 上创建所需的指标。
+func NewCollector(registry Registry, options ...Option) *Collector {
+	c := &Collector{namespace: DefaultNamespace}
+	for _, option := range options {
+		option(c)
+	}
+
+	c.duration = registry.Histogram(c.name("operation_duration_seconds"), "Duration of sqlmer operations in seconds.", "method")
+	c.errors = registry.Counter(c.name("operation_errors_total"), "Number of sqlmer operations that returned an error.", "method", "class")
+	c.rowsAffected = registry.Counter(c.name("rows_affected_total"), "Number of rows affected by sqlmer operations.", "method")
+	c.rowsReturned = registry.Counter(c.name("rows_returned_total"), "Number of rows returned by sqlmer operations.", "method")
+	c.transactions = registry.Counter(c.name("transactions_total"), "Number of transaction operations by outcome.", "outcome")
+
+	c.dbMaxOpenConnections = registry.Gauge(c.name("db_max_open_connections"), "Maximum number of open connections to the database.")
+	c.dbOpenConnections = registry.Gauge(c.name("db_open_connections"), "The number of established connections both in use and idle.")
+	c.dbInUse = registry.Gauge(c.name("db_in_use_connections"), "The number of connections currently in use.")
+	c.dbIdle = registry.Gauge(c.name("db_idle_connections"), "The number of idle connections.")
+	c.dbWaitCount = registry.Gauge(c.name("db_wait_count"), "The total number of connections waited for.")
+	c.dbWaitDuration = registry.Gauge(c.name("db_wait_duration_seconds"), "The total time blocked waiting for a new connection.")
+	c.dbMaxIdleClosed = registry.Gauge(c.name("db_max_idle_closed"), "The total number of connections closed due to SetMaxIdleConns.")
+	c.dbMaxIdleTimeClosed = registry.Gauge(c.name("db_max_idle_time_closed"), "The total number of connections closed due to SetConnMaxIdleTime.")
+	c.dbMaxLifetimeClosed = registry.Gauge(c.name("db_max_lifetime_closed"), "The total number of connections closed due to SetConnMaxLifetime.")
+
+	return c
+}
+
+// name 用于获取带前缀的指标名。
+func (c *Collector) name(name string) string {
+	if c.namespace == "" {
+		return name
+	}
+	return c.namespace + "_" + name
+}
+
+// Extend 用于包裹 DbClient ，统计其上（包括其上开启的事务中）所有方法的指标。
+func (c *Collector) Extend(raw sqlmer.DbClient) *wrap.WrappedDbClient {
+	return wrap.ExtendContext(raw, c.wrapFunc, wrap.WithTransactionCalls())
+}
+
+// wrapFunc 是统计指标的包裹函数。
+func (c *Collector) wrapFunc(ctx context.Context, info wrap.CallInfo) (context.Context, func(wrap.CallResult)) {
+	return ctx, func(result wrap.CallResult) {
+		c.duration.Observe(result.Duration.Seconds(), info.Method)
+		if result.Err != nil {
+			c.errors.Add(1, info.Method, ErrorClass(result.Err))
+		}
+		if result.RowsAffected > 0 {
+			c.rowsAffected.Add(float64(result.RowsAffected), info.Method)
+		}
+		if result.RowsReturned > 0 {
+			c.rowsReturned.Add(float64(result.RowsReturned), info.Method)
+		}
+		if outcome := txOutcome(info, result.Err); outcome != "" {
+			c.transactions.Add(1, outcome)
+		}
+	}
+}
+
+// txOutcome 用于获取事务操作的结果，非事务操作或嵌套事务（加入外层事务）上的操作，返回空字符串。
+func txOutcome(info wrap.CallInfo, err error) string {
+	if info.Nested {
+		return ""
+	}
+
+	switch info.Method {
+	case "CreateTransaction", "CreateTransactionContext":
+		if err != nil {
+			return TxOutcomeBeginFailed
+		}
+		return TxOutcomeBegun
+	case "Commit":
+		if err != nil {
+			return TxOutcomeCommitFailed
+		}
+		return TxOutcomeCommitted
+	case "Rollback":
+		if err != nil {
+			return TxOutcomeRollbackFailed
+		}
+		return TxOutcomeRolledBack
+	}
+	return ""
+}
+
+// CollectDBStats 用于将 DbClient 连接池的统计信息（ DbClient.Stats ）写入 db_* 指标。
+// 统计信息是即时值，需要定时调用，或在监控系统采集前调用，如：
+//
+//	go func() {
+//		for range time.Tick(10 * time.Second) {
+//			collector.CollectDBStats(dbClient)
+//		}
+//	}()
+func (c *Collector) CollectDBStats(dbClient sqlmer.DbClient) {
+	stats := dbClient.Stats()
+	c.dbMaxOpenConnections.Set(float64(stats.MaxOpenConnections))
+	c.dbOpenConnections.Set(float64(stats.OpenConnections))
+	c.dbInUse.Set(float64(stats.InUse))
+	c.dbIdle.Set(float64(stats.Idle))
+	c.dbWaitCount.Set(float64(stats.WaitCount))
+	c.dbWaitDuration.Set(stats.WaitDuration.Seconds())
+	c.dbMaxIdleClosed.Set(float64(stats.MaxIdleClosed))
+	c.dbMaxIdleTimeClosed.Set(float64(stats.MaxIdleTimeClosed))
+	c.dbMaxLifetimeClosed.Set(float64(stats.MaxLifetimeClosed))
+}
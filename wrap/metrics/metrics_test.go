@@ -0,0 +1,288 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/testenv"
+)
+
+// memoryRegistry 是用于测试的 Registry ，以“指标名{标签值}”为键记录值，直方图记录观测次数。
+type memoryRegistry struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func newMemoryRegistry() *memoryRegistry {
+	return &memoryRegistry{values: make(map[string]float64)}
+}
+
+func (r *memoryRegistry) key(name string, labelValues []string) string {
+	return name + "{" + strings.Join(labelValues, ",") + "}"
+}
+
+func (r *memoryRegistry) Histogram(name, help string, labelNames ...string) Histogram {
+	return HistogramFunc(func(value float64, labelValues ...string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.values[r.key(name, labelValues)]++
+	})
+}
+
+func (r *memoryRegistry) Counter(name, help string, labelNames ...string) Counter {
+	return CounterFunc(func(value float64, labelValues ...string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.values[r.key(name, labelValues)] += value
+	})
+}
+
+func (r *memoryRegistry) Gauge(name, help string, labelNames ...string) Gauge {
+	return GaugeFunc(func(value float64, labelValues ...string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.values[r.key(name, labelValues)] = value
+	})
+}
+
+func (r *memoryRegistry) get(key string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.values[key]
+}
+
+func newSqliteClient(t *testing.T, options ...sqlmer.DbClientOption) sqlmer.DbClient {
+	t.Helper()
+
+	dbClient, err := testenv.NewSqliteTempClient(t.TempDir(), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbClient.Close() })
+	return dbClient
+}
+
+func TestCollector_Extend(t *testing.T) {
+	registry := newMemoryRegistry()
+	collector := NewCollector(registry, WithNamespace("test"))
+	dbClient := collector.Extend(newSqliteClient(t))
+
+	if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest = 'x' WHERE id < 3"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbClient.Get("SELECT * FROM go_TypeTest WHERE id = 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbClient.Get("SELECT * FROM go_TypeTest WHERE id = 100"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbClient.SliceGet("SELECT * FROM go_TypeTest"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := dbClient.Scalar("SELECT COUNT(1) FROM go_TypeTest"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want float64
+	}{
+		{"test_operation_duration_seconds{Execute}", 1},
+		{"test_operation_duration_seconds{Get}", 2},
+		{"test_rows_affected_total{Execute}", 2},
+		{"test_rows_returned_total{Get}", 1},
+		{"test_rows_returned_total{SliceGet}", 4},
+		{"test_rows_returned_total{Scalar}", 1},
+	}
+	for _, tt := range tests {
+		if got := registry.get(tt.key); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCollector_Errors(t *testing.T) {
+	registry := newMemoryRegistry()
+	dbClient := NewCollector(registry).Extend(newSqliteClient(t))
+
+	if err := dbClient.SizedExecute(1, "UPDATE go_TypeTest SET varcharTest = 'x'"); err == nil {
+		t.Fatal("SizedExecute() error = nil")
+	}
+	if _, err := dbClient.Get("SELECT * FROM go_TypeTest WHERE id = @id", map[string]any{}); err == nil {
+		t.Fatal("Get() error = nil")
+	}
+	if _, err := dbClient.SliceGet("SELECT * FROM not_exists"); err == nil {
+		t.Fatal("SliceGet() error = nil")
+	}
+
+	tests := []string{
+		"sqlmer_operation_errors_total{SizedExecute,expected_size_wrong}",
+		"sqlmer_operation_errors_total{Get,parse_param_failed}",
+		"sqlmer_operation_errors_total{SliceGet,executing_sql}",
+	}
+	for _, key := range tests {
+		if got := registry.get(key); got != 1 {
+			t.Errorf("%s = %v, want 1", key, got)
+		}
+	}
+}
+
+func TestCollector_Transactions(t *testing.T) {
+	registry := newMemoryRegistry()
+	dbClient := NewCollector(registry).Extend(newSqliteClient(t))
+
+	tx, err := dbClient.CreateTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Execute("UPDATE go_TypeTest SET varcharTest = 'x' WHERE id = 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	tx.Close()
+
+	tx, err = dbClient.CreateTransactionContext(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(); err == nil {
+		t.Fatal("Rollback() error = nil, want error after the transaction is done")
+	}
+
+	tests := []struct {
+		key  string
+		want float64
+	}{
+		{"sqlmer_transactions_total{begun}", 2},
+		{"sqlmer_transactions_total{committed}", 1},
+		{"sqlmer_transactions_total{rolled_back}", 1},
+		{"sqlmer_transactions_total{rollback_failed}", 1},
+		{"sqlmer_rows_affected_total{Execute}", 1},
+		{"sqlmer_operation_duration_seconds{Commit}", 1},
+		{"sqlmer_operation_duration_seconds{Close}", 1},
+	}
+	for _, tt := range tests {
+		if got := registry.get(tt.key); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCollector_NestedTransactions(t *testing.T) {
+	registry := newMemoryRegistry()
+	dbClient := NewCollector(registry).Extend(newSqliteClient(t, sqlmer.WithSavepoint(true)))
+
+	tx, err := dbClient.CreateTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Close()
+
+	// 嵌套事务的开始、提交和回滚不计入 transactions_total 。
+	for _, commit := range []bool{true, false} {
+		nested, err := tx.CreateTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if commit {
+			err = nested.Commit()
+		} else {
+			err = nested.Rollback()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		nested.Close()
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want float64
+	}{
+		{"sqlmer_transactions_total{begun}", 1},
+		{"sqlmer_transactions_total{committed}", 1},
+		{"sqlmer_transactions_total{rolled_back}", 0},
+		{"sqlmer_operation_duration_seconds{Commit}", 2},
+		{"sqlmer_operation_duration_seconds{Rollback}", 1},
+	}
+	for _, tt := range tests {
+		if got := registry.get(tt.key); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCollector_CollectDBStats(t *testing.T) {
+	registry := newMemoryRegistry()
+	collector := NewCollector(registry)
+	dbClient := newSqliteClient(t, sqlmer.WithMaxOpenConns(3))
+
+	collector.CollectDBStats(dbClient)
+	if got := registry.get("sqlmer_db_max_open_connections{}"); got != 3 {
+		t.Errorf("db_max_open_connections = %v, want 3", got)
+	}
+	if got, want := registry.get("sqlmer_db_open_connections{}"), float64(dbClient.Stats().OpenConnections); got != want {
+		t.Errorf("db_open_connections = %v, want %v", got, want)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("unknown"), ErrorClassOther},
+		{fmt.Errorf("%w: wrapped", sqlmer.ErrExpectedSizeWrong), ErrorClassExpectedSizeWrong},
+		{fmt.Errorf("%w: wrapped", sqlmer.ErrClientClosed), ErrorClassClientClosed},
+		{fmt.Errorf("%w: %w", sqlmer.ErrExecutingSql, context.DeadlineExceeded), ErrorClassTimeout},
+		{context.Canceled, ErrorClassCanceled},
+	}
+	for _, tt := range tests {
+		if got := ErrorClass(tt.err); got != tt.want {
+			t.Errorf("ErrorClass(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestExpvarRegistry(t *testing.T) {
+	registry := NewExpvarRegistry("sqlmer_metrics_test")
+	collector := NewCollector(registry)
+	dbClient := collector.Extend(newSqliteClient(t))
+
+	if _, err := dbClient.SliceGet("SELECT * FROM go_TypeTest"); err != nil {
+		t.Fatal(err)
+	}
+	collector.CollectDBStats(dbClient)
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(registry.Map().String()), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	count := got["sqlmer_operation_duration_seconds_count"].(map[string]any)
+	if count["SliceGet"] != float64(1) {
+		t.Errorf("operation_duration_seconds_count = %v, want SliceGet=1", count)
+	}
+	returned := got["sqlmer_rows_returned_total"].(map[string]any)
+	if returned["SliceGet"] != float64(4) {
+		t.Errorf("rows_returned_total = %v, want SliceGet=4", returned)
+	}
+	if _, ok := got["sqlmer_db_open_connections"].(float64); !ok {
+		t.Errorf("db_open_connections = %v, want a number", got["sqlmer_db_open_connections"])
+	}
+}
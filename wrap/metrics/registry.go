@@ -0,0 +1,68 @@
+package metrics
+
+// Registry 用于创建指标，是统计指标与具体监控系统之间的桥梁。
+// 同一个 Registry 上，每个指标名只会被创建一次；标签值的顺序与创建时的 labelNames 一致。
+//
+// 桥接 Prometheus 时，可分别用 HistogramVec 、 CounterVec 、 GaugeVec 实现，如：
+//
+//	func (r promRegistry) Counter(name, help string, labelNames ...string) metrics.Counter {
+//		vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames)
+//		r.MustRegister(vec)
+//		return metrics.CounterFunc(func(value float64, labelValues ...string) {
+//			vec.WithLabelValues(labelValues...).Add(value)
+//		})
+//	}
+//
+// 桥接 expvar 时，可直接使用 NewExpvarRegistry 。
+type Registry interface {
+	// Histogram 用于创建一个直方图指标，用于统计耗时等分布。
+	Histogram(name, help string, labelNames ...string) Histogram
+
+	// Counter 用于创建一个只增不减的计数器指标。
+	Counter(name, help string, labelNames ...string) Counter
+
+	// Gauge 用于创建一个可任意设置的仪表盘指标。
+	Gauge(name, help string, labelNames ...string) Gauge
+}
+
+// Histogram 是直方图指标。
+type Histogram interface {
+	// Observe 用于记录一个观测值。
+	Observe(value float64, labelValues ...string)
+}
+
+// Counter 是计数器指标。
+type Counter interface {
+	// Add 用于增加计数，value 不能为负数。
+	Add(value float64, labelValues ...string)
+}
+
+// Gauge 是仪表盘指标。
+type Gauge interface {
+	// Set 用于设置当前值。
+	Set(value float64, labelValues ...string)
+}
+
+// HistogramFunc 是函数形式的 Histogram 。
+type HistogramFunc func(value float64, labelValues ...string)
+
+// Observe 用于记录一个观测值。
+func (f HistogramFunc) Observe(value float64, labelValues ...string) {
+	f(value, labelValues...)
+}
+
+// CounterFunc 是函数形式的 Counter 。
+type CounterFunc func(value float64, labelValues ...string)
+
+// Add 用于增加计数。
+func (f CounterFunc) Add(value float64, labelValues ...string) {
+	f(value, labelValues...)
+}
+
+// GaugeFunc 是函数形式的 Gauge 。
+type GaugeFunc func(value float64, labelValues ...string)
+
+// Set 用于设置当前值。
+func (f GaugeFunc) Set(value float64, labelValues ...string) {
+	f(value, labelValues...)
+}
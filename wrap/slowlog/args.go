@@ -0,0 +1,97 @@
+package slowlog
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/cmstar/go-conv"
+)
+
+// argsValue 用于将参数转换为日志的属性值：命名参数（ map 、结构体、 sql.NamedArg ）使用参数名，索引参数使用 p1...pn ，
+// 同名的参数后者覆盖前者，与 DbClient 合并参数的规则一致。需要脱敏的参数输出 RedactedValue ，过长的字符串会被截断。
+func (c *config) argsValue(args []any) slog.Value {
+	attrs := make([]slog.Attr, 0, len(args))
+	indexParamCount := 0
+	for _, arg := range args {
+		if named, ok := arg.(sql.NamedArg); ok {
+			attrs = append(attrs, c.paramAttr(named.Name, named.Value))
+			continue
+		}
+
+		v := reflect.ValueOf(arg)
+		if v.Kind() == reflect.Ptr && !v.IsNil() && !isValuer(arg) {
+			v = v.Elem()
+		}
+
+		switch {
+		case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+			keys := v.MapKeys()
+			slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+			for _, key := range keys {
+				attrs = append(attrs, c.paramAttr(key.String(), v.MapIndex(key).Interface()))
+			}
+
+		case v.Kind() == reflect.Struct && !isValuer(arg) && v.Type() != reflect.TypeOf(time.Time{}):
+			// 与 DbClient 通过 conv.StructToMap 转换结构体参数的规则一致，嵌入的结构体的字段会被展开。
+			conv.NewFieldWalker(v.Type(), "").WalkValues(v, func(field conv.FieldInfo, value reflect.Value) bool {
+				attrs = append(attrs, c.paramAttr(field.Name, value.Interface()))
+				return true
+			})
+
+		default:
+			indexParamCount++
+			attrs = append(attrs, c.paramAttr("p"+strconv.Itoa(indexParamCount), arg))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// paramAttr 用于生成单个参数的属性，按配置脱敏及截断。
+func (c *config) paramAttr(name string, value any) slog.Attr {
+	if c.redactedParams[normalizeParamName(name)] {
+		return slog.String(name, RedactedValue)
+	}
+
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		return slog.Any(name, value)
+	}
+	return slog.String(name, c.truncate(s))
+}
+
+// truncate 用于截断过长的字符串，截断后注明原始长度，格式与 sqlmer.SqlContextError 中的参数一致。
+func (c *config) truncate(s string) string {
+	maxLength := c.maxValueLength
+	if maxLength <= 0 {
+		maxLength = sqlmer.MaxLengthErrorValue
+	}
+	if len(s) <= maxLength {
+		return s
+	}
+	return s[:maxLength] + "...(length=" + strconv.Itoa(len(s)) + ")"
+}
+
+// normalizeParamName 用于统一参数名的格式，以便不区分大小写地匹配，并忽略开头的 @ 。
+func normalizeParamName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(name, "@"))
+}
+
+func isValuer(arg any) bool {
+	_, ok := arg.(driver.Valuer)
+	return ok
+}
@@ -0,0 +1,140 @@
+// Package slowlog 基于 log/slog 提供开箱即用的慢日志：
+// 通过 Extend 包裹 DbClient ，记录耗时超过阈值的语句及事务操作；或通过 Observer 配合 sqlmer.WithObserver ，额外记录参数绑定后实际执行的语句。
+package slowlog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/wrap"
+)
+
+// RedactedValue 是被脱敏的参数在日志中的值。
+const RedactedValue = "***"
+
+// config 是慢日志的配置。
+type config struct {
+	logger    *slog.Logger
+	threshold time.Duration
+
+	level          slog.Level      // 慢语句的日志级别，执行出错的语句使用 slog.LevelError 。
+	redactedParams map[string]bool // 需要脱敏的参数名（小写）。
+	maxValueLength int             // 参数值的最大长度，小于等于 0 时使用 sqlmer.MaxLengthErrorValue 。
+}
+
+// Option 是慢日志的可选配置。
+type Option func(config *config)
+
+// WithLevel 用于设置慢语句的日志级别（默认为 slog.LevelWarn ），执行出错的语句总是使用 slog.LevelError 。
+func WithLevel(level slog.Level) Option {
+	return func(config *config) {
+		config.level = level
+	}
+}
+
+// WithRedactedParams 用于设置需要脱敏的参数名（不区分大小写），这些参数的值在日志中输出为 RedactedValue 。
+// 索引参数的名称为 p1...pn ；结构体参数使用字段名。
+func WithRedactedParams(names ...string) Option {
+	return func(config *config) {
+		for _, name := range names {
+			config.redactedParams[normalizeParamName(name)] = true
+		}
+	}
+}
+
+// WithMaxValueLength 用于设置日志中参数值（字符串）的最大长度，超过时截断（默认沿用 sqlmer.MaxLengthErrorValue ）。
+func WithMaxValueLength(length int) Option {
+	return func(config *config) {
+		config.maxValueLength = length
+	}
+}
+
+func newConfig(logger *slog.Logger, threshold time.Duration, options []Option) *config {
+	c := &config{
+		logger:         logger,
+		threshold:      threshold,
+		level:          slog.LevelWarn,
+		redactedParams: make(map[string]bool),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Extend 用于包裹 DbClient ，记录耗时不小于 threshold 的语句，以及开始、提交、回滚、关闭事务的操作。
+// params:
+//
+//	@raw 原始的 DbClient 实例。
+//	@logger 输出日志的 slog.Logger 。
+//	@threshold 慢日志的阈值，小于等于 0 时记录所有调用。
+//	@options 可选配置，见 WithLevel 、 WithRedactedParams 、 WithMaxValueLength 。
+//
+// 日志的属性包括： method 、 sql 、 args 、 duration 、 in_tx ，以及 rows_affected （仅非查询语句）和 error （仅出错时）。
+func Extend(raw sqlmer.DbClient, logger *slog.Logger, threshold time.Duration, options ...Option) *wrap.WrappedDbClient {
+	c := newConfig(logger, threshold, options)
+	return wrap.ExtendContext(raw, c.wrapFunc, wrap.WithTransactionCalls())
+}
+
+// wrapFunc 是慢日志的包裹函数。
+func (c *config) wrapFunc(ctx context.Context, info wrap.CallInfo) (context.Context, func(wrap.CallResult)) {
+	return ctx, func(result wrap.CallResult) {
+		if result.Duration < c.threshold {
+			return
+		}
+
+		attrs := make([]slog.Attr, 0, 7)
+		attrs = append(attrs, slog.String("method", info.Method))
+		msg := "slow transaction operation"
+		if info.SQL != "" {
+			msg = "slow sql"
+			attrs = append(attrs, slog.String("sql", info.SQL), slog.Any("args", c.argsValue(info.Args)))
+		}
+		attrs = append(attrs, slog.Duration("duration", result.Duration), slog.Bool("in_tx", info.InTx))
+		if result.RowsAffected >= 0 {
+			attrs = append(attrs, slog.Int64("rows_affected", result.RowsAffected))
+		}
+		c.log(ctx, msg, result.Err, attrs)
+	}
+}
+
+// Observer 返回一个记录慢语句的 sqlmer.ObserverFunc ，通过 sqlmer.WithObserver 注册，
+// 与 Extend 不同，额外记录了参数绑定后实际执行的 SQL 。参数见 Extend 。
+// 绑定后的参数只有位置，没有参数名，为了能按参数名脱敏， args 记录的是调用时传入的原始参数，而不是实际传给驱动的参数。
+//
+// 日志的属性包括： sql 、 fixed_sql 、 args 、 duration 、 in_tx ，以及 rows_affected （仅非查询语句）和 error （仅出错时）。
+func Observer(logger *slog.Logger, threshold time.Duration, options ...Option) sqlmer.ObserverFunc {
+	c := newConfig(logger, threshold, options)
+	return func(ctx context.Context, o sqlmer.SqlObservation) {
+		if o.Duration < c.threshold {
+			return
+		}
+
+		attrs := make([]slog.Attr, 0, 7)
+		attrs = append(attrs,
+			slog.String("sql", o.RawSQL),
+			slog.String("fixed_sql", o.FixedSQL),
+			slog.Any("args", c.argsValue(o.Args)),
+			slog.Duration("duration", o.Duration),
+			slog.Bool("in_tx", o.InTx),
+		)
+		if o.Result != nil {
+			if rowsAffected, err := o.Result.RowsAffected(); err == nil {
+				attrs = append(attrs, slog.Int64("rows_affected", rowsAffected))
+			}
+		}
+		c.log(ctx, "slow sql", o.Err, attrs)
+	}
+}
+
+// log 用于输出一条日志，出错时使用 slog.LevelError 并附加 error 属性。
+func (c *config) log(ctx context.Context, msg string, err error, attrs []slog.Attr) {
+	level := c.level
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
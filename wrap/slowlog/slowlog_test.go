@@ -0,0 +1,182 @@
+package slowlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/testenv"
+)
+
+func newSqliteClient(t *testing.T, options ...sqlmer.DbClientOption) sqlmer.DbClient {
+	t.Helper()
+
+	dbClient, err := testenv.NewSqliteTempClient(t.TempDir(), options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbClient.Close() })
+	return dbClient
+}
+
+// newLogger 返回一个输出 JSON 的 logger ，以及读取已输出日志的函数。
+func newLogger() (*slog.Logger, func(t *testing.T) []map[string]any) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return logger, func(t *testing.T) []map[string]any {
+		t.Helper()
+
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var record map[string]any
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatal(err)
+			}
+			records = append(records, record)
+		}
+		buf.Reset()
+		return records
+	}
+}
+
+func TestExtend(t *testing.T) {
+	logger, records := newLogger()
+
+	t.Run("threshold", func(t *testing.T) {
+		dbClient := Extend(newSqliteClient(t), logger, time.Hour)
+		if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest = 'a' WHERE id = 1"); err != nil {
+			t.Fatal(err)
+		}
+		if got := records(t); len(got) != 0 {
+			t.Fatalf("got %d records, want none below the threshold", len(got))
+		}
+	})
+
+	t.Run("attrs", func(t *testing.T) {
+		dbClient := Extend(newSqliteClient(t), logger, 0,
+			WithLevel(slog.LevelInfo), WithRedactedParams("Password"), WithMaxValueLength(3))
+
+		args := map[string]any{"name": "bunnier", "password": "secret"}
+		if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest = @name, charTest = @password WHERE id = 1", args); err != nil {
+			t.Fatal(err)
+		}
+
+		got := records(t)
+		if len(got) != 1 {
+			t.Fatalf("got %d records, want 1", len(got))
+		}
+		record := got[0]
+		if record["level"] != "INFO" || record["msg"] != "slow sql" || record["method"] != "Execute" ||
+			record["sql"] != "UPDATE go_TypeTest SET varcharTest = @name, charTest = @password WHERE id = 1" ||
+			record["rows_affected"] != float64(1) || record["in_tx"] != false || record["duration"] == nil {
+			t.Fatalf("record = %v", record)
+		}
+		wantArgs := map[string]any{"name": "bun...(length=7)", "password": RedactedValue}
+		if gotArgs := record["args"].(map[string]any); gotArgs["name"] != wantArgs["name"] || gotArgs["password"] != wantArgs["password"] {
+			t.Fatalf("args = %v, want %v", gotArgs, wantArgs)
+		}
+	})
+
+	t.Run("embedded_struct_args", func(t *testing.T) {
+		dbClient := Extend(newSqliteClient(t), logger, 0, WithRedactedParams("password"))
+
+		type Credentials struct {
+			Password string
+		}
+		args := struct {
+			Name string
+			Credentials
+		}{"bunnier", Credentials{"secret"}}
+		if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest = @Name, charTest = @Password WHERE id = 1", args); err != nil {
+			t.Fatal(err)
+		}
+
+		record := records(t)[0]
+		if args := record["args"].(map[string]any); args["Name"] != "bunnier" || args["Password"] != RedactedValue || args["Credentials"] != nil {
+			t.Fatalf("args = %v", args)
+		}
+	})
+
+	t.Run("error_and_index_args", func(t *testing.T) {
+		dbClient := Extend(newSqliteClient(t), logger, 0, WithRedactedParams("@p2"))
+
+		if _, err := dbClient.Get("SELECT * FROM not_exists WHERE a=@p1 AND b=@p2", 1, "secret"); err == nil {
+			t.Fatal("Get() should fail")
+		}
+
+		record := records(t)[0]
+		if record["level"] != "ERROR" || record["error"] == nil || record["rows_affected"] != nil {
+			t.Fatalf("record = %v", record)
+		}
+		if args := record["args"].(map[string]any); args["p1"] != float64(1) || args["p2"] != RedactedValue {
+			t.Fatalf("args = %v", args)
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		dbClient := Extend(newSqliteClient(t), logger, 0)
+
+		tx, err := dbClient.CreateTransactionContext(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Execute("UPDATE go_TypeTest SET varcharTest = 'a' WHERE id = 1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		got := records(t)
+		wantMethods := []string{"CreateTransactionContext", "Execute", "Commit", "Close"}
+		if len(got) != len(wantMethods) {
+			t.Fatalf("got %d records, want %d", len(got), len(wantMethods))
+		}
+		for i, method := range wantMethods {
+			if got[i]["method"] != method {
+				t.Fatalf("record %d method = %v, want %v", i, got[i]["method"], method)
+			}
+		}
+		if got[0]["msg"] != "slow transaction operation" || got[0]["in_tx"] != false || got[2]["in_tx"] != true || got[2]["sql"] != nil {
+			t.Fatalf("transaction records = %v", got)
+		}
+	})
+}
+
+func TestObserver(t *testing.T) {
+	logger, records := newLogger()
+	dbClient := newSqliteClient(t, sqlmer.WithObserver(Observer(logger, 0, WithRedactedParams("password"))))
+
+	// IN 展开后，绑定后的参数位置发生了变化，脱敏依然按参数名进行。
+	args := map[string]any{"ids": []int{1, 2}, "name": "a", "password": "secret"}
+	if _, err := dbClient.Execute("UPDATE go_TypeTest SET varcharTest = @name, charTest = @password WHERE id IN (@ids)", args); err != nil {
+		t.Fatal(err)
+	}
+
+	got := records(t)
+	if len(got) != 1 {
+		t.Fatalf("got %d records, want 1", len(got))
+	}
+	record := got[0]
+	if record["fixed_sql"] != "UPDATE go_TypeTest SET varcharTest = ?, charTest = ? WHERE id IN (?,?)" || record["rows_affected"] != float64(2) {
+		t.Fatalf("record = %v", record)
+	}
+	if args := record["args"].(map[string]any); args["name"] != "a" || args["password"] != RedactedValue {
+		t.Fatalf("args = %v", args)
+	}
+	if strings.Contains(fmt.Sprint(record), "secret") {
+		t.Fatalf("record = %v, want the password redacted", record)
+	}
+}
@@ -2,6 +2,8 @@ package wrap
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/bunnier/sqlmer"
@@ -9,11 +11,19 @@ import (
 )
 
 var _ sqlmer.DbClient = (*WrappedDbClient)(nil)
+var _ sqlmer.RetryableErrorClassifier = (*WrappedDbClient)(nil)
+var _ sqlmer.TransactionRetryReporter = (*WrappedDbClient)(nil)
+var _ sqlmer.StmtPreparer = (*WrappedDbClient)(nil)
+var _ sqlmer.ScriptExecutor = (*WrappedDbClient)(nil)
+var _ sqlmer.StmtCacheStatsProvider = (*WrappedDbClient)(nil)
 
 // WrappedDbClient 将包包裹 DbClient 的所有 SQL 执行方法，以注入慢日志/统计指标等能力。
 type WrappedDbClient struct {
-	dbClient sqlmer.DbClient // 原始的 DbClient 实例。
-	wrapFunc WrapFunc        // 包裹函数。
+	dbClient  sqlmer.DbClient // 原始的 DbClient 实例。
+	wrapFunc  WrapContextFunc // 包裹函数。
+	retryFunc RetryFunc       // 事务重试时的回调函数。
+	inTx      bool            // 原始的 DbClient 是否是事务。
+	wrapTx    bool            // 是否包裹事务的开始、提交、回滚和关闭。
 }
 
 // WrapFunc 用于包裹 SQL 执行方法。
@@ -21,11 +31,75 @@ type WrappedDbClient struct {
 // 返回函数的 err 参数是 SQL 执行后返回的 error。
 type WrapFunc func(sql string, args []any) func(error)
 
+// CallInfo 是被包裹的方法调用的信息。
+type CallInfo struct {
+	Method string // 被调用的方法名，如 Execute 、 GetContext 。
+	SQL    string // 原始的 SQL 语句，即绑定参数前的 SQL 。
+	Args   []any  // 原始的参数。
+	InTx   bool   // 是否在事务中执行。
+
+	// 事务操作（开始、提交、回滚和关闭）是否作用于嵌套事务，即在事务中开始的事务，其余方法总为 false 。
+	Nested bool
+}
+
+// CallResult 是被包裹的方法调用的结果。
+type CallResult struct {
+	Err error // 方法返回的 error 。
+
+	// 语句影响的行数，仅 Execute / SizedExecute 系列的方法执行成功时有值，其余情况为 -1 。
+	RowsAffected int64
+
+	// 查询返回的行数，仅 Get / SliceGet / Scalar 系列的方法执行成功时有值，其余情况为 -1 。
+	RowsReturned int64
+
+	// 方法执行的耗时， Row / Rows 系列的方法只统计到返回游标对象为止。
+	Duration time.Duration
+}
+
+// WrapContextFunc 是可以访问 context 的包裹函数。
+// 函数本身在执行 SQL 语句前执行，返回的 context 会传给原始的 *Context 方法（不带 context 的方法中，
+// ctx 为 context.Background() ，返回的 context 被忽略），以便在其中附加链路追踪的 span 等信息；
+// 返回的函数在执行 SQL 语句后执行，参数为调用结果。
+type WrapContextFunc func(ctx context.Context, info CallInfo) (context.Context, func(CallResult))
+
+// AdaptWrapFunc 用于将 WrapFunc 转换为 WrapContextFunc 。
+func AdaptWrapFunc(wrapFunc WrapFunc) WrapContextFunc {
+	return func(ctx context.Context, info CallInfo) (context.Context, func(CallResult)) {
+		after := wrapFunc(info.SQL, info.Args)
+		return ctx, func(result CallResult) {
+			after(result.Err)
+		}
+	}
+}
+
+// RetryFunc 用于接收 sqlmer.DbClientEx.Transaction 重试事务的通知。
+// attempt 为失败的尝试次数（从 1 开始）， err 为导致失败的可重试错误。
+type RetryFunc func(attempt int, err error)
+
+// ExtendOption 是 Extend 的可选配置。
+type ExtendOption func(c *WrappedDbClient)
+
+// WithRetryFunc 用于为 WrappedDbClient 设置事务重试时的回调函数，以便记录日志或统计重试次数。
+func WithRetryFunc(retryFunc RetryFunc) ExtendOption {
+	return func(c *WrappedDbClient) {
+		c.retryFunc = retryFunc
+	}
+}
+
+// WithTransactionCalls 用于让包裹函数同样包裹事务的开始（ CreateTransaction / CreateTransactionContext ）、
+// 提交（ Commit ）、回滚（ Rollback ）和关闭（ Close ）操作，以便统计事务操作的耗时，此时 CallInfo.SQL 为空。
+func WithTransactionCalls() ExtendOption {
+	return func(c *WrappedDbClient) {
+		c.wrapTx = true
+	}
+}
+
 // Extend 加强 DbClient，在 DbClient 的数据库访问上，提供一层装饰器包裹，以注入慢日志/统计指标等能力。
 // params:
 //
 //	@raw 原始的 DbClient 实例。
 //	@execWrapFunc 包裹函数，在执行 SQL 语句前执行，返回一个函数，在执行 SQL 语句后执行。返回函数的 err 参数是 SQL 执行后返回的 error。
+//	@options 可选配置，见 WithRetryFunc 。
 //
 // returns:
 //
@@ -34,8 +108,89 @@ type WrapFunc func(sql string, args []any) func(error)
 // 注意：
 //   - 事务是内部语句独立包裹，而不是一整个事务包裹；
 //   - Rows 方法，在返回游标对象时包裹上下文即结束；
-func Extend(raw sqlmer.DbClient, execWrapFunc WrapFunc) *WrappedDbClient {
-	return &WrappedDbClient{raw, execWrapFunc}
+func Extend(raw sqlmer.DbClient, execWrapFunc WrapFunc, options ...ExtendOption) *WrappedDbClient {
+	return ExtendContext(raw, AdaptWrapFunc(execWrapFunc), options...)
+}
+
+// ExtendContext 与 Extend 一致，但使用可以访问 context 及调用详情的 WrapContextFunc 作为包裹函数，适用于链路追踪等场景。
+// params:
+//
+//	@raw 原始的 DbClient 实例。
+//	@wrapFunc 包裹函数，见 WrapContextFunc 。
+//	@options 可选配置，见 WithRetryFunc 。
+//
+// returns:
+//
+//	@wrappedDbClient 返回一个被包裹上注入逻辑的 DbClient 实例。
+func ExtendContext(raw sqlmer.DbClient, wrapFunc WrapContextFunc, options ...ExtendOption) *WrappedDbClient {
+	_, inTx := raw.(sqlmer.TransactionKeeper)
+	c := &WrappedDbClient{dbClient: raw, wrapFunc: wrapFunc, inTx: inTx}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// wrapCall 用于在调用原始方法前执行包裹函数，返回传给原始方法的 context ，以及在调用结束后执行的函数。
+func (c *WrappedDbClient) wrapCall(ctx context.Context, method string, sqlText string, args []any) (context.Context, func(err error, rowsAffected int64, rowsReturned int64)) {
+	start := time.Now()
+	wrappedCtx, after := c.wrapFunc(ctx, CallInfo{Method: method, SQL: sqlText, Args: args, InTx: c.inTx})
+	if wrappedCtx == nil {
+		wrappedCtx = ctx
+	}
+
+	return wrappedCtx, func(err error, rowsAffected int64, rowsReturned int64) {
+		if after != nil {
+			after(CallResult{Err: err, RowsAffected: rowsAffected, RowsReturned: rowsReturned, Duration: time.Since(start)})
+		}
+	}
+}
+
+// wrapTxCall 用于包裹事务操作，未开启 WithTransactionCalls 时不做任何处理。
+func (c *WrappedDbClient) wrapTxCall(ctx context.Context, method string, nested bool) (context.Context, func(err error)) {
+	if !c.wrapTx {
+		return ctx, func(error) {}
+	}
+
+	start := time.Now()
+	wrappedCtx, after := c.wrapFunc(ctx, CallInfo{Method: method, InTx: c.inTx, Nested: nested})
+	if wrappedCtx == nil {
+		wrappedCtx = ctx
+	}
+
+	return wrappedCtx, func(err error) {
+		if after != nil {
+			after(CallResult{Err: err, RowsAffected: -1, RowsReturned: -1, Duration: time.Since(start)})
+		}
+	}
+}
+
+// hitRowsReturned 用于获取 Get / Scalar 返回的行数，执行出错时为 -1 。
+func hitRowsReturned(hit bool, err error) int64 {
+	switch {
+	case err != nil:
+		return -1
+	case hit:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sliceRowsReturned 用于获取 SliceGet 返回的行数，执行出错时为 -1 。
+func sliceRowsReturned(n int, err error) int64 {
+	if err != nil {
+		return -1
+	}
+	return int64(n)
+}
+
+// sizedRowsAffected 用于获取 SizedExecute 影响的行数，执行成功时即为预期的行数。
+func sizedRowsAffected(expectedSize int64, err error) int64 {
+	if err != nil {
+		return -1
+	}
+	return expectedSize
 }
 
 // Dsn 用于获取当
@@ -54,22 +209,132 @@ func (c *WrappedDbClient) GetExecTimeout() time.Duration {
 	return c.dbClient.GetExecTimeout()
 }
 
+// PingContext 用于检查数据库连接是否可用，必要时会建立连接。
+func (c *WrappedDbClient) PingContext(ctx context.Context) error {
+	return c.dbClient.PingContext(ctx)
+}
+
+// Stats 用于获取连接池的统计信息。
+func (c *WrappedDbClient) Stats() sql.DBStats {
+	return c.dbClient.Stats()
+}
+
+// Close 用于关闭原始的 DbClient ，不再允许开始新的事务，并在进行中的事务完结后关闭连接池。
+func (c *WrappedDbClient) Close() error {
+	return c.dbClient.Close()
+}
+
 // CreateTransaction 用于开始一个事务。
 // returns:
 //
 //	@tran 返回一个实现了 TransactionKeeper（内嵌 DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
 //	@err 创建事务时遇到的错误。
 func (c *WrappedDbClient) CreateTransaction() (tran sqlmer.TransactionKeeper, err error) {
+	_, done := c.wrapTxCall(context.Background(), "CreateTransaction", c.inTx)
 	tran, err = c.dbClient.CreateTransaction()
+	done(err)
 	if err != nil {
 		return
 	}
 
-	// 将事务上的方法也包裹上包裹函数。。
-	tran = extendExecWrapTx(tran, c.wrapFunc)
+	// 将事务上的方法也包裹上包裹函数。
+	tran = c.extendTx(tran)
 	return
 }
 
+// CreateTransactionContext 用于开始一个事务。在事务中调用时，会加入当前事务，ctx 和 opts 沿用外层事务的设置。
+// params:
+//
+//	@ctx 事务的 context，在事务提交或回滚前一直有效，若 ctx 被取消，事务将被回滚。
+//	@opts 事务选项，用于指定隔离级别及是否只读，为 nil 时使用驱动的默认值。
+//
+// returns:
+//
+//	@tran 返回一个实现了 TransactionKeeper（内嵌 DbClient 接口） 接口的对象，在上面执行的语句会在同一个事务中执行。
+//	@err 创建事务时遇到的错误。
+func (c *WrappedDbClient) CreateTransactionContext(ctx context.Context, opts *sql.TxOptions) (tran sqlmer.TransactionKeeper, err error) {
+	wrappedCtx, done := c.wrapTxCall(ctx, "CreateTransactionContext", c.inTx)
+	tran, err = c.dbClient.CreateTransactionContext(wrappedCtx, opts)
+	done(err)
+	if err != nil {
+		return
+	}
+
+	// 将事务上的方法也包裹上包裹函数。
+	tran = c.extendTx(tran)
+	return
+}
+
+// IsRetryableError 用于判断给定的错误是否可以通过重试事务解决，判断逻辑由原始的 DbClient 提供。
+func (c *WrappedDbClient) IsRetryableError(err error) bool {
+	classifier, ok := c.dbClient.(sqlmer.RetryableErrorClassifier)
+	return ok && classifier.IsRetryableError(err)
+}
+
+// MaxParamCount 返回单条语句允许使用的最大参数个数，由原始的 DbClient 提供，返回 0 表示未知。
+func (c *WrappedDbClient) MaxParamCount() int {
+	if provider, ok := c.dbClient.(sqlmer.ParamLimitProvider); ok {
+		return provider.MaxParamCount()
+	}
+	return 0
+}
+
+// MaxInsertRows 返回单条 INSERT ... VALUES 语句允许插入的最大行数，由原始的 DbClient 提供，返回 0 表示不限制。
+func (c *WrappedDbClient) MaxInsertRows() int {
+	if provider, ok := c.dbClient.(sqlmer.ParamLimitProvider); ok {
+		return provider.MaxInsertRows()
+	}
+	return 0
+}
+
+// StmtCacheStats 返回语句缓存的统计信息，由原始的 DbClient 提供，不支持时返回零值。
+func (c *WrappedDbClient) StmtCacheStats() sqlmer.StmtCacheStats {
+	if provider, ok := c.dbClient.(sqlmer.StmtCacheStatsProvider); ok {
+		return provider.StmtCacheStats()
+	}
+	return sqlmer.StmtCacheStats{}
+}
+
+// Prepare 用于创建一个支持命名参数的预编译语句，由原始的 DbClient 提供。
+// 注意：在预编译语句上执行的语句不经过包裹函数。
+func (c *WrappedDbClient) Prepare(ctx context.Context, sqlText string) (*sqlmer.Stmt, error) {
+	if preparer, ok := c.dbClient.(sqlmer.StmtPreparer); ok {
+		return preparer.Prepare(ctx, sqlText)
+	}
+	return nil, fmt.Errorf("dbClient: %T does not support prepared statements", c.dbClient)
+}
+
+// ExecuteScript 用于执行包含多条语句的脚本，由原始的 DbClient 提供。
+// 整个脚本作为一次调用包裹， CallResult.RowsAffected 为所有执行成功的语句影响的行数之和。
+func (c *WrappedDbClient) ExecuteScript(ctx context.Context, script string, options ...sqlmer.ScriptOption) (results []sqlmer.ScriptResult, err error) {
+	executor, ok := c.dbClient.(sqlmer.ScriptExecutor)
+	if !ok {
+		return nil, fmt.Errorf("dbClient: %T does not support executing scripts", c.dbClient)
+	}
+
+	ctx, done := c.wrapCall(ctx, "ExecuteScript", script, nil)
+	defer func() {
+		var rowsAffected int64
+		for _, result := range results {
+			rowsAffected += max(result.RowsAffected, 0)
+		}
+		done(err, rowsAffected, -1)
+	}()
+	results, err = executor.ExecuteScript(ctx, script, options...)
+	return
+}
+
+// ReportTransactionRetry 用于报告一次失败的事务尝试，会调用 WithRetryFunc 设置的回调函数。
+func (c *WrappedDbClient) ReportTransactionRetry(attempt int, err error) {
+	if reporter, ok := c.dbClient.(sqlmer.TransactionRetryReporter); ok { // 多层包裹时，逐层报告。
+		reporter.ReportTransactionRetry(attempt, err)
+	}
+
+	if c.retryFunc != nil {
+		c.retryFunc(attempt, err)
+	}
+}
+
 // Execute 用于执行非查询SQL语句，并返回所影响的行数。
 // params:
 //
@@ -86,9 +351,9 @@ func (c *WrappedDbClient) CreateTransaction() (tran sqlmer.TransactionKeeper, er
 //   - sqlmer.ErrGetEffectedRows: 当执行成功，但驱动不支持获取影响行数时候，返回该类型错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) Execute(sqlText string, args ...any) (rowsEffected int64, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "Execute", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, rowsEffected, -1)
 	}()
 	rowsEffected, err = c.dbClient.Execute(sqlText, args...)
 	return
@@ -111,14 +376,63 @@ func (c *WrappedDbClient) Execute(sqlText string, args ...any) (rowsEffected int
 //   - sqlmer.ErrGetEffectedRows: 当执行成功，但驱动不支持获取影响行数时候，返回该类型错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) ExecuteContext(ctx context.Context, sqlText string, args ...any) (rowsEffected int64, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	ctx, done := c.wrapCall(ctx, "ExecuteContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, rowsEffected, -1)
 	}()
 	rowsEffected, err = c.dbClient.ExecuteContext(ctx, sqlText, args...)
 	return
 }
 
+// Insert 用于执行插入语句，并返回新插入行的自增 id 。
+// params:
+//
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//	@args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+//
+// returns:
+//
+//	@lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+//	@err 执行语句时遇到的错误。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+//   - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+//   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+func (c *WrappedDbClient) Insert(sqlText string, args ...any) (lastInsertId int64, err error) {
+	_, done := c.wrapCall(context.Background(), "Insert", sqlText, args)
+	defer func() { // 这个 defer 是为了获取到执行结果的 err。
+		done(err, -1, -1)
+	}()
+	lastInsertId, err = c.dbClient.Insert(sqlText, args...)
+	return
+}
+
+// InsertContext 用于执行插入语句，并返回新插入行的自增 id 。
+// params:
+//
+//	@ctx context。
+//	@sqlText SQL 语句，支持 @ 的命名参数占位及 @p1...@pn 这样的索引占位符。
+//	@args SQL 语句的参数，支持通过 map[string]any 提供命名参数值 或 通过变长参数提供索引参数值。
+//
+// returns:
+//
+//	@lastInsertId 新插入行的自增 id ，插入多行时，其含义由数据库决定。
+//	@err 执行语句时遇到的错误。
+//
+// 可以通过 errors.Is 判断的特殊 err：
+//   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
+//   - sqlmer.ErrGetInsertId: 当执行成功，但无法获取到自增 id 时候，返回该类型错误。
+//   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
+func (c *WrappedDbClient) InsertContext(ctx context.Context, sqlText string, args ...any) (lastInsertId int64, err error) {
+	ctx, done := c.wrapCall(ctx, "InsertContext", sqlText, args)
+	defer func() { // 这个 defer 是为了获取到执行结果的 err。
+		done(err, -1, -1)
+	}()
+	lastInsertId, err = c.dbClient.InsertContext(ctx, sqlText, args...)
+	return
+}
+
 // SizedExecute 用于执行非查询SQL语句，并断言所影响的行数。
 // params:
 //
@@ -136,9 +450,9 @@ func (c *WrappedDbClient) ExecuteContext(ctx context.Context, sqlText string, ar
 //   - sqlmer.ErrExpectedSizeWrong: 当没有影响到预期行数时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) SizedExecute(expectedSize int64, sqlText string, args ...any) (err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "SizedExecute", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, sizedRowsAffected(expectedSize, err), -1)
 	}()
 	err = c.dbClient.SizedExecute(expectedSize, sqlText, args...)
 	return
@@ -162,9 +476,9 @@ func (c *WrappedDbClient) SizedExecute(expectedSize int64, sqlText string, args
 //   - sqlmer.ErrExpectedSizeWrong: 当没有影响到预期行数时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) SizedExecuteContext(ctx context.Context, expectedSize int64, sqlText string, args ...any) (err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	ctx, done := c.wrapCall(ctx, "SizedExecuteContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, sizedRowsAffected(expectedSize, err), -1)
 	}()
 	err = c.dbClient.SizedExecuteContext(ctx, expectedSize, sqlText, args...)
 	return
@@ -185,9 +499,9 @@ func (c *WrappedDbClient) SizedExecuteContext(ctx context.Context, expectedSize
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) Exists(sqlText string, args ...any) (ok bool, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "Exists", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, -1)
 	}()
 	ok, err = c.dbClient.Exists(sqlText, args...)
 	return
@@ -209,9 +523,9 @@ func (c *WrappedDbClient) Exists(sqlText string, args ...any) (ok bool, err erro
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) ExistsContext(ctx context.Context, sqlText string, args ...any) (ok bool, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	ctx, done := c.wrapCall(ctx, "ExistsContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, -1)
 	}()
 	ok, err = c.dbClient.ExistsContext(ctx, sqlText, args...)
 	return
@@ -233,9 +547,9 @@ func (c *WrappedDbClient) ExistsContext(ctx context.Context, sqlText string, arg
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) Scalar(sqlText string, args ...any) (cell any, hit bool, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "Scalar", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, hitRowsReturned(hit, err))
 	}()
 	cell, hit, err = c.dbClient.Scalar(sqlText, args...)
 	return
@@ -258,9 +572,9 @@ func (c *WrappedDbClient) Scalar(sqlText string, args ...any) (cell any, hit boo
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) ScalarContext(ctx context.Context, sqlText string, args ...any) (cell any, hit bool, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	ctx, done := c.wrapCall(ctx, "ScalarContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, hitRowsReturned(hit, err))
 	}()
 	cell, hit, err = c.dbClient.ScalarContext(ctx, sqlText, args...)
 	return
@@ -281,9 +595,9 @@ func (c *WrappedDbClient) ScalarContext(ctx context.Context, sqlText string, arg
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) Get(sqlText string, args ...any) (mapRow map[string]any, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "Get", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, hitRowsReturned(mapRow != nil, err))
 	}()
 	mapRow, err = c.dbClient.Get(sqlText, args...)
 	return
@@ -305,9 +619,9 @@ func (c *WrappedDbClient) Get(sqlText string, args ...any) (mapRow map[string]an
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) GetContext(ctx context.Context, sqlText string, args ...any) (mapRow map[string]any, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	ctx, done := c.wrapCall(ctx, "GetContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, hitRowsReturned(mapRow != nil, err))
 	}()
 	mapRow, err = c.dbClient.GetContext(ctx, sqlText, args...)
 	return
@@ -328,9 +642,9 @@ func (c *WrappedDbClient) GetContext(ctx context.Context, sqlText string, args .
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) SliceGet(sqlText string, args ...any) (mapRows []map[string]any, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "SliceGet", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, sliceRowsReturned(len(mapRows), err))
 	}()
 	mapRows, err = c.dbClient.SliceGet(sqlText, args...)
 	return
@@ -352,9 +666,9 @@ func (c *WrappedDbClient) SliceGet(sqlText string, args ...any) (mapRows []map[s
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) SliceGetContext(ctx context.Context, sqlText string, args ...any) (mapRows []map[string]any, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	ctx, done := c.wrapCall(ctx, "SliceGetContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, sliceRowsReturned(len(mapRows), err))
 	}()
 	mapRows, err = c.dbClient.SliceGetContext(ctx, sqlText, args...)
 	return
@@ -375,9 +689,9 @@ func (c *WrappedDbClient) SliceGetContext(ctx context.Context, sqlText string, a
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) Row(sqlText string, args ...any) (row *sqlen.EnhanceRow, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "Row", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, -1)
 	}()
 	row, err = c.dbClient.Row(sqlText, args...)
 	return
@@ -398,12 +712,12 @@ func (c *WrappedDbClient) Row(sqlText string, args ...any) (row *sqlen.EnhanceRo
 // 可以通过 errors.Is 判断的特殊 err：
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
-func (c *WrappedDbClient) RowContext(context context.Context, sqlText string, args ...any) (row *sqlen.EnhanceRow, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+func (c *WrappedDbClient) RowContext(ctx context.Context, sqlText string, args ...any) (row *sqlen.EnhanceRow, err error) {
+	ctx, done := c.wrapCall(ctx, "RowContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, -1)
 	}()
-	row, err = c.dbClient.RowContext(context, sqlText, args...)
+	row, err = c.dbClient.RowContext(ctx, sqlText, args...)
 	return
 }
 
@@ -422,9 +736,9 @@ func (c *WrappedDbClient) RowContext(context context.Context, sqlText string, ar
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
 func (c *WrappedDbClient) Rows(sqlText string, args ...any) (rows *sqlen.EnhanceRows, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+	_, done := c.wrapCall(context.Background(), "Rows", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, -1)
 	}()
 	rows, err = c.dbClient.Rows(sqlText, args...)
 	return
@@ -445,11 +759,11 @@ func (c *WrappedDbClient) Rows(sqlText string, args ...any) (rows *sqlen.Enhance
 // 可以通过 errors.Is 判断的特殊 err：
 //   - sqlmer.ErrParseParamFailed: 当 SQL 语句中的参数解析失败时返回该类错误。
 //   - sqlmer.ErrExecutingSql: 当 SQL 语句执行时遇到错误，返回该类型错误。
-func (c *WrappedDbClient) RowsContext(context context.Context, sqlText string, args ...any) (rows *sqlen.EnhanceRows, err error) {
-	deferFunc := c.wrapFunc(sqlText, args)
+func (c *WrappedDbClient) RowsContext(ctx context.Context, sqlText string, args ...any) (rows *sqlen.EnhanceRows, err error) {
+	ctx, done := c.wrapCall(ctx, "RowsContext", sqlText, args)
 	defer func() { // 这个 defer 是为了获取到执行结果的 err。
-		deferFunc(err)
+		done(err, -1, -1)
 	}()
-	rows, err = c.dbClient.RowsContext(context, sqlText, args...)
+	rows, err = c.dbClient.RowsContext(ctx, sqlText, args...)
 	return
 }
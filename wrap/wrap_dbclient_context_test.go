@@ -0,0 +1,161 @@
+package wrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/internal/testenv"
+)
+
+type ctxKey struct{}
+
+func newWrapSqliteClient(t *testing.T) sqlmer.DbClient {
+	t.Helper()
+
+	dbClient, err := testenv.NewSqliteTempClient(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbClient.Close() })
+	return dbClient
+}
+
+func TestExtendContext(t *testing.T) {
+	var infos []CallInfo
+	var results []CallResult
+	var seenCtxValue []any
+
+	wrapFunc := func(ctx context.Context, info CallInfo) (context.Context, func(CallResult)) {
+		infos = append(infos, info)
+		return context.WithValue(ctx, ctxKey{}, info.Method), func(result CallResult) {
+			results = append(results, result)
+		}
+	}
+	reset := func() {
+		infos, results, seenCtxValue = nil, nil, nil
+	}
+
+	// 内层再包裹一次，用于检查外层包裹函数返回的 context 被传给了原始方法。
+	inner := ExtendContext(newWrapSqliteClient(t), func(ctx context.Context, info CallInfo) (context.Context, func(CallResult)) {
+		seenCtxValue = append(seenCtxValue, ctx.Value(ctxKey{}))
+		return ctx, nil
+	})
+	dbClient := ExtendContext(inner, wrapFunc)
+	ctx := context.Background()
+
+	t.Run("execute", func(t *testing.T) {
+		reset()
+		if _, err := dbClient.ExecuteContext(ctx, "UPDATE go_TypeTest SET varcharTest=@p1", "x"); err != nil {
+			t.Fatal(err)
+		}
+		want := CallInfo{Method: "ExecuteContext", SQL: "UPDATE go_TypeTest SET varcharTest=@p1", Args: []any{"x"}}
+		if len(infos) != 1 || infos[0].Method != want.Method || infos[0].SQL != want.SQL || infos[0].Args[0] != "x" || infos[0].InTx {
+			t.Fatalf("CallInfo = %+v, want %+v", infos, want)
+		}
+		if results[0].Err != nil || results[0].RowsAffected != 4 || results[0].Duration <= 0 {
+			t.Fatalf("CallResult = %+v, want 4 rows affected", results[0])
+		}
+		if len(seenCtxValue) != 1 || seenCtxValue[0] != "ExecuteContext" {
+			t.Fatalf("context passed to the raw method = %v, want ExecuteContext", seenCtxValue)
+		}
+	})
+
+	t.Run("execute_script", func(t *testing.T) {
+		reset()
+		script := "UPDATE go_TypeTest SET varcharTest='s' WHERE id=1;\nUPDATE go_TypeTest SET varcharTest='s';"
+		scriptResults, err := dbClient.ExecuteScript(ctx, script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scriptResults) != 2 {
+			t.Fatalf("got %d script results, want 2", len(scriptResults))
+		}
+		if len(infos) != 1 || infos[0].Method != "ExecuteScript" || infos[0].SQL != script {
+			t.Fatalf("CallInfo = %+v, want one ExecuteScript call", infos)
+		}
+		if results[0].Err != nil || results[0].RowsAffected != 5 {
+			t.Fatalf("CallResult = %+v, want 5 rows affected in total", results[0])
+		}
+	})
+
+	t.Run("query_and_error", func(t *testing.T) {
+		reset()
+		if _, err := dbClient.Get("SELECT * FROM go_TypeTest WHERE id=@p1", 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbClient.SizedExecute(1, "UPDATE go_TypeTest SET varcharTest='y' WHERE id=1"); err != nil {
+			t.Fatal(err)
+		}
+		_, err := dbClient.SliceGet("SELECT * FROM not_exists")
+		if !errors.Is(err, sqlmer.ErrExecutingSql) {
+			t.Fatalf("SliceGet() error = %v, want ErrExecutingSql", err)
+		}
+
+		wantMethods := []string{"Get", "SizedExecute", "SliceGet"}
+		wantRows := []int64{-1, 1, -1}
+		wantReturned := []int64{1, -1, -1}
+		for i, method := range wantMethods {
+			if infos[i].Method != method || results[i].RowsAffected != wantRows[i] || results[i].RowsReturned != wantReturned[i] {
+				t.Fatalf("call %d = %+v %+v, want %v with %d rows affected and %d rows returned", i, infos[i], results[i], method, wantRows[i], wantReturned[i])
+			}
+		}
+		if results[2].Err != err {
+			t.Fatalf("CallResult.Err = %v, want %v", results[2].Err, err)
+		}
+		// 不带 context 的方法，包裹函数返回的 context 不会传给原始方法。
+		if seenCtxValue[0] != nil {
+			t.Fatalf("context passed to the raw method = %v, want nil", seenCtxValue[0])
+		}
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		reset()
+		tx, err := dbClient.CreateTransactionContext(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Close()
+
+		if _, _, err := tx.ScalarContext(ctx, "SELECT varcharTest FROM go_TypeTest WHERE id=2"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sqlmer.Extend(tx).ExecuteScript(ctx, "UPDATE go_TypeTest SET varcharTest='t' WHERE id=2;"); err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 2 || infos[0].Method != "ScalarContext" || infos[1].Method != "ExecuteScript" || !infos[0].InTx || !infos[1].InTx {
+			t.Fatalf("CallInfo = %+v, want ScalarContext and ExecuteScript in transaction", infos)
+		}
+
+		stmt, err := sqlmer.Extend(tx).Prepare(ctx, "SELECT varcharTest FROM go_TypeTest WHERE id=@id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if row, err := stmt.Get(map[string]any{"id": 2}); err != nil || row["varcharTest"] != "t" {
+			t.Fatalf("Stmt.Get() = %v, %v, want varcharTest t", row, err)
+		}
+		if limit := tx.(sqlmer.ParamLimitProvider).MaxParamCount(); limit != 32766 {
+			t.Fatalf("MaxParamCount() = %d, want 32766", limit)
+		}
+	})
+
+	t.Run("adapt_wrap_func", func(t *testing.T) {
+		var sqls []string
+		var errs []error
+		dbClient := Extend(newWrapSqliteClient(t), func(sql string, args []any) func(error) {
+			sqls = append(sqls, sql)
+			return func(err error) { errs = append(errs, err) }
+		})
+
+		rows, err := dbClient.RowsContext(ctx, "SELECT * FROM go_TypeTest")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+		if len(sqls) != 1 || sqls[0] != "SELECT * FROM go_TypeTest" || len(errs) != 1 || errs[0] != nil {
+			t.Fatalf("WrapFunc got sqls %v and errs %v", sqls, errs)
+		}
+	})
+}
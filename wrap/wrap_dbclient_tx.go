@@ -1,35 +1,81 @@
 package wrap
 
 import (
+	"context"
+
 	"github.com/bunnier/sqlmer"
 )
 
 var _ sqlmer.TransactionKeeper = (*WrappedTransactionKeeper)(nil)
+var _ sqlmer.ScriptExecutor = (*WrappedTransactionKeeper)(nil)
+var _ sqlmer.StmtPreparer = (*WrappedTransactionKeeper)(nil)
+var _ sqlmer.ParamLimitProvider = (*WrappedTransactionKeeper)(nil)
+var _ sqlmer.RetryableErrorClassifier = (*WrappedTransactionKeeper)(nil)
 
 // WrappedTransactionKeeper 将包包裹 TransactionKeeper 的所有执行方法，以提供慢日志等能力。
 type WrappedTransactionKeeper struct {
 	sqlmer.DbClient                            // 内嵌 DbClient 实例。
 	transactionKeeper sqlmer.TransactionKeeper // 原始的 TransactionKeeper 实例。
+	wrapped           *WrappedDbClient         // 即内嵌的 DbClient ，用于包裹事务操作。
+	nested            bool                     // 是否是在事务中开始的嵌套事务。
 }
 
-func extendExecWrapTx(tx sqlmer.TransactionKeeper, wrapFunc WrapFunc) *WrappedTransactionKeeper {
+// extendTx 用于以当前实例的配置包裹事务。
+func (c *WrappedDbClient) extendTx(tx sqlmer.TransactionKeeper) *WrappedTransactionKeeper {
+	wrapped := &WrappedDbClient{dbClient: tx, wrapFunc: c.wrapFunc, inTx: true, wrapTx: c.wrapTx}
 	return &WrappedTransactionKeeper{
-		DbClient:          Extend(tx, wrapFunc),
+		DbClient:          wrapped,
 		transactionKeeper: tx,
+		wrapped:           wrapped,
+		nested:            c.inTx,
 	}
 }
 
 // Commit 用于提交事务。
 func (t *WrappedTransactionKeeper) Commit() error {
-	return t.transactionKeeper.Commit()
+	_, done := t.wrapped.wrapTxCall(context.Background(), "Commit", t.nested)
+	err := t.transactionKeeper.Commit()
+	done(err)
+	return err
 }
 
 // Rollback 用于回滚事务。
 func (t *WrappedTransactionKeeper) Rollback() error {
-	return t.transactionKeeper.Rollback()
+	_, done := t.wrapped.wrapTxCall(context.Background(), "Rollback", t.nested)
+	err := t.transactionKeeper.Rollback()
+	done(err)
+	return err
 }
 
 // Close 用于优雅关闭事务，创建事务后可 defer 执行本方法。
 func (t *WrappedTransactionKeeper) Close() error {
-	return t.transactionKeeper.Close()
+	_, done := t.wrapped.wrapTxCall(context.Background(), "Close", t.nested)
+	err := t.transactionKeeper.Close()
+	done(err)
+	return err
+}
+
+// ExecuteScript 用于在事务中执行包含多条语句的脚本，见 WrappedDbClient.ExecuteScript 。
+func (t *WrappedTransactionKeeper) ExecuteScript(ctx context.Context, script string, options ...sqlmer.ScriptOption) ([]sqlmer.ScriptResult, error) {
+	return t.wrapped.ExecuteScript(ctx, script, options...)
+}
+
+// Prepare 用于在事务中创建预编译语句，见 WrappedDbClient.Prepare 。
+func (t *WrappedTransactionKeeper) Prepare(ctx context.Context, sqlText string) (*sqlmer.Stmt, error) {
+	return t.wrapped.Prepare(ctx, sqlText)
+}
+
+// MaxParamCount 返回单条语句允许使用的最大参数个数，见 WrappedDbClient.MaxParamCount 。
+func (t *WrappedTransactionKeeper) MaxParamCount() int {
+	return t.wrapped.MaxParamCount()
+}
+
+// MaxInsertRows 返回单条 INSERT ... VALUES 语句允许插入的最大行数，见 WrappedDbClient.MaxInsertRows 。
+func (t *WrappedTransactionKeeper) MaxInsertRows() int {
+	return t.wrapped.MaxInsertRows()
+}
+
+// IsRetryableError 用于判断错误是否可以通过重试事务解决，见 WrappedDbClient.IsRetryableError 。
+func (t *WrappedTransactionKeeper) IsRetryableError(err error) bool {
+	return t.wrapped.IsRetryableError(err)
 }
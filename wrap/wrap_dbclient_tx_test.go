@@ -1,11 +1,14 @@
 package wrap
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
 
 	"errors"
 
 	"github.com/bunnier/sqlmer"
+	"github.com/bunnier/sqlmer/sqlite"
 )
 
 func Test_MysqlTransaction(t *testing.T) {
@@ -31,6 +34,43 @@ func Test_MysqlTransaction(t *testing.T) {
 	}
 }
 
+func Test_TransactionRetry(t *testing.T) {
+	errRetryable := errors.New("retryable error")
+	rawClient, err := sqlite.NewSqliteDbClient(filepath.Join(t.TempDir(), "retry.db"),
+		sqlmer.WithRetryableErrorFunc(func(err error) bool {
+			return errors.Is(err, errRetryable)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reportedAttempts []int
+	dbClient := sqlmer.Extend(Extend(rawClient, func(sql string, args []any) func(error) {
+		return func(err error) {}
+	}, WithRetryFunc(func(attempt int, err error) {
+		if !errors.Is(err, errRetryable) {
+			t.Errorf("RetryFunc() err = %v, want %v", err, errRetryable)
+		}
+		reportedAttempts = append(reportedAttempts, attempt)
+	})))
+
+	attempts := 0
+	err = dbClient.Transaction(context.Background(), func(tx *sqlmer.TransactionKeeperEx) error {
+		attempts++
+		if attempts < 3 {
+			return errRetryable
+		}
+		return nil
+	}, sqlmer.WithRetry(5, nil))
+	if err != nil {
+		t.Fatalf("Transaction() error = %v, wantErr nil", err)
+	}
+
+	if len(reportedAttempts) != 2 || reportedAttempts[0] != 1 || reportedAttempts[1] != 2 {
+		t.Errorf("RetryFunc() attempts = %v, want [1 2]", reportedAttempts)
+	}
+}
+
 func TransactionFuncTest(t *testing.T, dbClient sqlmer.DbClient) {
 	// 测试事务回滚。
 	t.Run("rollback", func(t *testing.T) {